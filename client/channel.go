@@ -0,0 +1,168 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"github.com/appcrash/media/server/rpc"
+	"sync/atomic"
+	"time"
+)
+
+// keepaliveTimeoutFactor is how many keep-alive intervals without reply make system channel reconnect
+const keepaliveTimeoutFactor = 3
+
+var ErrNotConnected = errors.New("system channel is not connected")
+
+// Handle adds handler of system events with the command, handlers are called in order of adding. events with
+// session id are passed to the session's handler first. USER_EVENT requiring ack is acked after all handlers return,
+// it may be handled more than once if the ack is lost.
+func (c *Client) Handle(cmd rpc.SystemCommand, h EventHandler) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.handlers[cmd] = append(c.handlers[cmd], h)
+}
+
+// WaitConnected blocks until system channel is connected or ctx is done
+func (c *Client) WaitConnected(ctx context.Context) error {
+	c.mutex.Lock()
+	connectedC := c.connectedC
+	c.mutex.Unlock()
+	select {
+	case <-connectedC:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Send sends event to media server by system channel, instance id is filled if empty
+func (c *Client) Send(evt *rpc.SystemEvent) error {
+	if evt.InstanceId == "" {
+		evt.InstanceId = c.instanceId
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.stream == nil {
+		return ErrNotConnected
+	}
+	// stream.Send is not goroutine safe
+	return c.stream.Send(evt)
+}
+
+// QueryCapacity asks media server to send CAPACITY_REPORT by system channel, handle it by Handle
+func (c *Client) QueryCapacity() error {
+	return c.Send(&rpc.SystemEvent{Cmd: rpc.SystemCommand_CAPACITY_QUERY})
+}
+
+func (c *Client) dispatch(evt *rpc.SystemEvent) {
+	c.mutex.Lock()
+	sh := c.sessionHandlers[evt.SessionId]
+	handlers := c.handlers[evt.Cmd]
+	c.mutex.Unlock()
+	if sh != nil && evt.SessionId != "" {
+		sh(evt)
+	}
+	for _, h := range handlers {
+		h(evt)
+	}
+	if evt.Cmd == rpc.SystemCommand_USER_EVENT && evt.AckId != 0 {
+		// ack after handled, the event is resent if media server doesn't receive it
+		ack := &rpc.SystemEvent{Cmd: rpc.SystemCommand_USER_EVENT_ACK, SessionId: evt.SessionId, AckId: evt.AckId}
+		if err := c.Send(ack); err != nil {
+			logger.Warnf("instance(%v) ack event(%v) failed: %v", c.instanceId, evt.AckId, err)
+		}
+	}
+}
+
+// channelLoop keeps system channel connected until client closed
+func (c *Client) channelLoop() {
+	defer c.wg.Done()
+	for {
+		if err := c.runChannel(); err != nil {
+			logger.Warnf("instance(%v) system channel disconnected: %v", c.instanceId, err)
+		}
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-time.After(c.reconnectInterval):
+		}
+	}
+}
+
+// runChannel registers instance, then keeps sending keep-alive and dispatching events until stream broken
+func (c *Client) runChannel() error {
+	ctx, cancel := context.WithCancel(c.ctx)
+	defer cancel()
+	stream, err := c.api.SystemChannel(ctx)
+	if err != nil {
+		return err
+	}
+	// register with the last received seq, then ack it so that media server replays events not received yet
+	lastSeq := atomic.LoadUint64(&c.lastSeq)
+	if err = stream.Send(&rpc.SystemEvent{Cmd: rpc.SystemCommand_REGISTER, InstanceId: c.instanceId,
+		Seq: lastSeq, Group: c.group}); err != nil {
+		return err
+	}
+	if err = stream.Send(&rpc.SystemEvent{Cmd: rpc.SystemCommand_SEQ_ACK, InstanceId: c.instanceId,
+		Seq: lastSeq}); err != nil {
+		return err
+	}
+	c.mutex.Lock()
+	c.stream = stream
+	close(c.connectedC)
+	c.mutex.Unlock()
+	logger.Infof("instance(%v) system channel connected", c.instanceId)
+	defer func() {
+		c.mutex.Lock()
+		c.stream = nil
+		c.connectedC = make(chan struct{})
+		c.mutex.Unlock()
+	}()
+
+	lastSeen := time.Now().UnixNano()
+	errC := make(chan error, 1)
+	go func() {
+		for {
+			evt, err := stream.Recv()
+			if err != nil {
+				errC <- err
+				return
+			}
+			atomic.StoreInt64(&lastSeen, time.Now().UnixNano())
+			if evt.Cmd == rpc.SystemCommand_KEEPALIVE {
+				continue
+			}
+			if evt.Seq == 0 {
+				c.dispatch(evt)
+				continue
+			}
+			// drop replayed or retransmitted events that have been dispatched
+			if last := atomic.LoadUint64(&c.lastSeq); evt.Seq > last {
+				if last != 0 && evt.Seq > last+1 {
+					logger.Warnf("instance(%v) lost events from seq %v to %v", c.instanceId, last+1, evt.Seq-1)
+				}
+				c.dispatch(evt)
+				atomic.StoreUint64(&c.lastSeq, evt.Seq)
+			}
+			c.Send(&rpc.SystemEvent{Cmd: rpc.SystemCommand_SEQ_ACK, Seq: atomic.LoadUint64(&c.lastSeq)})
+		}
+	}()
+
+	ticker := time.NewTicker(c.keepaliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case err = <-errC:
+			return err
+		case <-ticker.C:
+			if time.Since(time.Unix(0, atomic.LoadInt64(&lastSeen))) > keepaliveTimeoutFactor*c.keepaliveInterval {
+				return errors.New("keep-alive timeout")
+			}
+			if err = c.Send(&rpc.SystemEvent{Cmd: rpc.SystemCommand_KEEPALIVE}); err != nil {
+				return err
+			}
+		case <-c.ctx.Done():
+			return nil
+		}
+	}
+}
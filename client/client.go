@@ -0,0 +1,190 @@
+// Package client wraps MediaApi with typed session handles and a managed system channel, so that signalling services
+// don't have to deal with string session ids, "ok"/"err" replies and keep-alive of system channel by hand.
+package client
+
+import (
+	"context"
+	"errors"
+	"github.com/appcrash/media/server/rpc"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultKeepaliveInterval = 2 * time.Second
+	DefaultReconnectInterval = time.Second
+)
+
+var logger *logrus.Entry
+
+func init() {
+	InitLogger(logrus.New())
+}
+
+func InitLogger(gl *logrus.Logger) {
+	logger = gl.WithFields(logrus.Fields{"module": "client"})
+}
+
+// EventHandler is called in the system channel goroutine, don't block it
+type EventHandler func(evt *rpc.SystemEvent)
+
+type Config struct {
+	// Address of media server's grpc, i.e. "127.0.0.1:5678"
+	Address string
+	// DialOptions default to insecure transport if empty
+	DialOptions []grpc.DialOption
+	// InstanceId is used to register system channel and create sessions, system channel is not connected if empty
+	InstanceId string
+	// Group is the failover group registered with system channel, sessions of the instance are adopted by another
+	// instance of the group if this one is lost, see Adopt for adopting sessions explicitly
+	Group string
+	// KeepaliveInterval defaults to DefaultKeepaliveInterval, system channel reconnects if no keep-alive reply is
+	// received in 3 intervals. ReconnectInterval defaults to DefaultReconnectInterval.
+	KeepaliveInterval time.Duration
+	ReconnectInterval time.Duration
+}
+
+// Client is a connection to a media server, it is goroutine safe
+type Client struct {
+	instanceId        string
+	group             string
+	conn              *grpc.ClientConn
+	api               rpc.MediaApiClient
+	keepaliveInterval time.Duration
+	reconnectInterval time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mutex           sync.Mutex
+	handlers        map[rpc.SystemCommand][]EventHandler
+	sessionHandlers map[string]EventHandler
+	stream          rpc.MediaApi_SystemChannelClient // nil if system channel is not connected
+	connectedC      chan struct{}                    // closed when system channel is connected
+
+	lastSeq uint64 // seq of the last received event, accessed atomically
+}
+
+func New(c Config) (*Client, error) {
+	if c.Address == "" {
+		return nil, errors.New("empty media server address")
+	}
+	opts := c.DialOptions
+	if len(opts) == 0 {
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+	conn, err := grpc.Dial(c.Address, opts...)
+	if err != nil {
+		return nil, err
+	}
+	cli := &Client{
+		instanceId:        c.InstanceId,
+		group:             c.Group,
+		conn:              conn,
+		api:               rpc.NewMediaApiClient(conn),
+		keepaliveInterval: DefaultKeepaliveInterval,
+		reconnectInterval: DefaultReconnectInterval,
+		handlers:          make(map[rpc.SystemCommand][]EventHandler),
+		sessionHandlers:   make(map[string]EventHandler),
+		connectedC:        make(chan struct{}),
+	}
+	if c.KeepaliveInterval > 0 {
+		cli.keepaliveInterval = c.KeepaliveInterval
+	}
+	if c.ReconnectInterval > 0 {
+		cli.reconnectInterval = c.ReconnectInterval
+	}
+	cli.ctx, cli.cancel = context.WithCancel(context.Background())
+	if cli.instanceId != "" {
+		cli.wg.Add(1)
+		go cli.channelLoop()
+	}
+	return cli, nil
+}
+
+// Close disconnects system channel and grpc connection, sessions are kept in media server
+func (c *Client) Close() error {
+	c.cancel()
+	c.wg.Wait()
+	return c.conn.Close()
+}
+
+// Api returns the raw grpc client for rpc not wrapped by Client
+func (c *Client) Api() rpc.MediaApiClient {
+	return c.api
+}
+
+func (c *Client) InstanceId() string {
+	return c.instanceId
+}
+
+// Prepare creates a session, instance id of the client is used if param doesn't have one
+func (c *Client) Prepare(ctx context.Context, param *rpc.CreateParam) (*Session, error) {
+	if param.InstanceId == "" {
+		param.InstanceId = c.instanceId
+	}
+	rs, err := c.api.PrepareSession(ctx, param)
+	if err != nil {
+		return nil, err
+	}
+	return c.newSession(rs), nil
+}
+
+// PrepareWithOffer creates a session by remote sdp offer, the answer is available by Session.Answer
+func (c *Client) PrepareWithOffer(ctx context.Context, param *rpc.OfferParam) (*Session, error) {
+	if param.InstanceId == "" {
+		param.InstanceId = c.instanceId
+	}
+	answer, err := c.api.PrepareSessionWithOffer(ctx, param)
+	if err != nil {
+		return nil, err
+	}
+	s := c.newSession(answer.Session)
+	s.answer = answer
+	return s, nil
+}
+
+// Session returns handle of an existing session, i.e. created by another client
+func (c *Client) Session(sessionId string) *Session {
+	return c.newSession(&rpc.Session{SessionId: sessionId})
+}
+
+func (c *Client) Capabilities(ctx context.Context) (*rpc.Capabilities, error) {
+	return c.api.GetCapabilities(ctx, &rpc.Empty{})
+}
+
+// Capacity returns current load of media server, which is also sent as CAPACITY_REPORT to system channel when
+// queried by QueryCapacity or broadcast by media server periodically
+func (c *Client) Capacity(ctx context.Context) (*rpc.CapacityReport, error) {
+	return c.api.GetCapacity(ctx, &rpc.Empty{})
+}
+
+// ListSessions lists sessions created by instance of this client if param is nil
+func (c *Client) ListSessions(ctx context.Context, param *rpc.ListParam) ([]*rpc.SessionInfo, error) {
+	if param == nil {
+		param = &rpc.ListParam{InstanceId: c.instanceId}
+	}
+	list, err := c.api.ListSessions(ctx, param)
+	if err != nil {
+		return nil, err
+	}
+	return list.Sessions, nil
+}
+
+// Adopt takes over sessions of a lost instance, all of its sessions if sessionIds is empty. ids of adopted sessions
+// are returned, OWNERSHIP_TRANSFER of each one is also sent to system channel of this client.
+func (c *Client) Adopt(ctx context.Context, fromInstanceId string, sessionIds ...string) ([]string, error) {
+	result, err := c.api.AdoptSessions(ctx, &rpc.AdoptParam{
+		FromInstanceId: fromInstanceId,
+		SessionId:      sessionIds,
+		InstanceId:     c.instanceId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.SessionId, nil
+}
@@ -0,0 +1,453 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/appcrash/media/client"
+	"github.com/appcrash/media/server"
+	"github.com/appcrash/media/server/comp"
+	"github.com/appcrash/media/server/rpc"
+	"github.com/appcrash/media/server/utils"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// probe replies calls and notifies instance with casts
+type probe struct {
+	comp.SessionNode
+	comp.ChannelNode
+
+	channel chan *utils.RtpPacketList
+}
+
+func (n *probe) PullPacketChannel() <-chan *utils.RtpPacketList {
+	return n.channel
+}
+
+func (n *probe) HandlePacketChannel() chan<- *utils.RtpPacketList {
+	return n.channel
+}
+
+func (n *probe) OnCall(_ string, args []string) []string {
+	if len(args) > 0 && args[0] == "fail" {
+		return comp.WithError("failed")
+	}
+	return comp.WithOk(args...)
+}
+
+// OnCast of "ack EVENT" from instance notifies EVENT with ack, then notifies the delivery result
+func (n *probe) OnCast(from string, args []string) {
+	if from == comp.InstanceSender && len(args) == 2 && args[0] == "ack" {
+		go func() {
+			err := <-n.NotifyInstanceWithAck(args[1], 2*time.Second)
+			n.NotifyInstance(fmt.Sprintf("delivered %v", err))
+		}()
+		return
+	}
+	n.NotifyInstance(strings.Join(args, "#"))
+}
+
+func (n *probe) OnUserEvent(event string) {
+	n.NotifyInstance("user " + event)
+}
+
+var serverAddr string
+
+func startServer() (err error) {
+	var lis net.Listener
+	if lis, err = net.Listen("tcp", "127.0.0.1:0"); err != nil {
+		return
+	}
+	port := lis.Addr().(*net.TCPAddr).Port
+	lis.Close()
+	serverAddr = lis.Addr().String()
+
+	gl := logrus.New()
+	gl.SetLevel(logrus.WarnLevel)
+	server.InitServerLogger(gl)
+	client.InitLogger(gl)
+	comp.InitBuiltIn()
+	if err = comp.RegisterNodeTrait(comp.NT[probe]("probe", func() comp.SessionAware {
+		n := &probe{channel: make(chan *utils.RtpPacketList, 32)}
+		n.Self = n
+		n.Trait, _ = comp.NodeTraitOfType("probe")
+		return n
+	})); err != nil {
+		return
+	}
+	start, _, err := server.NewServer(&server.Config{
+		RtpIp:          "127.0.0.1",
+		StartPort:      32000,
+		EndPort:        32100,
+		PortQuarantine: -1,
+		GrpcIp:         "127.0.0.1",
+		GrpcPort:       uint16(port),
+		FailoverGrace:  500 * time.Millisecond,
+
+		CapacityReportPeriod: 200 * time.Millisecond,
+	})
+	if err != nil {
+		return
+	}
+	go start()
+	return
+}
+
+func TestMain(m *testing.M) {
+	if err := startServer(); err != nil {
+		panic(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	os.Exit(m.Run())
+}
+
+func prepareProbe(t *testing.T, c *client.Client) *client.Session {
+	s, err := c.Prepare(context.Background(), &rpc.CreateParam{
+		PeerIp:   "127.0.0.1",
+		PeerPort: 44070,
+		Codecs: []*rpc.CodecInfo{{
+			PayloadNumber: 8,
+			PayloadType:   rpc.CodecType_PCM_ALAW,
+		}},
+		GraphDesc: client.NewGraph().Add(client.N("probe")).String(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestSession(t *testing.T) {
+	c, err := client.New(client.Config{Address: serverAddr, InstanceId: "client_session"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err = c.WaitConnected(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	s := prepareProbe(t, c)
+	if s.Info().LocalRtpPort == 0 {
+		t.Fatalf("invalid session info: %v", s.Info())
+	}
+	reply, err := s.Call(ctx, "probe", "hello", "world")
+	if err != nil || strings.Join(reply, " ") != "hello world" {
+		t.Fatalf("invalid reply of call: %v %v", reply, err)
+	}
+	var callErr *client.CallError
+	if _, err = s.Call(ctx, "probe", "fail"); !errors.As(err, &callErr) || callErr.Reply[0] != "failed" {
+		t.Fatalf("call should fail with reply: %v", err)
+	}
+
+	sessionC, globalC := make(chan string, 1), make(chan string, 1)
+	s.OnEvent(func(evt *rpc.SystemEvent) {
+		sessionC <- evt.Event
+	})
+	c.Handle(rpc.SystemCommand_USER_EVENT, func(evt *rpc.SystemEvent) {
+		globalC <- evt.SessionId
+	})
+	if err = s.Cast(ctx, "probe", "a b", `say "hi"`, "it's"); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []chan string{sessionC, globalC} {
+		select {
+		case evt := <-c:
+			if evt != `a b#say "hi"#it's` && evt != s.Id() {
+				t.Fatalf("unexpected event: %v", evt)
+			}
+		case <-ctx.Done():
+			t.Fatal("event of cast not received")
+		}
+	}
+
+	if err = s.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if sessions, err := c.ListSessions(ctx, nil); err != nil || len(sessions) != 0 {
+		t.Fatalf("session should be stopped: %v %v", sessions, err)
+	}
+	if err = c.Session(s.Id()).Cast(ctx, "probe", "x"); err == nil {
+		t.Fatal("cast to stopped session should fail")
+	}
+}
+
+func TestUserEvent(t *testing.T) {
+	c, err := client.New(client.Config{Address: serverAddr, InstanceId: "client_user_event"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err = c.WaitConnected(ctx); err != nil {
+		t.Fatal(err)
+	}
+	s := prepareProbe(t, c)
+	defer s.Stop(ctx)
+	eventC := make(chan *rpc.SystemEvent, 4)
+	s.OnEvent(func(evt *rpc.SystemEvent) {
+		eventC <- evt
+	})
+	expectEvent := func(event string, withAck bool) {
+		t.Helper()
+		select {
+		case evt := <-eventC:
+			if evt.Event != event || evt.Node != "probe" || (evt.AckId != 0) != withAck {
+				t.Fatalf("expect event %v but got: %v", event, evt)
+			}
+		case <-ctx.Done():
+			t.Fatalf("event %v not received", event)
+		}
+	}
+
+	if err = s.SendEvent("", "hello"); err != nil {
+		t.Fatal(err)
+	}
+	expectEvent("user hello", false)
+	if err = s.SendEvent("probe", "ack world"); err != nil {
+		t.Fatal(err)
+	}
+	expectEvent("world", true)
+	expectEvent("delivered <nil>", false)
+}
+
+// proxy forwards tcp connections to media server, so that they can be broken by test
+type proxy struct {
+	lis   net.Listener
+	mutex sync.Mutex
+	conns []net.Conn
+}
+
+func newProxy(t *testing.T, target string) *proxy {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &proxy{lis: lis}
+	go func() {
+		for {
+			in, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			out, err := net.Dial("tcp", target)
+			if err != nil {
+				in.Close()
+				continue
+			}
+			p.mutex.Lock()
+			p.conns = append(p.conns, in, out)
+			p.mutex.Unlock()
+			go func() { io.Copy(out, in); out.Close() }()
+			go func() { io.Copy(in, out); in.Close() }()
+		}
+	}()
+	return p
+}
+
+func (p *proxy) breakConns() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for _, c := range p.conns {
+		c.Close()
+	}
+	p.conns = nil
+}
+
+func TestReconnect(t *testing.T) {
+	p := newProxy(t, serverAddr)
+	defer p.lis.Close()
+	c, err := client.New(client.Config{
+		Address:           p.lis.Addr().String(),
+		InstanceId:        "client_reconnect",
+		KeepaliveInterval: 100 * time.Millisecond,
+		ReconnectInterval: 100 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err = c.WaitConnected(ctx); err != nil {
+		t.Fatal(err)
+	}
+	s := prepareProbe(t, c)
+	defer s.Stop(context.Background())
+	eventC := make(chan string, 8)
+	s.OnEvent(func(evt *rpc.SystemEvent) {
+		eventC <- evt.Event
+	})
+
+	p.breakConns()
+	// events sent during disconnection are replayed once system channel is reconnected, each is dispatched once
+	direct, err := client.New(client.Config{Address: serverAddr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer direct.Close()
+	for _, event := range []string{"1", "2", "3"} {
+		if err = direct.Session(s.Id()).Cast(ctx, "probe", event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, expected := range []string{"1", "2", "3"} {
+		select {
+		case event := <-eventC:
+			if event != expected {
+				t.Fatalf("expect event %v but got %v", expected, event)
+			}
+		case <-ctx.Done():
+			t.Fatal("system channel is not reconnected")
+		}
+	}
+	select {
+	case event := <-eventC:
+		t.Fatalf("event %v is dispatched again", event)
+	case <-time.After(500 * time.Millisecond):
+	}
+}
+
+func TestFailover(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	newClient := func(instanceId, group string) *client.Client {
+		c, err := client.New(client.Config{Address: serverAddr, InstanceId: instanceId, Group: group})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = c.WaitConnected(ctx); err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	adopter := newClient("client_failover_b", "client_failover")
+	defer adopter.Close()
+	transferC := make(chan *rpc.SystemEvent, 4)
+	adopter.Handle(rpc.SystemCommand_OWNERSHIP_TRANSFER, func(evt *rpc.SystemEvent) {
+		transferC <- evt
+	})
+	expectTransfer := func(sessionId, from string) {
+		t.Helper()
+		select {
+		case evt := <-transferC:
+			if evt.SessionId != sessionId || evt.Event != from {
+				t.Fatalf("unexpected ownership transfer: %v", evt)
+			}
+		case <-ctx.Done():
+			t.Fatalf("session %v is not adopted", sessionId)
+		}
+	}
+
+	// sessions of a lost instance are adopted by instance of the same group, and events of nodes follow the owner
+	lost := newClient("client_failover_a", "client_failover")
+	s := prepareProbe(t, lost)
+	defer s.Stop(context.Background())
+	lost.Close()
+	expectTransfer(s.Id(), "client_failover_a")
+	eventC := make(chan string, 4)
+	adopted := adopter.Session(s.Id())
+	adopted.OnEvent(func(evt *rpc.SystemEvent) {
+		eventC <- evt.Event
+	})
+	if err := adopted.Cast(ctx, "probe", "adopted"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case event := <-eventC:
+		if event != "adopted" {
+			t.Fatalf("unexpected event: %v", event)
+		}
+	case <-ctx.Done():
+		t.Fatal("event of adopted session is not sent to adopter")
+	}
+	list, err := adopter.ListSessions(ctx, nil)
+	if err != nil || len(list) != 1 || list[0].SessionId != s.Id() {
+		t.Fatalf("adopted session should be listed by adopter: %v %v", list, err)
+	}
+
+	// sessions of instance without group wait for adoption
+	alone := newClient("client_failover_c", "")
+	s = prepareProbe(t, alone)
+	defer s.Stop(context.Background())
+	if _, err = adopter.Adopt(ctx, "client_failover_c"); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("sessions of registered instance should not be adopted: %v", err)
+	}
+	alone.Close()
+	time.Sleep(time.Second)
+	select {
+	case evt := <-transferC:
+		t.Fatalf("session without group is adopted: %v", evt)
+	default:
+	}
+	ids, err := adopter.Adopt(ctx, "client_failover_c")
+	if err != nil || len(ids) != 1 || ids[0] != s.Id() {
+		t.Fatalf("adopt session failed: %v %v", ids, err)
+	}
+	expectTransfer(s.Id(), "client_failover_c")
+}
+
+func TestCapacity(t *testing.T) {
+	c, err := client.New(client.Config{Address: serverAddr, InstanceId: "client_capacity"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	reportC := make(chan *rpc.CapacityReport, 16)
+	c.Handle(rpc.SystemCommand_CAPACITY_REPORT, func(evt *rpc.SystemEvent) {
+		select {
+		case reportC <- evt.Capacity:
+		default:
+		}
+	})
+	if err = c.WaitConnected(ctx); err != nil {
+		t.Fatal(err)
+	}
+	s := prepareProbe(t, c)
+	defer s.Stop(context.Background())
+	checkReport := func(report *rpc.CapacityReport) {
+		t.Helper()
+		if len(report.Interfaces) != 1 || report.Interfaces[0].Name != server.DefaultRtpInterface ||
+			report.Interfaces[0].Capacity != 50 || report.Interfaces[0].FreePorts >= 50 {
+			t.Fatalf("invalid interface capacity: %v", report.Interfaces)
+		}
+		if report.Sessions == 0 || report.GraphNodes == 0 || report.Goroutines == 0 || report.Draining {
+			t.Fatalf("invalid capacity report: %v", report)
+		}
+	}
+	report, err := c.Capacity(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkReport(report)
+
+	// expect periodic and queried reports, skip the ones made before session is prepared
+	if err = c.QueryCapacity(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; {
+		select {
+		case r := <-reportC:
+			if r.Time >= report.Time {
+				checkReport(r)
+				i++
+			}
+		case <-ctx.Done():
+			t.Fatal("capacity report not received")
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Node is a node definition of nmd graph, i.e. [name@scope:type key='value']
+type Node struct {
+	name, scope, typ string
+	props            map[string]string // formatted values
+}
+
+// N makes a node with name, its type is the name if not set by Type
+func N(name string) *Node {
+	return &Node{name: name, props: make(map[string]string)}
+}
+
+func (n *Node) Type(typ string) *Node {
+	n.typ = typ
+	return n
+}
+
+// Scope makes the node refer to node of another session
+func (n *Node) Scope(scope string) *Node {
+	n.scope = scope
+	return n
+}
+
+// Prop sets node property, value can be string, integer or float. nmd doesn't support negative numbers.
+func (n *Node) Prop(key string, value interface{}) *Node {
+	var v string
+	switch pv := value.(type) {
+	case string:
+		v = "'" + strings.ReplaceAll(pv, "'", `\'`) + "'"
+	case float32, float64:
+		v = fmt.Sprintf("%f", pv)
+	default:
+		v = fmt.Sprintf("%v", pv)
+	}
+	n.props[key] = v
+	return n
+}
+
+func (n *Node) String() string {
+	var b strings.Builder
+	b.WriteString("[" + n.name)
+	if n.scope != "" {
+		b.WriteString("@" + n.scope)
+	}
+	if n.typ != "" {
+		b.WriteString(":" + n.typ)
+	}
+	keys := make([]string, 0, len(n.props))
+	for k := range n.props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		b.WriteString(" " + k + "=" + n.props[k])
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+// Endpoint is one or more nodes in a link statement, multiple nodes are linked together like {[a],[b]}
+type Endpoint []*Node
+
+func (e Endpoint) String() string {
+	if len(e) == 1 {
+		return e[0].String()
+	}
+	nodes := make([]string, len(e))
+	for i, n := range e {
+		nodes[i] = n.String()
+	}
+	return "{" + strings.Join(nodes, ",") + "}"
+}
+
+// Graph builds graph description of nmd
+type Graph struct {
+	stmts []string
+}
+
+func NewGraph() *Graph {
+	return &Graph{}
+}
+
+// Add defines nodes without linking them
+func (g *Graph) Add(nodes ...*Node) *Graph {
+	for _, n := range nodes {
+		g.stmts = append(g.stmts, n.String())
+	}
+	return g
+}
+
+// Link links nodes in order, i.e. [a]->[b]->[c]
+func (g *Graph) Link(nodes ...*Node) *Graph {
+	endpoints := make([]Endpoint, len(nodes))
+	for i, n := range nodes {
+		endpoints[i] = Endpoint{n}
+	}
+	return g.LinkEndpoints(endpoints...)
+}
+
+// LinkEndpoints links endpoints in order, i.e. [a]->{[b],[c]}
+func (g *Graph) LinkEndpoints(endpoints ...Endpoint) *Graph {
+	es := make([]string, len(endpoints))
+	for i, e := range endpoints {
+		es[i] = e.String()
+	}
+	g.stmts = append(g.stmts, strings.Join(es, "->"))
+	return g
+}
+
+func (g *Graph) String() string {
+	return strings.Join(g.stmts, ";")
+}
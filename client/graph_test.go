@@ -0,0 +1,35 @@
+package client_test
+
+import (
+	"github.com/appcrash/media/client"
+	"github.com/appcrash/media/server/comp/nmd"
+	"testing"
+)
+
+func TestGraph(t *testing.T) {
+	g := client.NewGraph().
+		Add(client.N("rtp").Type("rtp_src").Prop("payload", 8).Prop("name", "it's")).
+		Link(client.N("a"), client.N("b").Prop("gain", 0.5)).
+		LinkEndpoints(client.Endpoint{client.N("b")}, client.Endpoint{client.N("c"), client.N("d").Scope("other")})
+	expected := `[rtp:rtp_src name='it\'s' payload=8];[a]->[b gain=0.500000];[b]->{[c],[d@other]}`
+	if g.String() != expected {
+		t.Fatalf("unexpected graph: %v", g)
+	}
+	gt := nmd.NewGraphTopology()
+	if err := gt.ParseGraph("session", g.String(), nil); err != nil {
+		t.Fatalf("graph can not be parsed: %v", err)
+	}
+	nodes := make(map[string]*nmd.NodeDef)
+	for _, nd := range gt.GetSortedNodeDefs() {
+		nodes[nd.Name] = nd
+	}
+	rtp := nodes["rtp"]
+	if rtp == nil || rtp.Type != "rtp_src" || len(rtp.Props) != 2 {
+		t.Fatalf("invalid node: %v", rtp)
+	}
+	for _, p := range rtp.Props {
+		if p.Key == "name" && p.Value != "it's" {
+			t.Fatalf("invalid quoted property: %v", p.Value)
+		}
+	}
+}
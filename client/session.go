@@ -0,0 +1,166 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/appcrash/media/server/rpc"
+	"strings"
+)
+
+const (
+	// execCmd is the built-in command executing nmd call/cast statements
+	execCmd = "exec"
+
+	replyOk    = "ok"
+	replyError = "err"
+)
+
+// CallError is returned when node replies with error, see comp.WithError
+type CallError struct {
+	Node  string
+	Reply []string
+}
+
+func (e *CallError) Error() string {
+	return fmt.Sprintf("node(%v) replied with error: %v", e.Node, strings.Join(e.Reply, " "))
+}
+
+// Session is handle of a session in media server
+type Session struct {
+	client *Client
+	info   *rpc.Session
+	answer *rpc.Answer // only for session created by offer
+}
+
+func (c *Client) newSession(info *rpc.Session) *Session {
+	return &Session{client: c, info: info}
+}
+
+func (s *Session) Id() string {
+	return s.info.GetSessionId()
+}
+
+// Info returns local and peer rtp address when the session is prepared
+func (s *Session) Info() *rpc.Session {
+	return s.info
+}
+
+// Answer returns sdp answer if the session is created by offer, otherwise nil
+func (s *Session) Answer() *rpc.Answer {
+	return s.answer
+}
+
+func (s *Session) Start(ctx context.Context) error {
+	_, err := s.client.api.StartSession(ctx, &rpc.StartParam{SessionId: s.Id()})
+	return err
+}
+
+// Update changes peer address of the session, payload number is not changed if it is not positive
+func (s *Session) Update(ctx context.Context, peerIp string, peerPort uint16, payloadNumber int) error {
+	_, err := s.client.api.UpdateSession(ctx, &rpc.UpdateParam{
+		SessionId:     s.Id(),
+		PeerIp:        peerIp,
+		PeerPort:      uint32(peerPort),
+		PayloadNumber: int32(payloadNumber),
+	})
+	if err == nil {
+		s.info.PeerIp, s.info.PeerRtpPort = peerIp, uint32(peerPort)
+	}
+	return err
+}
+
+// Reoffer updates session with remote sdp offer, returns the answer
+func (s *Session) Reoffer(ctx context.Context, sdp string) (*rpc.Answer, error) {
+	answer, err := s.client.api.UpdateSessionWithOffer(ctx, &rpc.ReofferParam{SessionId: s.Id(), Sdp: sdp})
+	if err == nil {
+		s.answer = answer
+	}
+	return answer, err
+}
+
+// Stop stops the session and removes its event handler
+func (s *Session) Stop(ctx context.Context) error {
+	s.OnEvent(nil)
+	_, err := s.client.api.StopSession(ctx, &rpc.StopParam{SessionId: s.Id()})
+	return err
+}
+
+func (s *Session) Describe(ctx context.Context) (*rpc.SessionDescription, error) {
+	return s.client.api.DescribeSession(ctx, &rpc.DescribeParam{SessionId: s.Id()})
+}
+
+// quoteArgs joins args as command of nmd call/cast statement, args with space or quote are double-quoted
+func quoteArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\"") {
+			arg = `"` + strings.ReplaceAll(arg, `"`, `\"`) + `"`
+		}
+		quoted[i] = arg
+	}
+	return strings.ReplaceAll(strings.Join(quoted, " "), "'", `\'`)
+}
+
+// Call sends command to node and waits for its reply, the leading "ok" is stripped from reply, *CallError is
+// returned if node replies "err"
+func (s *Session) Call(ctx context.Context, node string, args ...string) ([]string, error) {
+	result, err := s.client.api.ExecuteAction(ctx, &rpc.Action{
+		SessionId: s.Id(),
+		Cmd:       execCmd,
+		CmdArg:    fmt.Sprintf("[%v] <-> '%v'", node, quoteArgs(args)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	reply := strings.Fields(result.GetState())
+	if len(reply) == 0 {
+		return nil, nil
+	}
+	switch reply[0] {
+	case replyOk:
+		return reply[1:], nil
+	case replyError:
+		return nil, &CallError{Node: node, Reply: reply[1:]}
+	}
+	if result.GetState() == "error execute not exist" {
+		return nil, errors.New("session not exist")
+	}
+	return reply, nil
+}
+
+// Cast sends command to node without waiting for reply
+func (s *Session) Cast(ctx context.Context, node string, args ...string) error {
+	result, err := s.client.api.ExecuteAction(ctx, &rpc.Action{
+		SessionId: s.Id(),
+		Cmd:       execCmd,
+		CmdArg:    fmt.Sprintf("[%v] <-- '%v'", node, quoteArgs(args)),
+	})
+	if err == nil && result.GetState() == "error execute not exist" {
+		err = errors.New("session not exist")
+	}
+	return err
+}
+
+// SendEvent sends USER_EVENT to the session through system channel. if node is not empty, the event is parsed like
+// command of Cast and sent to the node, otherwise all nodes implementing comp.UserEventReceiver receive it.
+func (s *Session) SendEvent(node, event string) error {
+	return s.client.Send(&rpc.SystemEvent{
+		Cmd:       rpc.SystemCommand_USER_EVENT,
+		SessionId: s.Id(),
+		Node:      node,
+		Event:     event,
+	})
+}
+
+// OnEvent sets handler of system events of this session, i.e. USER_EVENT sent by nodes. nil handler removes it.
+func (s *Session) OnEvent(h EventHandler) {
+	c := s.client
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if h == nil {
+		delete(c.sessionHandlers, s.Id())
+	} else {
+		c.sessionHandlers[s.Id()] = h
+	}
+}
@@ -0,0 +1,450 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/appcrash/media/server/rpc"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// codecFlags parses repeated -codec flags in form of {CodecType}/{payload number}, i.e. PCM_ALAW/8
+type codecFlags []*rpc.CodecInfo
+
+func (cf *codecFlags) String() string {
+	var codecs []string
+	for _, c := range *cf {
+		codecs = append(codecs, fmt.Sprintf("%v/%v", c.PayloadType, c.PayloadNumber))
+	}
+	return strings.Join(codecs, ",")
+}
+
+func (cf *codecFlags) Set(value string) error {
+	parts := strings.SplitN(value, "/", 2)
+	if len(parts) != 2 {
+		return errors.New("codec should be in form of TYPE/PAYLOAD_NUMBER")
+	}
+	typ, ok := rpc.CodecType_value[strings.ToUpper(parts[0])]
+	if !ok {
+		return fmt.Errorf("unknown codec type: %v", parts[0])
+	}
+	pt, err := strconv.ParseUint(parts[1], 10, 7)
+	if err != nil {
+		return fmt.Errorf("invalid payload number: %v", parts[1])
+	}
+	*cf = append(*cf, &rpc.CodecInfo{PayloadType: rpc.CodecType(typ), PayloadNumber: uint32(pt)})
+	return nil
+}
+
+// parseArgs parses flags of command, and checks number of positional args
+func parseArgs(fs *flag.FlagSet, args []string, nbArgs int) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != nbArgs {
+		return fmt.Errorf("expect %v arguments but got %v", nbArgs, fs.NArg())
+	}
+	return nil
+}
+
+// readScript reads file if not empty, otherwise takes the argument
+func readScript(file, arg string) (string, error) {
+	if file == "" {
+		return arg, nil
+	}
+	data, err := os.ReadFile(file)
+	return string(data), err
+}
+
+func runPrepare(args []string) error {
+	var codecs codecFlags
+	fs := flag.NewFlagSet("prepare", flag.ContinueOnError)
+	graph := fs.String("graph", "", "graph description")
+	graphFile := fs.String("graph-file", "", "nmd file of graph description, overrides -graph")
+	peerIp := fs.String("peer-ip", "127.0.0.1", "rtp ip of peer")
+	peerPort := fs.Uint("peer-port", 0, "rtp port of peer")
+	itf := fs.String("interface", "", "rtp interface of the session, empty for the default one")
+	start := fs.Bool("start", false, "start the session once prepared")
+	fs.Var(&codecs, "codec", "codec in form of TYPE/PAYLOAD_NUMBER, can be repeated")
+	if err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+	graphDesc, err := readScript(*graphFile, *graph)
+	if err != nil {
+		return err
+	}
+	if len(codecs) == 0 {
+		codecs = codecFlags{{PayloadType: rpc.CodecType_PCM_ALAW, PayloadNumber: 8}}
+	}
+	c, err := connect(false)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	ctx, cancel := unaryContext()
+	defer cancel()
+	session, err := c.Prepare(ctx, &rpc.CreateParam{
+		PeerIp:        *peerIp,
+		PeerPort:      uint32(*peerPort),
+		Codecs:        codecs,
+		GraphDesc:     graphDesc,
+		InstanceId:    instanceId,
+		InterfaceName: *itf,
+	})
+	if err != nil {
+		return err
+	}
+	if *start {
+		if err = session.Start(ctx); err != nil {
+			session.Stop(ctx)
+			return err
+		}
+	}
+	output(session.Info())
+	return nil
+}
+
+func runStart(args []string) error {
+	fs := flag.NewFlagSet("start", flag.ContinueOnError)
+	if err := parseArgs(fs, args, 1); err != nil {
+		return err
+	}
+	c, err := connect(false)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	ctx, cancel := unaryContext()
+	defer cancel()
+	return c.Session(fs.Arg(0)).Start(ctx)
+}
+
+func runStop(args []string) error {
+	fs := flag.NewFlagSet("stop", flag.ContinueOnError)
+	if err := parseArgs(fs, args, 1); err != nil {
+		return err
+	}
+	c, err := connect(false)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	ctx, cancel := unaryContext()
+	defer cancel()
+	return c.Session(fs.Arg(0)).Stop(ctx)
+}
+
+func runUpdate(args []string) error {
+	fs := flag.NewFlagSet("update", flag.ContinueOnError)
+	peerIp := fs.String("peer-ip", "", "rtp ip of peer")
+	peerPort := fs.Uint("peer-port", 0, "rtp port of peer")
+	payloadNumber := fs.Int("payload", 0, "new payload number, not changed if not positive")
+	if err := parseArgs(fs, args, 1); err != nil {
+		return err
+	}
+	c, err := connect(false)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	ctx, cancel := unaryContext()
+	defer cancel()
+	return c.Session(fs.Arg(0)).Update(ctx, *peerIp, uint16(*peerPort), *payloadNumber)
+}
+
+func runExec(args []string) error {
+	fs := flag.NewFlagSet("exec", flag.ContinueOnError)
+	file := fs.String("file", "", "nmd file of call/cast statements")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 || (*file == "" && fs.NArg() != 2) {
+		return errors.New("expect session id and script")
+	}
+	script, err := readScript(*file, fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	c, err := connect(false)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	ctx, cancel := unaryContext()
+	defer cancel()
+	result, err := c.Api().ExecuteAction(ctx, &rpc.Action{SessionId: fs.Arg(0), Cmd: "exec", CmdArg: script})
+	if err != nil {
+		return err
+	}
+	output(result)
+	return nil
+}
+
+func runTail(args []string) error {
+	fs := flag.NewFlagSet("tail", flag.ContinueOnError)
+	if err := parseArgs(fs, args, 2); err != nil {
+		return err
+	}
+	c, err := connect(false)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	ctx, cancel := interruptContext()
+	defer cancel()
+	stream, err := c.Api().ExecuteActionWithNotify(ctx, &rpc.Action{
+		SessionId: fs.Arg(0),
+		Cmd:       "pull_stream",
+		CmdArg:    "<-chan " + fs.Arg(1),
+	})
+	if err != nil {
+		return err
+	}
+	for {
+		evt, err := stream.Recv()
+		if err == io.EOF || ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		output(evt)
+	}
+}
+
+func runPush(args []string) error {
+	fs := flag.NewFlagSet("push", flag.ContinueOnError)
+	chunk := fs.Int("chunk", 160, "bytes of each pushed data")
+	interval := fs.Duration("interval", 20*time.Millisecond, "interval between pushed data, no wait if zero")
+	if err := parseArgs(fs, args, 3); err != nil {
+		return err
+	}
+	if *chunk <= 0 {
+		return errors.New("chunk size should be positive")
+	}
+	f, err := os.Open(fs.Arg(2))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	c, err := connect(false)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	ctx, cancel := interruptContext()
+	defer cancel()
+	stream, err := c.Api().ExecuteActionWithPush(ctx)
+	if err != nil {
+		return err
+	}
+	buf := make([]byte, *chunk)
+	first := true
+	for ctx.Err() == nil {
+		n, err := io.ReadFull(f, buf)
+		if n > 0 {
+			data := &rpc.PushData{Data: append([]byte(nil), buf[:n]...)}
+			if first {
+				data.SessionId, data.Cmd, data.NodeName = fs.Arg(0), "push_stream", fs.Arg(1)
+				first = false
+			}
+			if err := stream.Send(data); err != nil {
+				return err
+			}
+			if *interval > 0 {
+				time.Sleep(*interval)
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	result, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+	output(result)
+	return nil
+}
+
+func runList(args []string) error {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	instance := fs.String("of", "", "only list sessions created by this instance")
+	status := fs.String("status", "", "comma separated status: created,updated,started,stopped")
+	if err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+	param := &rpc.ListParam{InstanceId: *instance}
+	if *status != "" {
+		param.Status = strings.Split(*status, ",")
+	}
+	c, err := connect(false)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	ctx, cancel := unaryContext()
+	defer cancel()
+	list, err := c.Api().ListSessions(ctx, param)
+	if err != nil {
+		return err
+	}
+	output(list)
+	return nil
+}
+
+func runDescribe(args []string) error {
+	fs := flag.NewFlagSet("describe", flag.ContinueOnError)
+	if err := parseArgs(fs, args, 1); err != nil {
+		return err
+	}
+	c, err := connect(false)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	ctx, cancel := unaryContext()
+	defer cancel()
+	desc, err := c.Session(fs.Arg(0)).Describe(ctx)
+	if err != nil {
+		return err
+	}
+	output(desc)
+	return nil
+}
+
+// runAdopt makes -instance adopt sessions, which must be registered in system channel by the signalling service
+func runAdopt(args []string) error {
+	fs := flag.NewFlagSet("adopt", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		return fmt.Errorf("expect previous owner instance")
+	}
+	c, err := connect(false)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	ctx, cancel := unaryContext()
+	defer cancel()
+	result, err := c.Api().AdoptSessions(ctx, &rpc.AdoptParam{
+		FromInstanceId: fs.Arg(0),
+		SessionId:      fs.Args()[1:],
+		InstanceId:     instanceId,
+	})
+	if err != nil {
+		return err
+	}
+	output(result)
+	return nil
+}
+
+func runCaps(args []string) error {
+	fs := flag.NewFlagSet("caps", flag.ContinueOnError)
+	if err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+	c, err := connect(false)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	ctx, cancel := unaryContext()
+	defer cancel()
+	caps, err := c.Capabilities(ctx)
+	if err != nil {
+		return err
+	}
+	output(caps)
+	return nil
+}
+
+func runCapacity(args []string) error {
+	fs := flag.NewFlagSet("capacity", flag.ContinueOnError)
+	if err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+	c, err := connect(false)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	ctx, cancel := unaryContext()
+	defer cancel()
+	report, err := c.Capacity(ctx)
+	if err != nil {
+		return err
+	}
+	output(report)
+	return nil
+}
+
+// runWatch registers system channel, NOTE: it replaces the system channel of a running instance with the same id
+func runWatch(args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	if err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+	c, err := connect(true)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	eventC := make(chan *rpc.SystemEvent, 64)
+	for value := range rpc.SystemCommand_name {
+		c.Handle(rpc.SystemCommand(value), func(evt *rpc.SystemEvent) {
+			select {
+			case eventC <- evt:
+			default:
+				log.Warnf("too many events, drop one")
+			}
+		})
+	}
+	ctx, cancel := interruptContext()
+	defer cancel()
+	for {
+		select {
+		case evt := <-eventC:
+			output(evt)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func runEvents(args []string) error {
+	fs := flag.NewFlagSet("events", flag.ContinueOnError)
+	if err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+	c, err := connect(false)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	ctx, cancel := interruptContext()
+	defer cancel()
+	stream, err := c.Api().WatchSessions(ctx, &rpc.WatchParam{InstanceId: instanceId})
+	if err != nil {
+		return err
+	}
+	for {
+		evt, err := stream.Recv()
+		if err == io.EOF || ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		output(evt)
+	}
+}
@@ -0,0 +1,210 @@
+// mediactl inspects and operates a running media server through the gRPC MediaApi.
+//
+// prepare a session with graph in an nmd file and start it:
+//
+//	mediactl prepare -graph-file echo.nmd -peer-ip 10.0.0.2 -peer-port 30000 -codec PCM_ALAW/8 -start
+//
+// call/cast nodes of the session with nmd script, tail a channel sink node and push a file to a channel source node:
+//
+//	mediactl exec 0123456789 "[player] <-- 'play a.wav'"
+//	mediactl tail 0123456789 sink
+//	mediactl push 0123456789 src audio.raw
+//
+// register as an instance and print system events, add -json for scripting:
+//
+//	mediactl -json -instance ops watch
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"github.com/appcrash/media/client"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+)
+
+var (
+	serverAddr string
+	instanceId string
+	token      string
+	caFile     string
+	certFile   string
+	keyFile    string
+	timeout    time.Duration
+	jsonOutput bool
+	verbose    bool
+)
+
+type command struct {
+	usage string
+	run   func(args []string) error
+}
+
+var commands = map[string]command{
+	"prepare":  {"[flags] - prepare a session", runPrepare},
+	"start":    {"SESSION_ID - start a prepared session", runStart},
+	"stop":     {"SESSION_ID - stop a session", runStop},
+	"update":   {"[flags] SESSION_ID - update peer address of a session", runUpdate},
+	"exec":     {"[-file FILE] SESSION_ID [SCRIPT] - execute call/cast nmd script", runExec},
+	"tail":     {"SESSION_ID NODE - print output of a channel sink node", runTail},
+	"push":     {"[flags] SESSION_ID NODE FILE - push file to a channel source node", runPush},
+	"list":     {"[flags] - list sessions", runList},
+	"describe": {"SESSION_ID - describe a session", runDescribe},
+	"caps":     {"- print capabilities of the server", runCaps},
+	"capacity": {"- print load of the server", runCapacity},
+	"watch":    {"- register system channel as instance and print events", runWatch},
+	"events":   {"- print lifecycle events of sessions created by instance", runEvents},
+	"adopt":    {"FROM_INSTANCE [SESSION_ID...] - hand sessions of a lost instance over to instance", runAdopt},
+}
+
+var log = logrus.New()
+
+func init() {
+	flag.StringVar(&serverAddr, "server", "127.0.0.1:5678", "grpc address of media server")
+	flag.StringVar(&instanceId, "instance", "mediactl", "instance id used to create sessions and watch events")
+	flag.StringVar(&token, "token", "", "authorization token")
+	flag.StringVar(&caFile, "ca", "", "ca certificate file to verify server, enables tls")
+	flag.StringVar(&certFile, "cert", "", "client certificate file for mutual tls")
+	flag.StringVar(&keyFile, "key", "", "client key file for mutual tls")
+	flag.DurationVar(&timeout, "timeout", 10*time.Second, "timeout of unary rpc")
+	flag.BoolVar(&jsonOutput, "json", false, "print results as json, one object per line")
+	flag.BoolVar(&verbose, "v", false, "verbose log")
+	flag.Usage = usage
+}
+
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "usage: mediactl [flags] COMMAND [args]\n\ncommands:\n")
+	var names []string
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(flag.CommandLine.Output(), "  %-9v %v\n", name, commands[name].usage)
+	}
+	fmt.Fprintf(flag.CommandLine.Output(), "\nflags:\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	flag.Parse()
+	log.SetOutput(os.Stderr)
+	if !verbose {
+		log.SetLevel(logrus.WarnLevel)
+	}
+	client.InitLogger(log)
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[flag.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command: %v\n", flag.Arg(0))
+		usage()
+		os.Exit(2)
+	}
+	if err := cmd.run(flag.Args()[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "%v: %v\n", flag.Arg(0), err)
+		os.Exit(1)
+	}
+}
+
+func dialOptions() ([]grpc.DialOption, error) {
+	var opts []grpc.DialOption
+	if caFile == "" {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	} else {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no valid certificate in %v", caFile)
+		}
+		tc := &tls.Config{RootCAs: roots}
+		if certFile != "" {
+			cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+			if err != nil {
+				return nil, err
+			}
+			tc.Certificates = []tls.Certificate{cert}
+		}
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tc)))
+	}
+	if token != "" {
+		withToken := func(ctx context.Context) context.Context {
+			return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+		}
+		opts = append(opts,
+			grpc.WithUnaryInterceptor(func(ctx context.Context, method string, req, reply interface{},
+				cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+				return invoker(withToken(ctx), method, req, reply, cc, opts...)
+			}),
+			grpc.WithStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn,
+				method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+				return streamer(withToken(ctx), desc, cc, method, opts...)
+			}))
+	}
+	return opts, nil
+}
+
+// connect makes client, system channel is connected only if watching is true
+func connect(watching bool) (*client.Client, error) {
+	opts, err := dialOptions()
+	if err != nil {
+		return nil, err
+	}
+	config := client.Config{Address: serverAddr, DialOptions: opts}
+	if watching {
+		config.InstanceId = instanceId
+	}
+	return client.New(config)
+}
+
+func unaryContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), timeout)
+}
+
+// interruptContext is done when SIGINT or SIGTERM received
+func interruptContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sigC := make(chan os.Signal, 1)
+	signal.Notify(sigC, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-sigC:
+			cancel()
+		case <-ctx.Done():
+		}
+		signal.Stop(sigC)
+	}()
+	return ctx, cancel
+}
+
+func output(msg proto.Message) {
+	if jsonOutput {
+		data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+		if err != nil {
+			log.Errorf("marshal %v failed: %v", msg, err)
+			return
+		}
+		fmt.Println(string(data))
+	} else {
+		fmt.Println(prototext.MarshalOptions{Multiline: true}.Format(msg))
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"github.com/appcrash/media/server"
+	"github.com/appcrash/media/server/comp"
+	"github.com/appcrash/media/server/prom"
+	"github.com/appcrash/media/server/utils"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+	"net"
+	"net/http"
+	"os"
+	"time"
+)
+
+const (
+	localGrpcIp    = "127.0.0.1"
+	localStartPort = 10000
+	localEndPort   = 20000
+)
+
+// echo sends back whatever rtp packets received, it makes a session its own rtp provider and consumer
+type echo struct {
+	comp.SessionNode
+
+	channel chan *utils.RtpPacketList
+}
+
+func (n *echo) PullPacketChannel() <-chan *utils.RtpPacketList {
+	return n.channel
+}
+
+func (n *echo) HandlePacketChannel() chan<- *utils.RtpPacketList {
+	return n.channel
+}
+
+func newEcho() comp.SessionAware {
+	n := &echo{channel: make(chan *utils.RtpPacketList, 32)}
+	n.Self = n
+	n.Trait, _ = comp.NodeTraitOfType("echo")
+	return n
+}
+
+// startLocalServer starts an in-process media server on a random grpc port along with a metrics http endpoint
+func startLocalServer() (grpcAddr, metricsUrl string, err error) {
+	var lis net.Listener
+	// pick a free port for grpc
+	if lis, err = net.Listen("tcp", localGrpcIp+":0"); err != nil {
+		return
+	}
+	grpcPort := lis.Addr().(*net.TCPAddr).Port
+	lis.Close()
+
+	// server logs every session operation, keep it quiet unless verbose
+	serverLogger := logrus.New()
+	serverLogger.SetOutput(os.Stdout)
+	if !verbose {
+		serverLogger.SetLevel(logrus.WarnLevel)
+	}
+	server.InitServerLogger(serverLogger)
+	comp.InitBuiltIn()
+	if err = comp.RegisterNodeTrait(comp.NT[echo]("echo", newEcho)); err != nil {
+		return
+	}
+	prom.InitCollector()
+
+	start, _, err := server.NewServer(&server.Config{
+		RtpIp:     localRtpIp,
+		StartPort: localStartPort,
+		EndPort:   localEndPort,
+		GrpcIp:    localGrpcIp,
+		GrpcPort:  uint16(grpcPort),
+	})
+	if err != nil {
+		return
+	}
+	go start()
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	if lis, err = net.Listen("tcp", localMetricsAddr); err != nil {
+		return
+	}
+	go http.Serve(lis, mux)
+
+	grpcAddr = fmt.Sprintf("%v:%v", localGrpcIp, grpcPort)
+	metricsUrl = fmt.Sprintf("http://%v/metrics", lis.Addr().String())
+	log.Infof("local media server started, grpc: %v, metrics: %v", grpcAddr, metricsUrl)
+	// give grpc server a moment to serve
+	time.Sleep(100 * time.Millisecond)
+	return
+}
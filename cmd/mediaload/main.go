@@ -0,0 +1,193 @@
+// mediaload is a load generator for capacity testing of media server.
+//
+// It creates, starts and stops sessions through the gRPC MediaApi at a given rate, and acts as the RTP peer of every
+// session by sending A-law frames and measuring what the server sends back. The tool periodically reports session
+// setup latency, packet loss, jitter and the server-side prometheus counters.
+//
+// run against a local in-process server with an echo graph:
+//
+//	mediaload -local -n 200 -rate 20 -hold 30s
+//
+// run against a remote server:
+//
+//	mediaload -server 10.0.0.1:5678 -metrics http://10.0.0.1:9100/metrics -peer-ip 10.0.0.2 -graph '[rtp_src]->[rtp_sink]'
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/appcrash/media/server/rpc"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+var (
+	serverAddr    string
+	metricsUrl    string
+	graphDesc     string
+	instanceId    string
+	peerIp        string
+	peerPortStart int
+	nbSession     int
+	rate          float64
+	hold          time.Duration
+	reportPeriod  time.Duration
+	verbose       bool
+
+	localMode        bool
+	localRtpIp       string
+	localMetricsAddr string
+)
+
+var log = logrus.New()
+
+func init() {
+	flag.StringVar(&serverAddr, "server", "127.0.0.1:5678", "grpc address of media server")
+	flag.StringVar(&metricsUrl, "metrics", "", "prometheus metrics url of media server, empty to disable scraping")
+	flag.StringVar(&graphDesc, "graph", "[echo]", "graph description of every session")
+	flag.StringVar(&instanceId, "instance", "mediaload", "instance id used to create sessions")
+	flag.StringVar(&peerIp, "peer-ip", "127.0.0.1", "local ip of rtp peers")
+	flag.IntVar(&peerPortStart, "peer-port", 30000, "first local rtp port of peers")
+	flag.IntVar(&nbSession, "n", 100, "number of sessions")
+	flag.Float64Var(&rate, "rate", 10, "sessions created per second")
+	flag.DurationVar(&hold, "hold", 20*time.Second, "how long each session lasts after started")
+	flag.DurationVar(&reportPeriod, "report", 5*time.Second, "report period")
+	flag.BoolVar(&verbose, "v", false, "verbose log")
+
+	flag.BoolVar(&localMode, "local", false, "start an in-process media server with echo node and test against it")
+	flag.StringVar(&localRtpIp, "local-rtp-ip", "127.0.0.1", "rtp ip of the in-process media server")
+	flag.StringVar(&localMetricsAddr, "local-metrics", "127.0.0.1:9100", "metrics listening address of the in-process media server")
+}
+
+func main() {
+	flag.Parse()
+	log.SetOutput(os.Stdout)
+	if verbose {
+		log.SetLevel(logrus.DebugLevel)
+	}
+	if nbSession <= 0 || rate <= 0 {
+		log.Fatalf("invalid session number(%v) or rate(%v)", nbSession, rate)
+	}
+
+	if localMode {
+		var err error
+		if serverAddr, metricsUrl, err = startLocalServer(); err != nil {
+			log.Fatalf("start local server failed: %v", err)
+		}
+	}
+
+	conn, err := grpc.Dial(serverAddr, grpc.WithInsecure())
+	if err != nil {
+		log.Fatalf("dial media server %v failed: %v", serverAddr, err)
+	}
+	defer conn.Close()
+	client := rpc.NewMediaApiClient(conn)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sigC := make(chan os.Signal, 1)
+	signal.Notify(sigC, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigC
+		log.Infof("interrupted, stop creating sessions and wait for running ones")
+		cancel()
+	}()
+
+	st := newStats()
+	rp := newReporter(st, metricsUrl)
+	reportCtx, stopReport := context.WithCancel(context.Background())
+	go rp.run(reportCtx, reportPeriod)
+
+	ports := newPortAllocator(peerPortStart)
+	wg := &sync.WaitGroup{}
+	interval := time.Duration(float64(time.Second) / rate)
+	ticker := time.NewTicker(interval)
+	log.Infof("start %v sessions at rate %v/s to %v with graph %v", nbSession, rate, serverAddr, graphDesc)
+
+createLoop:
+	for i := 0; i < nbSession; i++ {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			break createLoop
+		}
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			runSession(ctx, client, ports, st, index)
+		}(i)
+	}
+	ticker.Stop()
+	wg.Wait()
+	stopReport()
+	rp.report(true)
+}
+
+// runSession carries out the whole life of one session: prepare, start, exchange rtp, stop
+func runSession(ctx context.Context, client rpc.MediaApiClient, ports *portAllocator, st *stats, index int) {
+	var p *peer
+	var err error
+	if p, err = ports.newPeer(peerIp); err != nil {
+		log.Errorf("session#%v can not create rtp peer: %v", index, err)
+		atomic.AddInt64(&st.failed, 1)
+		return
+	}
+	defer ports.release(p)
+
+	begin := time.Now()
+	session, err := client.PrepareSession(ctx, &rpc.CreateParam{
+		PeerIp:   peerIp,
+		PeerPort: uint32(p.port),
+		Codecs: []*rpc.CodecInfo{{
+			PayloadNumber: pcmaPayloadType,
+			PayloadType:   rpc.CodecType_PCM_ALAW,
+		}},
+		GraphDesc:  graphDesc,
+		InstanceId: instanceId,
+	})
+	if err != nil {
+		log.Errorf("session#%v prepare failed: %v", index, err)
+		atomic.AddInt64(&st.failed, 1)
+		return
+	}
+	atomic.AddInt64(&st.created, 1)
+	prepared := time.Now()
+	stopSession := func() {
+		if _, err := client.StopSession(context.Background(), &rpc.StopParam{SessionId: session.SessionId}); err != nil {
+			log.Errorf("session#%v(%v) stop failed: %v", index, session.SessionId, err)
+		}
+		atomic.AddInt64(&st.stopped, 1)
+	}
+	if _, err = client.StartSession(ctx, &rpc.StartParam{SessionId: session.SessionId}); err != nil {
+		log.Errorf("session#%v(%v) start failed: %v", index, session.SessionId, err)
+		atomic.AddInt64(&st.failed, 1)
+		stopSession()
+		return
+	}
+	atomic.AddInt64(&st.started, 1)
+	st.addLatency(prepared.Sub(begin), time.Since(begin))
+	log.Debugf("session#%v(%v) started, local %v:%v", index, session.SessionId, session.LocalIp, session.LocalRtpPort)
+
+	remote := fmt.Sprintf("%v:%v", session.LocalIp, session.LocalRtpPort)
+	if err = p.start(remote); err != nil {
+		log.Errorf("session#%v(%v) rtp peer start failed: %v", index, session.SessionId, err)
+		atomic.AddInt64(&st.failed, 1)
+		stopSession()
+		return
+	}
+	atomic.AddInt64(&st.active, 1)
+	select {
+	case <-time.After(hold):
+	case <-ctx.Done():
+	}
+	atomic.AddInt64(&st.active, -1)
+	p.stop()
+	stopSession()
+	st.addPeer(p)
+}
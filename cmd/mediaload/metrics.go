@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"github.com/prometheus/common/expfmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// server side metrics interested, defined in package prom
+var interestedMetrics = map[string]bool{
+	"all_session":       true,
+	"created_session":   true,
+	"started_session":   true,
+	"session_goroutine": true,
+	"used_port_pair":    true,
+	"node_graph_nodes":  true,
+	"node_graph_links":  true,
+}
+
+type metricsScraper struct {
+	url    string
+	client *http.Client
+}
+
+func newMetricsScraper(url string) *metricsScraper {
+	return &metricsScraper{
+		url:    url,
+		client: &http.Client{Timeout: 3 * time.Second},
+	}
+}
+
+// scrape fetches metrics in prometheus text format and renders interested ones as "name{labels}=value ..."
+func (ms *metricsScraper) scrape() (result string, err error) {
+	resp, err := ms.client.Get(ms.url)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("http status %v", resp.Status)
+		return
+	}
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(resp.Body)
+	if err != nil {
+		return
+	}
+	var items []string
+	for name, mf := range families {
+		if !interestedMetrics[name] {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			var labels []string
+			for _, lp := range m.GetLabel() {
+				labels = append(labels, fmt.Sprintf("%v=%v", lp.GetName(), lp.GetValue()))
+			}
+			var value float64
+			switch {
+			case m.GetCounter() != nil:
+				value = m.GetCounter().GetValue()
+			case m.GetGauge() != nil:
+				value = m.GetGauge().GetValue()
+			case m.GetUntyped() != nil:
+				value = m.GetUntyped().GetValue()
+			}
+			key := name
+			if len(labels) > 0 {
+				key += "{" + strings.Join(labels, ",") + "}"
+			}
+			items = append(items, fmt.Sprintf("%v=%v", key, value))
+		}
+	}
+	sort.Strings(items)
+	result = strings.Join(items, " ")
+	return
+}
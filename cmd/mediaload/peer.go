@@ -0,0 +1,244 @@
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	pcmaPayloadType = 8
+	pcmaClockRate   = 8000
+	pcmaSilence     = 0xd5
+	frameDuration   = 20 * time.Millisecond
+	frameSamples    = pcmaClockRate * frameDuration / time.Second
+	rtpHeaderLength = 12
+	rtpVersion      = 2
+	maxPortTries    = 1000
+)
+
+// peer is the remote endpoint of one server session. it sends an A-law frame every 20ms and collects statistics of
+// the rtp stream sent back by media server, loss and jitter are calculated as described in RFC 3550 (A.1, A.8)
+type peer struct {
+	port               int
+	dataConn, ctrlConn *net.UDPConn
+
+	ssrc      uint32
+	sent      int64
+	startTime time.Time
+	stopC     chan struct{}
+	wg        sync.WaitGroup
+
+	// receiving statistics, only touched by receive goroutine until stopped
+	received    int64
+	initialized bool
+	baseSeq     uint32
+	maxSeq      uint16
+	cycles      uint32
+	transit     int64
+	jitter      float64 // in timestamp unit
+	maxJitter   float64
+}
+
+// portAllocator hands out even local ports for peers, rtcp uses port+1
+type portAllocator struct {
+	mutex sync.Mutex
+	next  int
+	free  []int
+}
+
+func newPortAllocator(start int) *portAllocator {
+	if start&0x01 != 0 {
+		start++
+	}
+	return &portAllocator{next: start}
+}
+
+func (pa *portAllocator) get() (port int) {
+	pa.mutex.Lock()
+	defer pa.mutex.Unlock()
+	if n := len(pa.free); n > 0 {
+		port = pa.free[n-1]
+		pa.free = pa.free[:n-1]
+		return
+	}
+	if pa.next > math.MaxUint16-1 {
+		return 0
+	}
+	port = pa.next
+	pa.next += 2
+	return
+}
+
+func (pa *portAllocator) put(port int) {
+	pa.mutex.Lock()
+	defer pa.mutex.Unlock()
+	pa.free = append(pa.free, port)
+}
+
+func (pa *portAllocator) newPeer(ip string) (p *peer, err error) {
+	localIp := net.ParseIP(ip)
+	if localIp == nil {
+		return nil, fmt.Errorf("invalid peer ip: %v", ip)
+	}
+	for i := 0; i < maxPortTries; i++ {
+		port := pa.get()
+		if port == 0 {
+			break
+		}
+		var data, ctrl *net.UDPConn
+		if data, err = net.ListenUDP("udp", &net.UDPAddr{IP: localIp, Port: port}); err != nil {
+			// port in use by others, never try it again
+			continue
+		}
+		if ctrl, err = net.ListenUDP("udp", &net.UDPAddr{IP: localIp, Port: port + 1}); err != nil {
+			data.Close()
+			continue
+		}
+		p = &peer{
+			port:     port,
+			dataConn: data,
+			ctrlConn: ctrl,
+			ssrc:     rand.Uint32(),
+			stopC:    make(chan struct{}),
+		}
+		return
+	}
+	return nil, errors.New("peer runs out of port resource")
+}
+
+func (pa *portAllocator) release(p *peer) {
+	p.close()
+	pa.put(p.port)
+}
+
+func (p *peer) start(remote string) (err error) {
+	var remoteAddr *net.UDPAddr
+	if remoteAddr, err = net.ResolveUDPAddr("udp", remote); err != nil {
+		return
+	}
+	p.startTime = time.Now()
+	p.wg.Add(3)
+	go p.sendLoop(remoteAddr)
+	go p.receiveLoop()
+	go p.drainCtrl()
+	return
+}
+
+func (p *peer) stop() {
+	close(p.stopC)
+	// unblock readers
+	p.dataConn.SetReadDeadline(time.Now())
+	p.ctrlConn.SetReadDeadline(time.Now())
+	p.wg.Wait()
+}
+
+func (p *peer) close() {
+	p.dataConn.Close()
+	p.ctrlConn.Close()
+}
+
+func (p *peer) sendLoop(remote *net.UDPAddr) {
+	defer p.wg.Done()
+	var seq = uint16(rand.Uint32())
+	var ts = rand.Uint32()
+	packet := make([]byte, rtpHeaderLength+frameSamples)
+	for i := rtpHeaderLength; i < len(packet); i++ {
+		packet[i] = pcmaSilence
+	}
+	packet[0] = rtpVersion << 6
+	packet[1] = pcmaPayloadType
+	binary.BigEndian.PutUint32(packet[8:], p.ssrc)
+
+	ticker := time.NewTicker(frameDuration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			binary.BigEndian.PutUint16(packet[2:], seq)
+			binary.BigEndian.PutUint32(packet[4:], ts)
+			if _, err := p.dataConn.WriteToUDP(packet, remote); err == nil {
+				atomic.AddInt64(&p.sent, 1)
+			}
+			seq++
+			ts += uint32(frameSamples)
+		case <-p.stopC:
+			return
+		}
+	}
+}
+
+func (p *peer) receiveLoop() {
+	defer p.wg.Done()
+	buf := make([]byte, 1500)
+	for {
+		n, _, err := p.dataConn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+		if n < rtpHeaderLength || buf[0]>>6 != rtpVersion {
+			continue
+		}
+		seq := binary.BigEndian.Uint16(buf[2:])
+		ts := binary.BigEndian.Uint32(buf[4:])
+		p.onPacket(seq, ts, time.Now())
+	}
+}
+
+// media server sends rtcp to port+1, just consume them
+func (p *peer) drainCtrl() {
+	defer p.wg.Done()
+	buf := make([]byte, 1500)
+	for {
+		if _, _, err := p.ctrlConn.ReadFromUDP(buf); err != nil {
+			return
+		}
+	}
+}
+
+func (p *peer) onPacket(seq uint16, ts uint32, arrival time.Time) {
+	p.received++
+	if !p.initialized {
+		p.initialized = true
+		p.baseSeq = uint32(seq)
+		p.maxSeq = seq
+	} else {
+		// RFC 3550 A.1, detect wrap around of sequence number
+		if delta := seq - p.maxSeq; delta < math.MaxUint16/2 {
+			if seq < p.maxSeq {
+				p.cycles += 1 << 16
+			}
+			p.maxSeq = seq
+		}
+	}
+
+	// RFC 3550 A.8, interarrival jitter
+	arrivalTs := int64(arrival.Sub(p.startTime) * pcmaClockRate / time.Second)
+	transit := arrivalTs - int64(ts)
+	if p.received > 1 {
+		d := math.Abs(float64(transit - p.transit))
+		p.jitter += (d - p.jitter) / 16
+		if p.jitter > p.maxJitter {
+			p.maxJitter = p.jitter
+		}
+	}
+	p.transit = transit
+}
+
+// expected packets number based on received sequence numbers
+func (p *peer) expected() int64 {
+	if !p.initialized {
+		return 0
+	}
+	return int64(p.cycles+uint32(p.maxSeq)) - int64(p.baseSeq) + 1
+}
+
+func jitterToDuration(j float64) time.Duration {
+	return time.Duration(j * float64(time.Second) / pcmaClockRate)
+}
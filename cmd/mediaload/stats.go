@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// stats aggregates all sessions' results, counters are updated atomically by session goroutines
+type stats struct {
+	created, started, stopped, failed, active int64
+
+	mutex          sync.Mutex
+	prepareLatency []time.Duration
+	setupLatency   []time.Duration // prepare + start
+	sent, received int64
+	expected       int64
+	jitterSum      time.Duration
+	maxJitter      time.Duration
+	nbPeer         int64
+}
+
+func newStats() *stats {
+	return &stats{}
+}
+
+func (s *stats) addLatency(prepare, setup time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.prepareLatency = append(s.prepareLatency, prepare)
+	s.setupLatency = append(s.setupLatency, setup)
+}
+
+// addPeer merges rtp statistics of a stopped peer
+func (s *stats) addPeer(p *peer) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.sent += atomic.LoadInt64(&p.sent)
+	s.received += p.received
+	s.expected += p.expected()
+	s.jitterSum += jitterToDuration(p.jitter)
+	if mj := jitterToDuration(p.maxJitter); mj > s.maxJitter {
+		s.maxJitter = mj
+	}
+	s.nbPeer++
+}
+
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(float64(len(sorted)-1) * p)
+	return sorted[i]
+}
+
+func latencySummary(samples []time.Duration) string {
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return fmt.Sprintf("p50=%v p95=%v p99=%v max=%v",
+		percentile(sorted, 0.5).Round(time.Microsecond), percentile(sorted, 0.95).Round(time.Microsecond),
+		percentile(sorted, 0.99).Round(time.Microsecond), percentile(sorted, 1).Round(time.Microsecond))
+}
+
+// reporter periodically prints client side stats along with server side metrics
+type reporter struct {
+	st        *stats
+	scraper   *metricsScraper
+	startTime time.Time
+}
+
+func newReporter(st *stats, metricsUrl string) *reporter {
+	rp := &reporter{
+		st:        st,
+		startTime: time.Now(),
+	}
+	if metricsUrl != "" {
+		rp.scraper = newMetricsScraper(metricsUrl)
+	}
+	return rp
+}
+
+func (rp *reporter) run(ctx context.Context, period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			rp.report(false)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (rp *reporter) report(final bool) {
+	st := rp.st
+	var sb strings.Builder
+	title := "report"
+	if final {
+		title = "final report"
+	}
+	fmt.Fprintf(&sb, "==== %v at %v ====\n", title, time.Since(rp.startTime).Round(time.Second))
+	fmt.Fprintf(&sb, "sessions: created=%v started=%v stopped=%v failed=%v active=%v\n",
+		atomic.LoadInt64(&st.created), atomic.LoadInt64(&st.started), atomic.LoadInt64(&st.stopped),
+		atomic.LoadInt64(&st.failed), atomic.LoadInt64(&st.active))
+
+	st.mutex.Lock()
+	fmt.Fprintf(&sb, "prepare latency: %v\n", latencySummary(st.prepareLatency))
+	fmt.Fprintf(&sb, "setup latency:   %v\n", latencySummary(st.setupLatency))
+	if st.nbPeer > 0 {
+		var loss float64
+		if st.expected > 0 {
+			loss = float64(st.expected-st.received) * 100 / float64(st.expected)
+		}
+		fmt.Fprintf(&sb, "rtp(%v finished peers): sent=%v received=%v expected=%v loss=%.2f%% jitter avg=%v max=%v\n",
+			st.nbPeer, st.sent, st.received, st.expected, loss,
+			(st.jitterSum / time.Duration(st.nbPeer)).Round(time.Microsecond), st.maxJitter.Round(time.Microsecond))
+	}
+	st.mutex.Unlock()
+
+	if rp.scraper != nil {
+		if metrics, err := rp.scraper.scrape(); err != nil {
+			fmt.Fprintf(&sb, "server metrics: scrape failed: %v\n", err)
+		} else {
+			fmt.Fprintf(&sb, "server metrics: %v\n", metrics)
+		}
+	}
+	fmt.Print(sb.String())
+}
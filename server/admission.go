@@ -0,0 +1,168 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/appcrash/media/server/comp"
+	"github.com/appcrash/media/server/prom"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"math"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const cpuSamplePeriod = time.Second
+
+const (
+	rejectMaxSessions            = "max_sessions"
+	rejectMaxSessionsPerInstance = "max_sessions_per_instance"
+	rejectMaxGraphNodes          = "max_graph_nodes"
+	rejectGoroutineWatermark     = "goroutine_watermark"
+	rejectCpuWatermark           = "cpu_watermark"
+)
+
+// AdmissionPolicy limits sessions created by PrepareSession, zero field means unlimited
+type AdmissionPolicy struct {
+	MaxSessions            int // sessions of the whole server
+	MaxSessionsPerInstance int // sessions created by the same instance id
+	MaxGraphNodes          int // nodes in graph of one session
+	// MaxGoroutines rejects new session if runtime.NumGoroutine exceeds it
+	MaxGoroutines int
+	// MaxCpuUsage rejects new session if process cpu usage exceeds it, 1.0 means all cpus are busy. it is only
+	// supported on linux
+	MaxCpuUsage float64
+}
+
+// admission counts sessions that are admitted but not finalized yet, rejections are returned as grpc status:
+// ResourceExhausted for quotas, InvalidArgument for oversized graph and Unavailable for overloaded server
+type admission struct {
+	policy AdmissionPolicy
+
+	mutex      sync.Mutex
+	nbSession  int
+	nbInstance map[string]int
+
+	cpuUsage uint64 // float64 bits of last sampled cpu usage
+	cancel   context.CancelFunc
+}
+
+func newAdmission(policy AdmissionPolicy) *admission {
+	a := &admission{
+		policy:     policy,
+		nbInstance: make(map[string]int),
+	}
+	// cpu usage is also reported in capacity report, so sample it whenever supported
+	if _, ok := processCpuTime(); ok {
+		var ctx context.Context
+		ctx, a.cancel = context.WithCancel(context.Background())
+		go a.sampleCpu(ctx)
+	} else if policy.MaxCpuUsage > 0 {
+		logger.Warnf("cpu watermark of admission is not supported on %v, ignored", runtime.GOOS)
+	}
+	return a
+}
+
+func (a *admission) reject(reason string, code codes.Code, format string, args ...interface{}) error {
+	prom.AdmissionRejected.WithLabelValues(reason).Inc()
+	msg := fmt.Sprintf(format, args...)
+	logger.Warnf("admission rejected: %v", msg)
+	return status.Error(code, msg)
+}
+
+// admit reserves a slot for the instance's new session, it must be released once the session is finalized
+func (a *admission) admit(instanceId string) error {
+	p := a.policy
+	if p.MaxGoroutines > 0 {
+		if n := runtime.NumGoroutine(); n > p.MaxGoroutines {
+			return a.reject(rejectGoroutineWatermark, codes.Unavailable,
+				"goroutine number %v exceeds watermark %v", n, p.MaxGoroutines)
+		}
+	}
+	if p.MaxCpuUsage > 0 {
+		if usage := a.cpu(); usage > p.MaxCpuUsage {
+			return a.reject(rejectCpuWatermark, codes.Unavailable,
+				"cpu usage %.2f exceeds watermark %.2f", usage, p.MaxCpuUsage)
+		}
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if p.MaxSessions > 0 && a.nbSession >= p.MaxSessions {
+		return a.reject(rejectMaxSessions, codes.ResourceExhausted,
+			"session number reaches max %v", p.MaxSessions)
+	}
+	if p.MaxSessionsPerInstance > 0 && a.nbInstance[instanceId] >= p.MaxSessionsPerInstance {
+		return a.reject(rejectMaxSessionsPerInstance, codes.ResourceExhausted,
+			"session number of instance(%v) reaches max %v", instanceId, p.MaxSessionsPerInstance)
+	}
+	a.nbSession++
+	a.nbInstance[instanceId]++
+	return nil
+}
+
+func (a *admission) release(instanceId string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.nbSession--
+	if a.nbInstance[instanceId]--; a.nbInstance[instanceId] <= 0 {
+		delete(a.nbInstance, instanceId)
+	}
+}
+
+// transfer moves slot of an adopted session to the adopter, quota of the adopter is not checked as the session is
+// already running
+func (a *admission) transfer(from, to string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if a.nbInstance[from]--; a.nbInstance[from] <= 0 {
+		delete(a.nbInstance, from)
+	}
+	a.nbInstance[to]++
+}
+
+// cpu returns the last sampled cpu usage, negative if it is not supported
+func (a *admission) cpu() float64 {
+	if a.cancel == nil {
+		return -1
+	}
+	return math.Float64frombits(atomic.LoadUint64(&a.cpuUsage))
+}
+
+func (a *admission) checkGraph(composer *comp.Composer) error {
+	if a.policy.MaxGraphNodes <= 0 {
+		return nil
+	}
+	if n := len(composer.GetSortedNodes()); n > a.policy.MaxGraphNodes {
+		return a.reject(rejectMaxGraphNodes, codes.InvalidArgument,
+			"graph has %v nodes, exceeds max %v", n, a.policy.MaxGraphNodes)
+	}
+	return nil
+}
+
+// sampleCpu periodically calculates cpu usage of the process from the cpu time it consumed
+func (a *admission) sampleCpu(ctx context.Context) {
+	ticker := time.NewTicker(cpuSamplePeriod)
+	defer ticker.Stop()
+	lastCpu, _ := processCpuTime()
+	lastTime := time.Now()
+	for {
+		select {
+		case <-ticker.C:
+			cpu, _ := processCpuTime()
+			now := time.Now()
+			usage := float64(cpu-lastCpu) / float64(now.Sub(lastTime)) / float64(runtime.NumCPU())
+			atomic.StoreUint64(&a.cpuUsage, math.Float64bits(usage))
+			lastCpu, lastTime = cpu, now
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (a *admission) close() {
+	if a.cancel != nil {
+		a.cancel()
+	}
+}
@@ -0,0 +1,15 @@
+package server
+
+import (
+	"golang.org/x/sys/unix"
+	"time"
+)
+
+// processCpuTime returns user and system cpu time consumed by the process
+func processCpuTime() (time.Duration, bool) {
+	var ru unix.Rusage
+	if err := unix.Getrusage(unix.RUSAGE_SELF, &ru); err != nil {
+		return 0, false
+	}
+	return time.Duration(ru.Utime.Nano() + ru.Stime.Nano()), true
+}
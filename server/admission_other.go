@@ -0,0 +1,9 @@
+//go:build !linux
+
+package server
+
+import "time"
+
+func processCpuTime() (time.Duration, bool) {
+	return 0, false
+}
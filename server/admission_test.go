@@ -0,0 +1,60 @@
+package server_test
+
+import (
+	"context"
+	"github.com/appcrash/media/server"
+	"github.com/appcrash/media/server/rpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestAdmission(t *testing.T) {
+	client := startTestServer(t, &server.Config{
+		RtpIp:          "127.0.0.1",
+		PortQuarantine: -1,
+		Admission: server.AdmissionPolicy{
+			MaxSessions:            2,
+			MaxSessionsPerInstance: 1,
+			MaxGraphNodes:          1,
+		},
+	}).client
+	ctx := context.Background()
+
+	prepare := func(instanceId, graph string) (*rpc.Session, error) {
+		return client.PrepareSession(ctx, &rpc.CreateParam{
+			PeerIp:   "127.0.0.1",
+			PeerPort: 44040,
+			Codecs: []*rpc.CodecInfo{{
+				PayloadNumber: 8,
+				PayloadType:   rpc.CodecType_PCM_ALAW,
+			}},
+			GraphDesc:  graph,
+			InstanceId: instanceId,
+		})
+	}
+	expect := func(err error, code codes.Code) {
+		t.Helper()
+		if status.Code(err) != code {
+			t.Fatalf("expect %v but got: %v", code, err)
+		}
+	}
+
+	s1, err := prepare("admission_a", "[echo]")
+	expect(err, codes.OK)
+	_, err = prepare("admission_a", "[echo]")
+	expect(err, codes.ResourceExhausted)
+	_, err = prepare("admission_b", "[rtp_src]->[rtp_sink]")
+	expect(err, codes.InvalidArgument)
+	_, err = prepare("admission_b", "[echo]")
+	expect(err, codes.OK)
+	_, err = prepare("admission_c", "[echo]")
+	expect(err, codes.ResourceExhausted)
+
+	// stopped session releases its slot
+	if _, err = client.StopSession(ctx, &rpc.StopParam{SessionId: s1.SessionId}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = prepare("admission_c", "[echo]")
+	expect(err, codes.OK)
+}
@@ -0,0 +1,198 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"os"
+	"strings"
+)
+
+// TLSConfig enables tls of grpc server and http gateway, client certificates are required and verified by
+// ClientCAFile if it is not empty, i.e. mutual tls
+type TLSConfig struct {
+	CertFile, KeyFile string
+	ClientCAFile      string
+}
+
+func (c *TLSConfig) tlsConfig() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	tc := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if c.ClientCAFile != "" {
+		pem, err := os.ReadFile(c.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no valid certificate in %v", c.ClientCAFile)
+		}
+		tc.ClientCAs = pool
+		tc.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tc, nil
+}
+
+func (c *TLSConfig) serverOption() (grpc.ServerOption, error) {
+	tc, err := c.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	return grpc.Creds(credentials.NewTLS(tc)), nil
+}
+
+// Identity is the authenticated caller of rpc, it can only operate sessions created by its instance unless it is
+// an admin
+type Identity struct {
+	InstanceId string
+	Admin      bool
+}
+
+// Authenticator maps credentials of rpc call to identity, rpc is rejected with Unauthenticated if error returned
+type Authenticator interface {
+	Authenticate(ctx context.Context) (*Identity, error)
+}
+
+// TokenAuthenticator maps token in "authorization" metadata to identity, the "Bearer " prefix is optional
+type TokenAuthenticator map[string]Identity
+
+func (ta TokenAuthenticator) Authenticate(ctx context.Context) (*Identity, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, errors.New("missing authorization token")
+	}
+	token := strings.TrimPrefix(values[0], "Bearer ")
+	if id, ok := ta[token]; ok {
+		return &id, nil
+	}
+	return nil, errors.New("invalid authorization token")
+}
+
+// CertAuthenticator maps subject common name of client certificate to identity, it requires mutual tls so that the
+// certificate is verified
+type CertAuthenticator map[string]Identity
+
+func (ca CertAuthenticator) Authenticate(ctx context.Context) (*Identity, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, errors.New("unknown peer")
+	}
+	ti, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(ti.State.VerifiedChains) == 0 || len(ti.State.VerifiedChains[0]) == 0 {
+		return nil, errors.New("no verified client certificate")
+	}
+	cn := ti.State.VerifiedChains[0][0].Subject.CommonName
+	if id, ok := ca[cn]; ok {
+		return &id, nil
+	}
+	return nil, fmt.Errorf("unknown client certificate: %v", cn)
+}
+
+// ChainAuthenticator tries authenticators in order, the first succeeded one wins
+type ChainAuthenticator []Authenticator
+
+func (cha ChainAuthenticator) Authenticate(ctx context.Context) (id *Identity, err error) {
+	err = errors.New("no authenticator")
+	for _, a := range cha {
+		if id, err = a.Authenticate(ctx); err == nil {
+			return
+		}
+	}
+	return
+}
+
+type identityKey struct{}
+
+// identityFrom returns nil if authentication is disabled
+func identityFrom(ctx context.Context) *Identity {
+	id, _ := ctx.Value(identityKey{}).(*Identity)
+	return id
+}
+
+func authenticate(a Authenticator, ctx context.Context) (context.Context, error) {
+	id, err := a.Authenticate(ctx)
+	if err != nil {
+		logger.Warnf("rpc authentication failed: %v", err)
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return context.WithValue(ctx, identityKey{}, id), nil
+}
+
+type authServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func authInterceptors(a Authenticator) []grpc.ServerOption {
+	unary := func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(a, ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+	stream := func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		ctx, err := authenticate(a, ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authServerStream{ServerStream: ss, ctx: ctx})
+	}
+	return []grpc.ServerOption{grpc.UnaryInterceptor(unary), grpc.StreamInterceptor(stream)}
+}
+
+// authorizeInstance checks caller can act as the instance
+func authorizeInstance(ctx context.Context, instanceId string) error {
+	id := identityFrom(ctx)
+	if id == nil || id.Admin || id.InstanceId == instanceId {
+		return nil
+	}
+	return status.Errorf(codes.PermissionDenied, "identity of instance(%v) can not act as instance(%v)",
+		id.InstanceId, instanceId)
+}
+
+func authorizeAdmin(ctx context.Context) error {
+	if id := identityFrom(ctx); id != nil && !id.Admin {
+		return status.Error(codes.PermissionDenied, "admin identity is required")
+	}
+	return nil
+}
+
+// authorizeSession checks caller owns the session, nonexistent session is left to rpc to report
+func (srv *MediaServer) authorizeSession(ctx context.Context, sessionId string) error {
+	if id := identityFrom(ctx); id == nil || id.Admin {
+		return nil
+	}
+	sid, err := SessionIdFromString(sessionId)
+	if err != nil {
+		return nil
+	}
+	srv.sessionMutex.Lock()
+	session, ok := srv.sessionMap[sid]
+	srv.sessionMutex.Unlock()
+	if !ok {
+		return nil
+	}
+	return authorizeInstance(ctx, session.GetInstanceId())
+}
@@ -0,0 +1,197 @@
+package server_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"github.com/appcrash/media/server"
+	"github.com/appcrash/media/server/rpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	der  []byte
+}
+
+func newTestCert(t *testing.T, cn string, parent *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial, _ := rand.Int(rand.Reader, big.NewInt(1<<62))
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	signer, signerKey := tmpl, key
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return &testCert{cert: cert, key: key, der: der}
+}
+
+func (c *testCert) tlsCert() tls.Certificate {
+	return tls.Certificate{Certificate: [][]byte{c.der}, PrivateKey: c.key}
+}
+
+func (c *testCert) writeFiles(t *testing.T, dir, name string) (certFile, keyFile string) {
+	certFile, keyFile = filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key")
+	keyDer, err := x509.MarshalECPrivateKey(c.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.der}), 0600)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600)
+	return
+}
+
+func authCreateParam(instanceId string) *rpc.CreateParam {
+	return &rpc.CreateParam{
+		PeerIp:   "127.0.0.1",
+		PeerPort: 44050,
+		Codecs: []*rpc.CodecInfo{{
+			PayloadNumber: 8,
+			PayloadType:   rpc.CodecType_PCM_ALAW,
+		}},
+		GraphDesc:  "[echo]",
+		InstanceId: instanceId,
+	}
+}
+
+func expectCode(t *testing.T, err error, code codes.Code) {
+	t.Helper()
+	if status.Code(err) != code {
+		t.Fatalf("expect %v but got: %v", code, err)
+	}
+}
+
+func TestMutualTLSAuth(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCert(t, "test ca", nil)
+	caFile, _ := ca.writeFiles(t, dir, "ca")
+	certFile, keyFile := newTestCert(t, "media server", ca).writeFiles(t, dir, "server")
+	ts := startTestServer(t, &server.Config{
+		RtpIp:          "127.0.0.1",
+		PortQuarantine: -1,
+		TLS:            &server.TLSConfig{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile},
+		Authenticator: server.CertAuthenticator{
+			"client a": {InstanceId: "tls_a"},
+			"admin":    {Admin: true},
+		},
+	})
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	dial := func(cn string) rpc.MediaApiClient {
+		tc := &tls.Config{RootCAs: roots}
+		if cn != "" {
+			tc.Certificates = []tls.Certificate{newTestCert(t, cn, ca).tlsCert()}
+		}
+		conn, err := grpc.Dial(fmt.Sprintf("%v:%v", grpcIp, ts.port), grpc.WithTransportCredentials(credentials.NewTLS(tc)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		return rpc.NewMediaApiClient(conn)
+	}
+	ctx := context.Background()
+	clientA, admin := dial("client a"), dial("admin")
+
+	_, err := dial("").GetVersion(ctx, &rpc.Empty{})
+	expectCode(t, err, codes.Unavailable) // handshake fails without client certificate
+	_, err = dial("client x").GetVersion(ctx, &rpc.Empty{})
+	expectCode(t, err, codes.Unauthenticated)
+
+	_, err = clientA.PrepareSession(ctx, authCreateParam("tls_b"))
+	expectCode(t, err, codes.PermissionDenied)
+	session, err := clientA.PrepareSession(ctx, authCreateParam(""))
+	expectCode(t, err, codes.OK)
+	list, err := admin.ListSessions(ctx, &rpc.ListParam{InstanceId: "tls_a"})
+	if err != nil || len(list.Sessions) != 1 || list.Sessions[0].SessionId != session.SessionId {
+		t.Fatalf("instance id should be the identity's if not given: %v %v", list, err)
+	}
+	_, err = clientA.Drain(ctx, &rpc.DrainParam{})
+	expectCode(t, err, codes.PermissionDenied)
+	_, err = admin.StopSession(ctx, &rpc.StopParam{SessionId: session.SessionId})
+	expectCode(t, err, codes.OK)
+}
+
+func TestTokenAuth(t *testing.T) {
+	client := startTestServer(t, &server.Config{
+		RtpIp:          "127.0.0.1",
+		PortQuarantine: -1,
+		Authenticator: server.TokenAuthenticator{
+			"token_a": {InstanceId: "token_a"},
+			"token_b": {InstanceId: "token_b"},
+		},
+	}).client
+	withToken := func(token string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+	}
+	ctxA, ctxB := withToken("token_a"), withToken("token_b")
+
+	_, err := client.GetVersion(context.Background(), &rpc.Empty{})
+	expectCode(t, err, codes.Unauthenticated)
+	_, err = client.GetVersion(withToken("token_x"), &rpc.Empty{})
+	expectCode(t, err, codes.Unauthenticated)
+
+	session, err := client.PrepareSession(ctxA, authCreateParam("token_a"))
+	expectCode(t, err, codes.OK)
+	_, err = client.StopSession(ctxB, &rpc.StopParam{SessionId: session.SessionId})
+	expectCode(t, err, codes.PermissionDenied)
+	_, err = client.ExecuteAction(ctxB, &rpc.Action{SessionId: session.SessionId, Cmd: "exec"})
+	expectCode(t, err, codes.PermissionDenied)
+	_, err = client.DescribeSession(ctxB, &rpc.DescribeParam{SessionId: session.SessionId})
+	expectCode(t, err, codes.PermissionDenied)
+	list, err := client.ListSessions(ctxB, &rpc.ListParam{})
+	if err != nil || len(list.Sessions) != 0 {
+		t.Fatalf("instance should only list its own sessions: %v %v", list, err)
+	}
+
+	// system channel can only be registered as the identity's instance
+	sc, err := client.SystemChannel(ctxB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc.Send(&rpc.SystemEvent{Cmd: rpc.SystemCommand_REGISTER, InstanceId: "token_a"})
+	_, err = sc.Recv()
+	expectCode(t, err, codes.PermissionDenied)
+
+	// sessions can only be adopted by instance of the same failover group
+	_, err = client.AdoptSessions(ctxB, &rpc.AdoptParam{FromInstanceId: "token_a"})
+	expectCode(t, err, codes.PermissionDenied)
+
+	_, err = client.StopSession(ctxA, &rpc.StopParam{SessionId: session.SessionId})
+	expectCode(t, err, codes.OK)
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+	"github.com/appcrash/media/server/comp"
+	"github.com/appcrash/media/server/rpc"
+	"reflect"
+	"sort"
+)
+
+func messageTraitNames(types []comp.MessageType) (names []string) {
+	for _, t := range types {
+		if mt, ok := comp.MessageTraitOfType(t); ok {
+			names = append(names, mt.Name())
+		}
+	}
+	return
+}
+
+// nodeProperties are exported fields of node that can be configured in graph description, fields of embedded
+// structs like SessionNode are common to all nodes, so they are excluded
+func nodeProperties(typ reflect.Type) (props []*rpc.NodeProperty) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.Anonymous || !field.IsExported() {
+			continue
+		}
+		props = append(props, &rpc.NodeProperty{Name: field.Name, Type: field.Type.String()})
+	}
+	return
+}
+
+// nodeCapability makes a probe node to find out message types it accepts and offers, as they are declared by
+// methods of the node
+func nodeCapability(trait *comp.NodeTrait) *rpc.NodeCapability {
+	nc := &rpc.NodeCapability{
+		NodeType:   trait.NodeType,
+		Properties: nodeProperties(trait.Type),
+	}
+	if node := trait.FactoryFunc(); node != nil {
+		nc.Accept = messageTraitNames(node.Accept())
+		nc.Offer = messageTraitNames(node.Offer())
+	}
+	return nc
+}
+
+func messageCapabilities() (mcs []*rpc.MessageCapability) {
+	var traits []*comp.MessageTrait
+	comp.VisitMessageTrait(func(trait *comp.MessageTrait) {
+		traits = append(traits, trait)
+	})
+	for _, from := range traits {
+		mc := &rpc.MessageCapability{Name: from.Name(), TypeId: int32(from.TypeId)}
+		for _, to := range traits {
+			if from.TypeId != to.TypeId && comp.CanConvertMessage(from.TypeId, to.TypeId) {
+				mc.ConvertibleTo = append(mc.ConvertibleTo, to.Name())
+			}
+		}
+		mcs = append(mcs, mc)
+	}
+	return
+}
+
+// supportedCodecs are codecs that rtp stack has profile for
+func supportedCodecs() (codecs []rpc.CodecType) {
+	for value := range rpc.CodecType_name {
+		if c := rpc.CodecType(value); profileOfCodec(c) != "" {
+			codecs = append(codecs, c)
+		}
+	}
+	sort.Slice(codecs, func(i, j int) bool { return codecs[i] < codecs[j] })
+	return
+}
+
+func (srv *MediaServer) commandCapabilities() (ccs []*rpc.CommandCapability) {
+	for _, trait := range srv.commandTraits {
+		cc := &rpc.CommandCapability{Name: trait.CmdName}
+		switch trait.CmdTrait {
+		case CmdTraitSimple:
+			cc.Trait = rpc.CommandTraitType_SIMPLE
+		case CmdTraitPullStream:
+			cc.Trait = rpc.CommandTraitType_PULL_STREAM
+		case CmdTraitPushStream:
+			cc.Trait = rpc.CommandTraitType_PUSH_STREAM
+		}
+		ccs = append(ccs, cc)
+	}
+	return
+}
+
+// GetCapabilities reports node types, message types, codecs and commands this server supports
+func (srv *MediaServer) GetCapabilities(_ context.Context, _ *rpc.Empty) (*rpc.Capabilities, error) {
+	var traits []*comp.NodeTrait
+	comp.VisitNodeTrait(func(trait *comp.NodeTrait) {
+		traits = append(traits, trait)
+	})
+	sort.Slice(traits, func(i, j int) bool { return traits[i].NodeType < traits[j].NodeType })
+	caps := &rpc.Capabilities{
+		Version:  rpc.Version_DEFAULT,
+		Messages: messageCapabilities(),
+		Codecs:   supportedCodecs(),
+		Commands: srv.commandCapabilities(),
+	}
+	for _, trait := range traits {
+		caps.Nodes = append(caps.Nodes, nodeCapability(trait))
+	}
+	return caps, nil
+}
@@ -0,0 +1,64 @@
+package server_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/appcrash/media/server/rpc"
+	"google.golang.org/grpc"
+	"testing"
+)
+
+func TestGetCapabilities(t *testing.T) {
+	conn, err := grpc.Dial(fmt.Sprintf("%v:%v", grpcIp, grpcPort), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	caps, err := rpc.NewMediaApiClient(conn).GetCapabilities(context.Background(), &rpc.Empty{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if caps.Version != rpc.Version_DEFAULT {
+		t.Fatalf("invalid version: %v", caps.Version)
+	}
+
+	nodes := make(map[string]*rpc.NodeCapability)
+	for _, n := range caps.Nodes {
+		nodes[n.NodeType] = n
+	}
+	for _, nodeType := range []string{"chan_sink", "chan_src", "pubsub", "echo"} {
+		if nodes[nodeType] == nil {
+			t.Fatalf("node type %v should be reported", nodeType)
+		}
+	}
+	sink := nodes["chan_sink"]
+	if len(sink.Accept) != 2 || sink.Accept[0] != "raw_byte" {
+		t.Fatalf("invalid accepted message types of chan_sink: %v", sink.Accept)
+	}
+
+	messages := make(map[string]*rpc.MessageCapability)
+	for _, m := range caps.Messages {
+		messages[m.Name] = m
+	}
+	if messages["raw_byte"] == nil || messages["channel_link_request"] == nil {
+		t.Fatalf("builtin message types should be reported: %v", caps.Messages)
+	}
+
+	codecs := make(map[rpc.CodecType]bool)
+	for _, c := range caps.Codecs {
+		codecs[c] = true
+	}
+	if !codecs[rpc.CodecType_PCM_ALAW] || !codecs[rpc.CodecType_TELEPHONE_EVENT_8K] || codecs[rpc.CodecType_RAW] {
+		t.Fatalf("invalid supported codecs: %v", caps.Codecs)
+	}
+
+	commands := make(map[string]rpc.CommandTraitType)
+	for _, c := range caps.Commands {
+		commands[c.Name] = c.Trait
+	}
+	if len(commands) != 3 || commands["exec"] != rpc.CommandTraitType_SIMPLE ||
+		commands["pull_stream"] != rpc.CommandTraitType_PULL_STREAM ||
+		commands["push_stream"] != rpc.CommandTraitType_PUSH_STREAM {
+		t.Fatalf("invalid builtin commands: %v", caps.Commands)
+	}
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"context"
+	"github.com/appcrash/media/server/channel"
+	"github.com/appcrash/media/server/rpc"
+	"runtime"
+	"sort"
+	"time"
+)
+
+// capacityReport makes a snapshot of server's load
+func (srv *MediaServer) capacityReport() *rpc.CapacityReport {
+	report := &rpc.CapacityReport{
+		GraphNodes: uint32(srv.graph.NodeCount()),
+		GraphLinks: uint32(srv.graph.LinkCount()),
+		Goroutines: uint32(runtime.NumGoroutine()),
+		CpuUsage:   srv.admission.cpu(),
+		Time:       unixMilli(time.Now()),
+	}
+	var names []string
+	for name := range srv.rtpInterfaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		pool := srv.rtpInterfaces[name].portPool
+		report.Interfaces = append(report.Interfaces, &rpc.InterfaceCapacity{
+			Name:      name,
+			FreePorts: uint32(pool.Free()),
+			Capacity:  uint32(pool.Capacity()),
+		})
+	}
+	for _, session := range srv.getSessions() {
+		report.Sessions++
+		if session.GetStatus() == sessionStatusStarted {
+			report.StartedSessions++
+		}
+	}
+	ds := srv.drainState
+	ds.mutex.Lock()
+	if ds.draining {
+		report.Draining = true
+		report.DrainDeadline = unixMilli(ds.deadline)
+	}
+	ds.mutex.Unlock()
+	return report
+}
+
+// notifyCapacity sends capacity report to the instance, or all instances if instanceId is empty
+func (srv *MediaServer) notifyCapacity(instanceId string) {
+	se := &rpc.SystemEvent{
+		Cmd:        rpc.SystemCommand_CAPACITY_REPORT,
+		InstanceId: instanceId,
+		Capacity:   srv.capacityReport(),
+	}
+	sc := channel.GetSystemChannel()
+	var err error
+	if instanceId == "" {
+		err = sc.BroadcastInstance(se)
+	} else {
+		err = sc.NotifyInstance(se)
+	}
+	if err != nil {
+		logger.Debugf("failed to send capacity report: %v", err)
+	}
+}
+
+// capacityLoop broadcasts capacity report periodically until ctx is done
+func (srv *MediaServer) capacityLoop(ctx context.Context, period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			srv.notifyCapacity("")
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (srv *MediaServer) GetCapacity(_ context.Context, _ *rpc.Empty) (*rpc.CapacityReport, error) {
+	return srv.capacityReport(), nil
+}
@@ -0,0 +1,73 @@
+package channel
+
+import (
+	"errors"
+	"github.com/appcrash/media/server/prom"
+	"github.com/appcrash/media/server/rpc"
+	"github.com/prometheus/client_golang/prometheus"
+	"sync/atomic"
+	"time"
+)
+
+var ErrAckTimeout = errors.New("server channel: instance doesn't ack event in time")
+
+type pendingEvent struct {
+	se      *rpc.SystemEvent
+	timer   *time.Timer
+	resultC chan error
+}
+
+// NotifyInstanceWithAck NONBLOCK send event to instance, the returned channel reports nil once instance acks it, or
+// ErrAckTimeout if no ack received in timeout. the event is replayed if the instance reconnects before timeout, so it
+// is delivered at least once and instance should deduplicate it by ack id.
+func (sc *Channel) NotifyInstanceWithAck(se *rpc.SystemEvent, timeout time.Duration) <-chan error {
+	resultC := make(chan error, 1)
+	if se.InstanceId == "" {
+		resultC <- errors.New("invalid instance id when notifying instance")
+		return resultC
+	}
+	id := atomic.AddUint64(&sc.ackIdCounter, 1)
+	se.AckId = id
+	sc.mutex.Lock()
+	sc.pending[id] = &pendingEvent{
+		se:      se,
+		timer:   time.AfterFunc(timeout, func() { sc.resolve(id, ErrAckTimeout) }),
+		resultC: resultC,
+	}
+	sc.mutex.Unlock()
+	if err := sc.NotifyInstance(se); err != nil {
+		// keep waiting as the event is replayed if instance reconnects before timeout
+		logger.Warnf("notify instance(%v) with ack(%v) failed, wait for resending: %v", se.InstanceId, id, err)
+	}
+	return resultC
+}
+
+// resolve reports result of pending event if it is not resolved yet
+func (sc *Channel) resolve(id uint64, err error) {
+	sc.mutex.Lock()
+	pe, exist := sc.pending[id]
+	delete(sc.pending, id)
+	sc.mutex.Unlock()
+	if !exist {
+		return
+	}
+	pe.timer.Stop()
+	result := "acked"
+	if err != nil {
+		result = "timeout"
+	}
+	prom.UserEventDelivery.With(prometheus.Labels{"result": result}).Inc()
+	pe.resultC <- err
+}
+
+// ack resolves pending event only if it was sent to the instance
+func (sc *Channel) ack(instanceId string, id uint64) {
+	sc.mutex.Lock()
+	pe, exist := sc.pending[id]
+	sc.mutex.Unlock()
+	if !exist || pe.se.InstanceId != instanceId {
+		logger.Debugf("instance(%v) acks unknown event(%v)", instanceId, id)
+		return
+	}
+	sc.resolve(id, nil)
+}
@@ -2,7 +2,9 @@ package channel
 
 import (
 	"fmt"
+	"github.com/appcrash/media/server/prom"
 	"github.com/appcrash/media/server/rpc"
+	"google.golang.org/protobuf/proto"
 	"sync"
 	"time"
 )
@@ -12,9 +14,16 @@ type Listener interface {
 	OnChannelEvent(e *rpc.SystemEvent)
 }
 
+// InstanceListener is optionally implemented by Listener to know an instance is lost, i.e. its rpc ends or keep-alive
+// times out. it is not invoked if the instance is replaced by re-registering.
+type InstanceListener interface {
+	OnInstanceLost(name string)
+}
+
 type InstanceState struct {
 	name                       string
 	lastSeen                   time.Time
+	reliable                   bool // instance acks events by SEQ_ACK
 	FromInstanceC, ToInstanceC chan *rpc.SystemEvent
 }
 
@@ -25,6 +34,10 @@ type Channel struct {
 	mutex            sync.Mutex
 	instanceStateMap map[string]*InstanceState
 	listeners        []Listener
+
+	ackIdCounter uint64
+	pending      map[uint64]*pendingEvent // events waiting for ack of instance
+	outboxes     map[string]*outbox
 }
 
 // singleton sys channel
@@ -38,6 +51,8 @@ const (
 func newChannel() *Channel {
 	return &Channel{
 		instanceStateMap: make(map[string]*InstanceState),
+		pending:          make(map[uint64]*pendingEvent),
+		outboxes:         make(map[string]*outbox),
 	}
 }
 
@@ -65,6 +80,12 @@ func (sc *Channel) AddListener(l Listener) {
 }
 
 func (sc *Channel) RegisterInstance(name string) (is *InstanceState, err error) {
+	return sc.ResumeInstance(name, 0)
+}
+
+// ResumeInstance registers instance with the last seq it received, unacked events are replayed once the instance
+// acks by SEQ_ACK. if media server has no outbox of the instance, i.e. restarted, the sequence continues from lastSeq.
+func (sc *Channel) ResumeInstance(name string, lastSeq uint64) (is *InstanceState, err error) {
 	if name == "" {
 		err = fmt.Errorf("invalid instance name")
 		return
@@ -89,10 +110,56 @@ func (sc *Channel) RegisterInstance(name string) (is *InstanceState, err error)
 	}
 
 	sc.instanceStateMap[name] = is
+	if ob, exist := sc.outboxes[name]; exist {
+		ob.disconnectedAt = time.Time{}
+	} else {
+		sc.outboxes[name] = &outbox{nextSeq: lastSeq + 1}
+	}
+	sc.sweepOutboxes()
 	go sc.startReceiveLoop(name)
 	return
 }
 
+// UnregisterInstance closes the instance state if it is not replaced by re-registering yet
+func (sc *Channel) UnregisterInstance(is *InstanceState) {
+	sc.mutex.Lock()
+	current, exist := sc.instanceStateMap[is.name]
+	if !exist || current != is {
+		sc.mutex.Unlock()
+		return
+	}
+	is.close()
+	delete(sc.instanceStateMap, is.name)
+	if ob, exist := sc.outboxes[is.name]; exist {
+		ob.disconnectedAt = time.Now()
+	}
+	sc.sweepOutboxes()
+	listeners := sc.listeners
+	sc.mutex.Unlock()
+
+	for _, l := range listeners {
+		if il, ok := l.(InstanceListener); ok {
+			il.OnInstanceLost(is.name)
+		}
+	}
+}
+
+// FromInstance NONBLOCK forwards event received from the instance to the channel, returns false if the instance state
+// is closed
+func (sc *Channel) FromInstance(is *InstanceState, se *rpc.SystemEvent) bool {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+	if is.FromInstanceC == nil {
+		return false
+	}
+	select {
+	case is.FromInstanceC <- se:
+	default:
+		logger.Errorf("server channel: instance %v sends too fast, drop event", is.name)
+	}
+	return true
+}
+
 func (sc *Channel) HasInstance(name string) (exist bool) {
 	sc.mutex.Lock()
 	_, exist = sc.instanceStateMap[name]
@@ -100,43 +167,59 @@ func (sc *Channel) HasInstance(name string) (exist bool) {
 	return
 }
 
-// NotifyInstance NONBLOCK send event to instance
+// NotifyInstance NONBLOCK send event to instance. events except KEEPALIVE and CAPACITY_REPORT are sequenced and
+// kept until acked, so they are retransmitted even if error returned because of full send queue or disconnection,
+// as long as the instance acks events by SEQ_ACK.
 func (sc *Channel) NotifyInstance(se *rpc.SystemEvent) (err error) {
 	if se.InstanceId == "" {
 		return fmt.Errorf("invalid instance id when notifying instance")
 	}
+	// channels are closed with mutex held, so send with it to avoid sending to closed channel
 	sc.mutex.Lock()
-	if is, exist := sc.instanceStateMap[se.InstanceId]; exist {
-		sc.mutex.Unlock()
-		select {
-		case is.ToInstanceC <- se:
-		default:
-			err = fmt.Errorf("server channel: send to instance %v failed", se.InstanceId)
+	defer sc.mutex.Unlock()
+	return sc.notify(se.InstanceId, se)
+}
+
+// BroadcastInstance NONBLOCK send event to all instances, each instance gets a copy with its own seq
+func (sc *Channel) BroadcastInstance(se *rpc.SystemEvent) (err error) {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+	for name := range sc.instanceStateMap {
+		if e := sc.notify(name, proto.Clone(se).(*rpc.SystemEvent)); e != nil {
+			err = fmt.Errorf("server channel: broadcast to instance %v failed: %v", name, e)
 		}
-	} else {
-		sc.mutex.Unlock()
-		err = fmt.Errorf("server channel: no such instance %v when send to instance", se.InstanceId)
 	}
 	return
 }
 
-// BroadcastInstance NONBLOCK send event to all instances
-func (sc *Channel) BroadcastInstance(se *rpc.SystemEvent) (err error) {
-	var instances []*InstanceState
-	sc.mutex.Lock()
-	for _, is := range sc.instanceStateMap {
-		instances = append(instances, is)
+// notify must be called with mutex held
+func (sc *Channel) notify(instanceId string, se *rpc.SystemEvent) error {
+	var oe *outboxEvent
+	if ob, exist := sc.outboxes[instanceId]; exist && sequenced(se.Cmd) {
+		oe = sc.enqueue(instanceId, ob, se)
 	}
-	sc.mutex.Unlock()
-	for _, is := range instances {
-		select {
-		case is.ToInstanceC <- se:
-		default:
-			err = fmt.Errorf("server channel: broadcast to instance %v failed", is.name)
-			return
+	is, exist := sc.instanceStateMap[instanceId]
+	if !exist {
+		if oe != nil {
+			return fmt.Errorf("server channel: instance %v is disconnected, event is kept for replay", instanceId)
 		}
+		return fmt.Errorf("server channel: no such instance %v when send to instance", instanceId)
 	}
-	return
+	select {
+	case is.ToInstanceC <- se:
+		if oe != nil {
+			oe.sentAt = time.Now()
+		}
+	default:
+		prom.SystemChannelQueueFull.WithLabelValues(instanceId).Inc()
+		return fmt.Errorf("server channel: send to instance %v failed", instanceId)
+	}
+	return nil
+}
+
+// sequenced tells whether events of the command are worth replaying, periodic ones are stale once lost
+func sequenced(cmd rpc.SystemCommand) bool {
+	return cmd != rpc.SystemCommand_KEEPALIVE && cmd != rpc.SystemCommand_CAPACITY_REPORT
 }
 
 func (sc *Channel) startReceiveLoop(instanceId string) {
@@ -148,11 +231,13 @@ func (sc *Channel) startReceiveLoop(instanceId string) {
 		sc.mutex.Unlock()
 		return
 	}
+	fromC := is.FromInstanceC
 	sc.mutex.Unlock()
 	ticker := time.NewTicker(KeepAliveCheckDuration)
+	defer ticker.Stop()
 	for {
 		select {
-		case se, more := <-is.FromInstanceC:
+		case se, more := <-fromC:
 			if !more {
 				logger.Infof("instance %v from-instance channel closed", instanceId)
 				return
@@ -168,6 +253,12 @@ func (sc *Channel) startReceiveLoop(instanceId string) {
 					Event:      se.Event,
 				}
 				sc.NotifyInstance(&rse)
+			case rpc.SystemCommand_USER_EVENT_ACK:
+				is.lastSeen = time.Now()
+				sc.ack(instanceId, se.AckId)
+			case rpc.SystemCommand_SEQ_ACK:
+				is.lastSeen = time.Now()
+				sc.ackSeq(is, se.Seq)
 			default:
 				handled = false
 			}
@@ -187,12 +278,10 @@ func (sc *Channel) startReceiveLoop(instanceId string) {
 		case <-ticker.C:
 			if time.Since(is.lastSeen) > KeepAliveTimeout {
 				logger.Errorf("server channel for instance(%v) keep-alive times out, close it", instanceId)
-				sc.mutex.Lock()
-				is.close()
-				delete(sc.instanceStateMap, instanceId)
-				sc.mutex.Unlock()
+				sc.UnregisterInstance(is)
 				return
 			}
+			sc.retransmitExpired(is)
 		}
 
 	}
@@ -38,6 +38,112 @@ func TestKeepaliveTimeout(t *testing.T) {
 	}
 }
 
+func TestNotifyWithAck(t *testing.T) {
+	const instanceId = "ackInstance"
+	sc := channel.GetSystemChannel()
+	is, err := sc.RegisterInstance(instanceId)
+	if err != nil {
+		t.Fatal("register instance failed")
+	}
+	defer func() { sc.UnregisterInstance(is) }()
+	event := func() *rpc.SystemEvent {
+		return &rpc.SystemEvent{Cmd: rpc.SystemCommand_USER_EVENT, InstanceId: instanceId, Event: "hello"}
+	}
+
+	// received by instance but never acked
+	resultC := sc.NotifyInstanceWithAck(event(), 100*time.Millisecond)
+	received := <-is.ToInstanceC
+	sc.FromInstance(is, &rpc.SystemEvent{Cmd: rpc.SystemCommand_SEQ_ACK, InstanceId: instanceId, Seq: received.Seq})
+	if err = <-resultC; err != channel.ErrAckTimeout {
+		t.Fatalf("expect ack timeout but got: %v", err)
+	}
+
+	// resent after re-registering, then acked
+	resultC = sc.NotifyInstanceWithAck(event(), 2*time.Second)
+	first := <-is.ToInstanceC
+	if is, err = sc.RegisterInstance(instanceId); err != nil {
+		t.Fatal("re-register instance failed")
+	}
+	sc.FromInstance(is, &rpc.SystemEvent{Cmd: rpc.SystemCommand_SEQ_ACK, InstanceId: instanceId, Seq: received.Seq})
+	resent := <-is.ToInstanceC
+	if first.AckId == 0 || resent.AckId != first.AckId {
+		t.Fatalf("event should be resent with the same ack id: %v %v", first, resent)
+	}
+	sc.FromInstance(is, &rpc.SystemEvent{Cmd: rpc.SystemCommand_USER_EVENT_ACK, InstanceId: instanceId,
+		AckId: resent.AckId})
+	select {
+	case err = <-resultC:
+		if err != nil {
+			t.Fatalf("event should be acked: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("ack is not reported")
+	}
+}
+
+func TestReplayOnReconnect(t *testing.T) {
+	const instanceId = "replayInstance"
+	sc := channel.GetSystemChannel()
+	ack := func(is *channel.InstanceState, seq uint64) {
+		sc.FromInstance(is, &rpc.SystemEvent{Cmd: rpc.SystemCommand_SEQ_ACK, InstanceId: instanceId, Seq: seq})
+	}
+	notify := func(event string) error {
+		return sc.NotifyInstance(&rpc.SystemEvent{Cmd: rpc.SystemCommand_USER_EVENT, InstanceId: instanceId,
+			Event: event})
+	}
+	expect := func(is *channel.InstanceState, seq uint64, event string) {
+		t.Helper()
+		select {
+		case se := <-is.ToInstanceC:
+			if se.Seq != seq || se.Event != event {
+				t.Fatalf("expect event %v with seq %v but got: %v", event, seq, se)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("event %v not received", event)
+		}
+	}
+
+	is, err := sc.ResumeInstance(instanceId, 0)
+	if err != nil {
+		t.Fatal("register instance failed")
+	}
+	ack(is, 0)
+	for _, event := range []string{"a", "b", "c"} {
+		if err = notify(event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expect(is, 1, "a")
+	expect(is, 2, "b")
+	expect(is, 3, "c")
+	ack(is, 1)
+	time.Sleep(50 * time.Millisecond)
+
+	// events are kept when instance is disconnected, then replayed with unacked ones after reconnecting
+	sc.UnregisterInstance(is)
+	if err = notify("d"); err == nil {
+		t.Fatal("notify disconnected instance should return error")
+	}
+	if is, err = sc.ResumeInstance(instanceId, 1); err != nil {
+		t.Fatal("re-register instance failed")
+	}
+	defer func() { sc.UnregisterInstance(is) }()
+	ack(is, 2)
+	expect(is, 3, "c")
+	expect(is, 4, "d")
+
+	// sequence continues from the instance's if media server has no record of it, i.e. restarted
+	fresh, err := sc.ResumeInstance(instanceId+"_fresh", 100)
+	if err != nil {
+		t.Fatal("register instance failed")
+	}
+	defer sc.UnregisterInstance(fresh)
+	sc.NotifyInstance(&rpc.SystemEvent{Cmd: rpc.SystemCommand_USER_EVENT, InstanceId: instanceId + "_fresh"})
+	if se := <-fresh.ToInstanceC; se.Seq != 101 {
+		t.Fatalf("sequence should continue from the instance's: %v", se)
+	}
+}
+
 func ExampleBroadcast() {
 	const n = 5
 	wg := &sync.WaitGroup{}
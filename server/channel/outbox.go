@@ -0,0 +1,111 @@
+package channel
+
+import (
+	"github.com/appcrash/media/server/prom"
+	"github.com/appcrash/media/server/rpc"
+	"time"
+)
+
+const (
+	// RetransmitBufferSize is the max number of unacked events kept for each instance, the oldest one is dropped
+	// when it is full
+	RetransmitBufferSize = 1024
+	// RetransmitInterval is how long an unacked event waits before sent again
+	RetransmitInterval = KeepAliveCheckDuration * 2
+	// OutboxExpiry is how long unacked events of a disconnected instance are kept
+	OutboxExpiry = time.Minute
+)
+
+type outboxEvent struct {
+	se     *rpc.SystemEvent
+	sentAt time.Time // zero if never sent
+}
+
+// outbox keeps sequence and unacked events of an instance across its system channel rpc, so that events can be
+// replayed when the instance reconnects
+type outbox struct {
+	nextSeq        uint64
+	unacked        []*outboxEvent // in order of seq
+	disconnectedAt time.Time      // zero if connected
+}
+
+// enqueue assigns seq to event and keeps it until acked, must be called with mutex held
+func (sc *Channel) enqueue(instanceId string, ob *outbox, se *rpc.SystemEvent) *outboxEvent {
+	se.Seq = ob.nextSeq
+	ob.nextSeq++
+	if len(ob.unacked) >= RetransmitBufferSize {
+		ob.unacked[0] = nil
+		ob.unacked = ob.unacked[1:]
+		prom.SystemChannelOverflow.WithLabelValues(instanceId).Inc()
+	}
+	oe := &outboxEvent{se: se}
+	ob.unacked = append(ob.unacked, oe)
+	prom.SystemChannelUnacked.WithLabelValues(instanceId).Set(float64(len(ob.unacked)))
+	return oe
+}
+
+// ackSeq drops events acked by instance. the first ack of an instance state turns on retransmission, and replays
+// all unacked events immediately as they may be lost with previous rpc.
+func (sc *Channel) ackSeq(is *InstanceState, seq uint64) {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+	ob := sc.outboxes[is.name]
+	if ob == nil {
+		return
+	}
+	n := 0
+	for n < len(ob.unacked) && ob.unacked[n].se.Seq <= seq {
+		n++
+	}
+	ob.unacked = ob.unacked[n:]
+	prom.SystemChannelUnacked.WithLabelValues(is.name).Set(float64(len(ob.unacked)))
+	if !is.reliable {
+		is.reliable = true
+		sc.retransmit(is, ob, true)
+	}
+}
+
+// retransmitExpired sends events that are not acked in RetransmitInterval again
+func (sc *Channel) retransmitExpired(is *InstanceState) {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+	if ob := sc.outboxes[is.name]; ob != nil && is.reliable {
+		sc.retransmit(is, ob, false)
+	}
+}
+
+// retransmit must be called with mutex held, it stops once send queue is full
+func (sc *Channel) retransmit(is *InstanceState, ob *outbox, all bool) {
+	if is.ToInstanceC == nil {
+		return
+	}
+	now := time.Now()
+	for _, oe := range ob.unacked {
+		if !all && now.Sub(oe.sentAt) < RetransmitInterval {
+			continue
+		}
+		select {
+		case is.ToInstanceC <- oe.se:
+			if !oe.sentAt.IsZero() {
+				prom.SystemChannelRetransmit.WithLabelValues(is.name).Inc()
+			}
+			oe.sentAt = now
+		default:
+			prom.SystemChannelQueueFull.WithLabelValues(is.name).Inc()
+			return
+		}
+	}
+}
+
+// sweepOutboxes drops outboxes of instances disconnected longer than OutboxExpiry, must be called with mutex held
+func (sc *Channel) sweepOutboxes() {
+	for name, ob := range sc.outboxes {
+		if !ob.disconnectedAt.IsZero() && time.Since(ob.disconnectedAt) > OutboxExpiry {
+			delete(sc.outboxes, name)
+			prom.SystemChannelUnacked.DeleteLabelValues(name)
+			prom.SystemChannelOverflow.DeleteLabelValues(name)
+			prom.SystemChannelQueueFull.DeleteLabelValues(name)
+			prom.SystemChannelRetransmit.DeleteLabelValues(name)
+		}
+	}
+}
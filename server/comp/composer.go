@@ -7,6 +7,7 @@ import (
 	"github.com/appcrash/media/server/utils"
 	"reflect"
 	"strings"
+	"sync"
 )
 
 var (
@@ -33,7 +34,8 @@ const (
 // Node.OnExit
 type Composer struct {
 	sessionId  string
-	instanceId string
+	ownerMutex sync.RWMutex
+	instanceId string // changed when session is adopted by another instance
 
 	gt             *nmd.GraphTopology
 	nodeSortedList []SessionAware // topographical sorted nodes, first one has no receiver
@@ -41,9 +43,18 @@ type Composer struct {
 
 	initiator  CommandInitiator
 	linkPoints []LinkPoint
+	links      []ComposedLink
 	nodeExited bool // ensure node UnInit called only once
 }
 
+// ComposedLink is a link created by composer, with the message trait agreed by both sides
+type ComposedLink struct {
+	Sender, Receiver SessionAware // Receiver is nil if it is a node of other scope
+	ReceiverScope    string
+	ReceiverName     string
+	LinkPoint        LinkPoint
+}
+
 func NewSessionComposer(sessionId, instanceId string) *Composer {
 	sc := &Composer{
 		sessionId:  sessionId,
@@ -65,13 +76,28 @@ func (c *Composer) GetSessionId() string {
 }
 
 func (c *Composer) GetInstanceId() string {
+	c.ownerMutex.RLock()
+	defer c.ownerMutex.RUnlock()
 	return c.instanceId
 }
 
+// SetInstanceId changes owner of the session, nodes send events to the new owner since then
+func (c *Composer) SetInstanceId(instanceId string) {
+	c.ownerMutex.Lock()
+	defer c.ownerMutex.Unlock()
+	c.instanceId = instanceId
+}
+
 func (c *Composer) GetNode(name string) SessionAware {
 	return c.nodeMap[name]
 }
 
+// isExternal tells the node is in other scope(i.e. [mixer@room1]), it is not created by composer but only linked to,
+// whether it is hosted by this media server or a peer
+func (c *Composer) isExternal(nd *nmd.NodeDef) bool {
+	return nd.Scope != "" && nd.Scope != c.sessionId
+}
+
 func (c *Composer) ParseGraphDescription(desc string) (err error) {
 	gt := nmd.NewGraphTopology()
 	err = gt.ParseGraph(c.sessionId, desc, filterGatewayNode)
@@ -91,6 +117,25 @@ func (c *Composer) Connect(sender, receiver SessionAware, preferredOffer []Messa
 	return
 }
 
+// overflowStreamable is implemented by nodes embedding SessionNode, it creates links with overflow policy
+type overflowStreamable interface {
+	StreamToWithOverflow(session, name string, preferredOffer []MessageType,
+		overflow event.OverflowPolicy) (LinkPoint, error)
+}
+
+// streamTo links sender to the other node, only nodes implementing overflowStreamable can have overflow policy
+// other than event.OverflowTimeout
+func streamTo(sender SessionAware, session, name string, preferredOffer []MessageType,
+	overflow event.OverflowPolicy) (LinkPoint, error) {
+	if overflow == event.OverflowTimeout {
+		return sender.StreamTo(session, name, preferredOffer)
+	}
+	if node, ok := sender.(overflowStreamable); ok {
+		return node.StreamToWithOverflow(session, name, preferredOffer, overflow)
+	}
+	return nil, fmt.Errorf("node %v can not link with overflow policy %v", sender.GetNodeName(), overflow)
+}
+
 // currently, only allow gateway nodes to create loops in the graph, all you have to do is name the node with
 // "gateway" suffix. gateway node diffs from default filter node by acting as a message exchanger to outside,
 // so a node or a sub-graph can write message to gateway and read from it at the same time.
@@ -127,19 +172,37 @@ func (c *Composer) connectNodes(graph *event.Graph) (lps []LinkPoint, err error)
 	// their input message type, which means message types propagate from senders(at the end of sorted list) to
 	// receivers(at the start of sorted list), so iterate the sorted list reversely until all message types are
 	// determined as required by link creation
-	for i := len(c.nodeSortedList) - 1; i >= 0; i-- {
+	for i := len(nodeDefs) - 1; i >= 0; i-- {
 		var slps []LinkPoint = nil
-		sender := c.nodeSortedList[i]
-		for _, receiverDef := range nodeDefs[i].Deps {
-			receiver := c.nodeMap[receiverDef.Name]
+		if c.isExternal(nodeDefs[i]) {
+			continue
+		}
+		sender := c.nodeMap[nodeDefs[i].Name]
+		for j, receiverDef := range nodeDefs[i].Deps {
+			var receiver SessionAware
+			overflow := event.OverflowTimeout
+			if name := nodeDefs[i].DepOverflow[j]; name != "" {
+				var ok bool
+				if overflow, ok = event.ParseOverflowPolicy(name); !ok {
+					err = fmt.Errorf("unknown overflow policy %v of link %v -> %v", name, sender.GetNodeName(),
+						receiverDef.Name)
+					return
+				}
+			}
 			// TODO: nmd language add support for specifying preferred offer
 			// TODO: use ssa to analyze Offer() of every node, check the message type is statically or dynamically defined
-			if lp, err = c.Connect(sender, receiver, nil); err != nil {
-				return
+			if c.isExternal(receiverDef) {
+				lp, err = streamTo(sender, receiverDef.Scope, receiverDef.Name, sender.Offer(), overflow)
 			} else {
-				lps = append(lps, lp)
-				slps = append(slps, lp)
+				receiver = c.nodeMap[receiverDef.Name]
+				lp, err = streamTo(sender, receiver.GetNodeScope(), receiver.GetNodeName(), sender.Offer(), overflow)
+			}
+			if err != nil {
+				return
 			}
+			lps = append(lps, lp)
+			slps = append(slps, lp)
+			c.links = append(c.links, ComposedLink{sender, receiver, receiverDef.Scope, receiverDef.Name, lp})
 		}
 		// the sender has created link points, check the node's field and try to inject them to field variables
 		value := reflect.ValueOf(sender).Elem()
@@ -170,8 +233,15 @@ func (c *Composer) ComposeNodes(graph *event.Graph) (err error) {
 		}
 	}()
 
-	// create node instances
+	// create node instances, nodes of other scopes only accept links from this session
 	for _, n := range nodeDefs {
+		if c.isExternal(n) {
+			if len(n.Deps) > 0 {
+				err = fmt.Errorf("node %v of scope %v can not send to this session", n.Name, n.Scope)
+				return
+			}
+			continue
+		}
 		n.Props = append(n.Props, &nmd.NodeProp{
 			Key:   "Name",
 			Type:  "str",
@@ -215,6 +285,11 @@ func (c *Composer) GetSortedNodes() (ni []*nmd.NodeDef) {
 	return c.gt.GetSortedNodeDefs()
 }
 
+// GetLinks returns links created when composing nodes
+func (c *Composer) GetLinks() []ComposedLink {
+	return c.links
+}
+
 func (c *Composer) GetCommandInitiator() CommandInitiator {
 	return c.initiator
 }
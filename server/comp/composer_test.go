@@ -150,11 +150,14 @@ func initComposer() {
 }
 
 func composeIt(session, gd string) (*comp.Composer, error) {
+	return composeItOn(event.NewEventGraph(), session, gd)
+}
+
+func composeItOn(graph *event.Graph, session, gd string) (*comp.Composer, error) {
 	c := comp.NewSessionComposer(session, "")
 	if err := c.ParseGraphDescription(gd); err != nil {
 		return nil, fmt.Errorf("parse graph failed: %v", gd)
 	}
-	graph := event.NewEventGraph()
 	if err := c.ComposeNodes(graph); err != nil {
 		return nil, err
 	}
@@ -184,6 +187,92 @@ func TestComposerBasic(t *testing.T) {
 	}
 }
 
+func TestComposerLinks(t *testing.T) {
+	gd := `[input:chan_src] -> [pubsub] -> {[output1:chan_sink],[output2:chan_sink]};`
+	c := comp.NewSessionComposer("link_session", "")
+	if err := c.ParseGraphDescription(gd); err != nil {
+		t.Fatal(err)
+	}
+	graph := event.NewEventGraph()
+	if err := c.ComposeNodes(graph); err != nil {
+		t.Fatal(err)
+	}
+	links := make(map[string]string)
+	for _, l := range c.GetLinks() {
+		links[l.Sender.GetNodeName()+"->"+l.Receiver.GetNodeName()] = l.LinkPoint.MessageTrait().Name()
+	}
+	if len(links) != 3 || links["input->pubsub"] == "" || links["pubsub->output1"] == "" ||
+		links["pubsub->output2"] == "" {
+		t.Fatalf("invalid composed links: %v", links)
+	}
+	si := graph.DescribeScope("link_session")
+	if si == nil || len(si.Nodes) != 4 || len(si.Links) != 3 {
+		t.Fatalf("invalid scope info: %+v", si)
+	}
+}
+
+func TestComposerLinkOverflow(t *testing.T) {
+	gd := `[input:chan_src] -> [pubsub] -> {[output1:chan_sink overflow=drop_oldest],[output2:chan_sink]};`
+	graph := event.NewEventGraph()
+	if _, err := composeItOn(graph, "overflow_session", gd); err != nil {
+		t.Fatal(err)
+	}
+	si := graph.DescribeScope("overflow_session")
+	if si == nil || len(si.Links) != 3 {
+		t.Fatalf("invalid scope info: %+v", si)
+	}
+	for _, l := range si.Links {
+		expected := event.OverflowTimeout
+		if l.To.Name == "output1" {
+			expected = event.OverflowDropOldest
+		}
+		if l.Overflow != expected {
+			t.Fatalf("wrong overflow policy of link to %v: %v", l.To.Name, l.Overflow)
+		}
+	}
+
+	gd = `[input:chan_src] -> [output:chan_sink overflow=drop_all]`
+	if _, err := composeIt("overflow_session2", gd); err == nil {
+		t.Fatal("should fail with unknown overflow policy")
+	}
+}
+
+// pubsub fans out messages while its links go down
+func TestPubsubLinkDown(t *testing.T) {
+	gd := `[input:chan_src] -> [pubsub] -> {[output1:chan_sink],[output2:chan_sink],[output3:chan_sink]};`
+	c, err := composeIt("pubsub_link_down", gd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inputC := make(chan []byte)
+	c.GetNode("input").(*comp.ChanSrc).LinkMe(inputC)
+	for _, name := range []string{"output1", "output2", "output3"} {
+		outputC := make(chan []byte, 1)
+		c.GetNode(name).(*comp.ChanSink).LinkMe(outputC)
+		go func() {
+			for range outputC {
+			}
+		}()
+	}
+	ps := c.GetNode("pubsub").(*comp.Pubsub)
+	linkIds := []int{ps.GetLinkPoint(0).LinkId(), ps.GetLinkPoint(1).LinkId()}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			inputC <- []byte("fan out")
+		}
+	}()
+	for _, linkId := range linkIds {
+		time.Sleep(time.Millisecond)
+		ps.OnLinkDown(linkId, "", "")
+	}
+	<-done
+	if ps.GetLinkPoint(1) != nil {
+		t.Fatal("links of pubsub should be down")
+	}
+}
+
 func TestLoop(t *testing.T) {
 	gd := `[input:chan_src] -> [abc:fake_gateway] ->[cba:fake_gateway] -> {[output1:chan_sink],[output2:chan_sink]};`
 	_, err := composeIt("test_session", gd)
@@ -344,3 +433,67 @@ func ExampleComposerBuiltInCommand() {
 	// p1 print fire in the hole
 	// p2 print fire in the hole
 }
+
+// connect graphs as if they were in different media servers
+func connectGraph(g1, g2 *event.Graph) {
+	c1, c2 := make(chan *event.RemoteFrame, 64), make(chan *event.RemoteFrame, 64)
+	r1 := g1.NewRemote("g2", comp.RemoteCodec{}, func(f *event.RemoteFrame) error { c2 <- f; return nil })
+	r2 := g2.NewRemote("g1", comp.RemoteCodec{}, func(f *event.RemoteFrame) error { c1 <- f; return nil })
+	pump := func(c chan *event.RemoteFrame, r *event.Remote) {
+		for f := range c {
+			r.Receive(f)
+		}
+	}
+	go pump(c1, r1)
+	go pump(c2, r2)
+}
+
+func TestComposerRemoteScope(t *testing.T) {
+	g1, g2 := event.NewEventGraph(), event.NewEventGraph()
+	connectGraph(g1, g2)
+	room := comp.NewSessionComposer("room1", "")
+	if err := room.ParseGraphDescription("[sink:chan_sink]"); err != nil {
+		t.Fatal(err)
+	}
+	if err := room.ComposeNodes(g2); err != nil {
+		t.Fatal(err)
+	}
+	outputC := make(chan []byte, 1)
+	room.GetNode("sink").(*comp.ChanSink).LinkMe(outputC)
+
+	var c *comp.Composer
+	var err error
+	for i := 0; i < 50; i++ {
+		// wait for scope advertisement
+		if c, err = composeItOn(g1, "remote_session", "[input:chan_src] -> [sink@room1]"); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	links := c.GetLinks()
+	if len(links) != 1 || links[0].Receiver != nil || links[0].ReceiverScope != "room1" ||
+		links[0].LinkPoint.MessageTrait().TypeId != comp.MtRawByte {
+		t.Fatalf("invalid link to remote scope: %+v", links)
+	}
+	inputC := make(chan []byte)
+	c.GetNode("input").(*comp.ChanSrc).LinkMe(inputC)
+	inputC <- []byte("remote")
+	select {
+	case data := <-outputC:
+		if string(data) != "remote" {
+			t.Fatalf("wrong data received: %v", string(data))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("remote node didn't receive message")
+	}
+
+	if _, err = composeItOn(g1, "remote_custom", "[fire] -> [sink@room1]"); err == nil {
+		t.Fatal("custom message can not be streamed to remote scope")
+	}
+	if _, err = composeItOn(g1, "remote_sender", "[sink@room1] -> [output:chan_sink]"); err == nil {
+		t.Fatal("node of other scope can not send to this session")
+	}
+}
@@ -3,16 +3,41 @@ package comp
 import (
 	"github.com/appcrash/media/server/channel"
 	"github.com/appcrash/media/server/rpc"
+	"time"
 )
 
-// ChannelNode enables a node to send async event to signalling service through system channel
+// InstanceSender is the fromNode of OnCast when instance sends USER_EVENT to the node through system channel
+const InstanceSender = "@instance"
+
+// UserEventReceiver is implemented by nodes interested in USER_EVENT sent to the session without node name,
+// OnUserEvent is invoked in event loop of the node
+type UserEventReceiver interface {
+	OnUserEvent(event string)
+}
+
+// SendUserEvent puts user event to event loops of all nodes implementing UserEventReceiver, it waits no longer than
+// delivery timeout of each node
+func (c *Composer) SendUserEvent(event string) {
+	c.IterateNode(func(name string, node SessionAware) {
+		if _, ok := node.(UserEventReceiver); !ok {
+			return
+		}
+		if sn, ok := node.(interface{ DeliverToStream(msg Message) }); ok {
+			sn.DeliverToStream(&UserEventMessage{Event: event})
+		}
+	})
+}
+
+// ChannelNode enables a node to send async event to signalling service through system channel, events are sent to
+// the instance owning the session at the moment
 type ChannelNode struct {
-	sessionId, instanceId, nodeName string
+	composer            *Composer
+	sessionId, nodeName string
 }
 
 func (n *ChannelNode) BeforeCompose(c *Composer, node SessionAware) error {
+	n.composer = c
 	n.sessionId = c.GetSessionId()
-	n.instanceId = c.GetInstanceId()
 	n.nodeName = node.GetNodeName()
 	return nil
 }
@@ -20,17 +45,31 @@ func (n *ChannelNode) BeforeCompose(c *Composer, node SessionAware) error {
 func (n *ChannelNode) NotifyInstance(event string) error {
 	return channel.GetSystemChannel().NotifyInstance(&rpc.SystemEvent{
 		Cmd:        rpc.SystemCommand_USER_EVENT,
-		InstanceId: n.instanceId,
+		InstanceId: n.composer.GetInstanceId(),
 		SessionId:  n.sessionId,
+		Node:       n.nodeName,
 		Event:      event,
 	})
 }
 
+// NotifyInstanceWithAck sends event like NotifyInstance, the returned channel reports nil once instance acks it or
+// error if not acked in timeout. the event may be delivered more than once.
+func (n *ChannelNode) NotifyInstanceWithAck(event string, timeout time.Duration) <-chan error {
+	return channel.GetSystemChannel().NotifyInstanceWithAck(&rpc.SystemEvent{
+		Cmd:        rpc.SystemCommand_USER_EVENT,
+		InstanceId: n.composer.GetInstanceId(),
+		SessionId:  n.sessionId,
+		Node:       n.nodeName,
+		Event:      event,
+	}, timeout)
+}
+
 func (n *ChannelNode) BroadcastInstance(event string) error {
 	return channel.GetSystemChannel().BroadcastInstance(&rpc.SystemEvent{
 		Cmd:        rpc.SystemCommand_USER_EVENT,
-		InstanceId: n.instanceId,
+		InstanceId: n.composer.GetInstanceId(),
 		SessionId:  n.sessionId,
+		Node:       n.nodeName,
 		Event:      event,
 	})
 }
@@ -1,11 +1,15 @@
 package comp
 
-import "strconv"
+import (
+	"github.com/appcrash/media/server/event"
+	"strconv"
+)
 
 // builtinCommandInitiator provides default common CALL commands for all nodes:
 // -----------------------------------------------------------------------
 // conn {local_node_name}  # return link_index if succeed
 // conn {session} {node_name} # return link_link if succeed
+// conn {session} {node_name} {overflow} # link with overflow policy, i.e. drop_oldest
 // enable_link {link_index}
 // disable_link {link_index}
 type builtinCommandInitiator struct {
@@ -47,10 +51,17 @@ func (c *builtinCommandInitiator) checkBuiltinCall(to SessionAware, args []strin
 	switch args[0] {
 	case "conn":
 		var session, name string
+		overflow := event.OverflowTimeout
 		if argLen == 2 {
 			name = args[1]
 		} else if argLen == 3 {
 			session, name = args[1], args[2]
+		} else if argLen == 4 {
+			var ok bool
+			session, name = args[1], args[2]
+			if overflow, ok = event.ParseOverflowPolicy(args[3]); !ok {
+				return WithError("unknown overflow policy")
+			}
 		} else {
 			logger.Errorf("wrong conn command: %v", args)
 			return WithError("wrong conn command")
@@ -59,7 +70,7 @@ func (c *builtinCommandInitiator) checkBuiltinCall(to SessionAware, args []strin
 			session = c.composer.sessionId
 		}
 
-		if lp, err := to.StreamTo(session, name, to.Offer()); err == nil {
+		if lp, err := streamTo(to, session, name, to.Offer(), overflow); err == nil {
 			return WithOk(strconv.Itoa(lp.LinkId()))
 		} else {
 			return WithError(err.Error())
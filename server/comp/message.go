@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"github.com/appcrash/media/server/comp/nmd"
 	"github.com/appcrash/media/server/event"
+	"github.com/appcrash/media/server/utils"
 	"strings"
 )
 
@@ -102,6 +103,17 @@ func EventToMessage[M Message](evt *event.Event) (msg M, ok bool) {
 type RawByteMessage struct {
 	MessageBase
 	Data []byte
+
+	buffer *utils.PacketBuffer // backs Data once shared or if created from a pooled buffer
+}
+
+// NewRawByteMessageFromBuffer creates message whose Data is the content of buf, the message takes over the caller's
+// reference to buf
+func NewRawByteMessageFromBuffer(buf *utils.PacketBuffer) *RawByteMessage {
+	return &RawByteMessage{
+		Data:   buf.Bytes(),
+		buffer: buf,
+	}
 }
 
 func (m *RawByteMessage) Clone() Cloneable {
@@ -112,6 +124,37 @@ func (m *RawByteMessage) Clone() Cloneable {
 	return clone
 }
 
+// Share returns a message with its own header but the same Data, which is copied only when written after
+// MakeWritable
+func (m *RawByteMessage) Share() Cloneable {
+	if m.buffer == nil {
+		m.buffer = utils.WrapPacketBuffer(m.Data)
+	}
+	return &RawByteMessage{
+		MessageBase: m.MessageBase.Clone(),
+		Data:        m.Data,
+		buffer:      m.buffer.Retain(),
+	}
+}
+
+func (m *RawByteMessage) MakeWritable() {
+	if m.buffer == nil || !m.buffer.Shared() {
+		return
+	}
+	nb := utils.NewPacketBufferFrom(m.Data)
+	m.buffer.Release()
+	m.buffer = nb
+	m.Data = nb.Bytes()
+}
+
+func (m *RawByteMessage) Release() {
+	if m.buffer != nil {
+		m.buffer.Release()
+		m.buffer = nil
+	}
+	m.Data = nil
+}
+
 // Message Processor
 var (
 	nullMessagePostProcessor = func(message Message) {}
@@ -14,3 +14,10 @@ type ChannelLinkRequestMessage struct {
 	InBandCommandCall[interface{}]
 	LinkChannel chan []byte
 }
+
+// UserEventMessage received when instance sends USER_EVENT to the session without node name, it is passed to
+// UserEventReceiver in event loop of the node
+type UserEventMessage struct {
+	MessageBase
+	Event string
+}
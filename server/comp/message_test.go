@@ -3,6 +3,7 @@ package comp_test
 import (
 	"bytes"
 	"github.com/appcrash/media/server/comp"
+	"github.com/appcrash/media/server/utils"
 	"testing"
 )
 
@@ -35,3 +36,44 @@ func TestMessageHeader(t *testing.T) {
 		t.Fatalf("get key wrong: %v", string(value))
 	}
 }
+
+func TestRawByteMessageShare(t *testing.T) {
+	m := &comp.RawByteMessage{Data: []byte("abc")}
+	shared := m.Share().(*comp.RawByteMessage)
+	if &shared.Data[0] != &m.Data[0] {
+		t.Fatal("shared message should refer to the same data")
+	}
+	shared.MakeWritable()
+	shared.Data[0] = 'A'
+	if string(m.Data) != "abc" || string(shared.Data) != "Abc" {
+		t.Fatalf("writing shared message should not affect the original: %s %s", m.Data, shared.Data)
+	}
+	shared.Release()
+	m.Release()
+	if m.Data != nil {
+		t.Fatal("released message should not refer to data")
+	}
+}
+
+const benchFanout = 4
+
+func BenchmarkRawByteFanoutClone(b *testing.B) {
+	msg := &comp.RawByteMessage{Data: make([]byte, 1024)}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < benchFanout; j++ {
+			_ = msg.Clone()
+		}
+	}
+}
+
+func BenchmarkRawByteFanoutShare(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		msg := comp.NewRawByteMessageFromBuffer(utils.NewPacketBuffer(1024))
+		for j := 0; j < benchFanout-1; j++ {
+			msg.Share().(*comp.RawByteMessage).Release()
+		}
+		msg.Release()
+	}
+}
@@ -7,6 +7,10 @@ import (
 
 // TODO: every statement should have sequence id
 
+// LinkOverflowKey is the reserved property of receiver in link statement, it sets overflow policy of the link instead
+// of property of the node, i.e. [src] -> [sink overflow=drop_oldest]
+const LinkOverflowKey = "overflow"
+
 type NodeProp struct {
 	Key, Type string
 	Value     interface{}
@@ -22,6 +26,7 @@ type NodeDef struct {
 	Name, Scope, Type string
 	Props             []*NodeProp
 	Deps              []*NodeDef // record receivers of this node
+	DepOverflow       []string   // overflow policy of link to each receiver in Deps, empty if not set
 }
 
 func (nd *NodeDef) String() string {
@@ -33,7 +38,8 @@ func (nd *NodeDef) String() string {
 }
 
 type EndpointDefs struct {
-	Nodes []*NodeDef
+	Nodes    []*NodeDef
+	Overflow []string // link overflow policy set on each node of the endpoint
 }
 
 type CallActionDefs struct {
@@ -1,6 +1,7 @@
 package nmd
 
 import (
+	"fmt"
 	"github.com/antlr/antlr4/runtime/Go/antlr"
 	"strconv"
 	"strings"
@@ -20,11 +21,13 @@ type Listener struct {
 	nodeMap       map[string]*NodeDef
 	nbNode        int
 	nodeDefStack  []*NodeDef
+	overflowStack []string
 	endpointStack []*EndpointDefs
 
 	currentNodeProp *NodeProp
 	currentNodeDef  *NodeDef
 	currentEndpoint *EndpointDefs
+	currentOverflow string
 
 	errorString string
 }
@@ -53,6 +56,7 @@ func unquoteString(quotedString string) string {
 
 func (l *Listener) pushNodeDef() {
 	l.nodeDefStack = append(l.nodeDefStack, l.currentNodeDef)
+	l.overflowStack = append(l.overflowStack, l.currentOverflow)
 }
 
 func (l *Listener) pushEndpoint() {
@@ -87,10 +91,12 @@ func (l *Listener) EnterLink_stmt(c *Link_stmtContext) {
 func (l *Listener) EnterEndpoint(c *EndpointContext) {
 	l.currentEndpoint = &EndpointDefs{}
 	l.nodeDefStack = nil
+	l.overflowStack = nil
 }
 
 func (l *Listener) EnterNode_id(c *Node_idContext) {
 	var name, scope, typ string
+	l.currentOverflow = ""
 	name = c.GetName().GetText()
 	if c.scope != nil {
 		scope = c.GetScope().GetText()
@@ -143,14 +149,16 @@ func (l *Listener) ExitGraph(c *GraphContext) {
 
 func (l *Listener) ExitEndpoint(c *EndpointContext) {
 	l.currentEndpoint.Nodes = l.nodeDefStack
+	l.currentEndpoint.Overflow = l.overflowStack
 	l.pushEndpoint()
 
 	if len(l.endpointStack) == 2 {
 		// a link is found
 		from, to := l.endpointStack[0], l.endpointStack[1]
 		for _, f := range from.Nodes {
-			for _, t := range to.Nodes {
+			for i, t := range to.Nodes {
 				f.Deps = append(f.Deps, t)
+				f.DepOverflow = append(f.DepOverflow, to.Overflow[i])
 			}
 		}
 		// discard the first endpoint
@@ -163,6 +171,11 @@ func (l *Listener) ExitNode_def(c *Node_defContext) {
 }
 
 func (l *Listener) ExitNode_prop(c *Node_propContext) {
+	if l.currentNodeProp.Key == LinkOverflowKey {
+		// belongs to the link to this node, not the node itself
+		l.currentOverflow = fmt.Sprint(l.currentNodeProp.Value)
+		return
+	}
 	l.currentNodeProp.FormalizeKey()
 	l.currentNodeDef.Props = append(l.currentNodeDef.Props, l.currentNodeProp)
 }
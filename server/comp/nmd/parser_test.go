@@ -66,3 +66,30 @@ func TestSink(t *testing.T) {
 		t.Fatal("parse sink statement failed")
 	}
 }
+
+func TestLinkOverflow(t *testing.T) {
+	testStr := `[a] -> {[b overflow=drop_oldest], [c x=1]} -> [d overflow='block']`
+	input := antlr.NewInputStream(testStr)
+	lexer := nmd.NewnmdLexer(input)
+	stream := antlr.NewCommonTokenStream(lexer, antlr.TokenDefaultChannel)
+	parser := nmd.NewnmdParser(stream)
+	listener := nmd.NewListener("test_session")
+	antlr.ParseTreeWalkerDefault.Walk(listener, parser.Graph())
+	for _, n := range listener.NodeDefs {
+		switch n.Name {
+		case "a":
+			if len(n.DepOverflow) != 2 || n.DepOverflow[0] != "drop_oldest" || n.DepOverflow[1] != "" {
+				t.Fatalf("wrong overflow of links from a: %v", n.DepOverflow)
+			}
+		case "b", "c":
+			if len(n.DepOverflow) != 1 || n.DepOverflow[0] != "block" {
+				t.Fatalf("wrong overflow of links from %v: %v", n.Name, n.DepOverflow)
+			}
+		}
+		for _, p := range n.Props {
+			if p.Key == nmd.LinkOverflowKey {
+				t.Fatalf("overflow should not be property of node %v", n.Name)
+			}
+		}
+	}
+}
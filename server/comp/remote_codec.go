@@ -0,0 +1,129 @@
+package comp
+
+import (
+	"encoding"
+	"encoding/binary"
+	"github.com/appcrash/media/server/event"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	binaryMarshalerType   = reflect.TypeOf((*encoding.BinaryMarshaler)(nil)).Elem()
+	binaryUnmarshalerType = reflect.TypeOf((*encoding.BinaryUnmarshaler)(nil)).Elem()
+)
+
+const remoteLinkPointTimeout = 2 * time.Second
+
+// RemoteCodec serializes messages streamed to nodes of remote scopes. raw bytes and link point negotiation are built
+// in, messages of other types cross media servers if their pointers implement both encoding.BinaryMarshaler and
+// encoding.BinaryUnmarshaler. offers that can not be serialized are removed in negotiation.
+type RemoteCodec struct{}
+
+// RemoteTransferable tells whether message of the trait can be streamed to remote scopes
+func RemoteTransferable(mt *MessageTrait) bool {
+	if mt.TypeId == MtRawByte {
+		return true
+	}
+	return mt.PtrType.Implements(binaryMarshalerType) && mt.PtrType.Implements(binaryUnmarshalerType)
+}
+
+func (RemoteCodec) Encode(evt *event.Event) (data []byte, onReply func(data []byte), ok bool) {
+	msg, isMsg := EventToMessage[Message](evt)
+	if !isMsg {
+		return
+	}
+	switch m := msg.(type) {
+	case *RawByteMessage:
+		data = make([]byte, binary.MaxVarintLen64, binary.MaxVarintLen64+len(m.Meta)+len(m.Data))
+		data = data[:binary.PutUvarint(data, uint64(len(m.Meta)))]
+		data = append(data, m.Meta...)
+		data = append(data, m.Data...)
+		// proxy is the final receiver on this server
+		m.Release()
+		ok = true
+	case *LinkPointRequestMessage:
+		var names []string
+		for _, mt := range m.PreferredTrait {
+			if RemoteTransferable(mt) {
+				names = append(names, mt.Name())
+			}
+		}
+		if len(names) == 0 {
+			m.C <- nil
+			return
+		}
+		data = make([]byte, 8)
+		binary.BigEndian.PutUint64(data, uint64(m.LinkIdentity))
+		data = append(data, strings.Join(names, ",")...)
+		onReply = func(reply []byte) {
+			mt, _ := MessageTraitOfName(string(reply))
+			m.C <- mt
+		}
+		ok = true
+	case *ChannelLinkRequestMessage:
+		select {
+		case m.C <- "channel can not be linked across media servers":
+		default:
+		}
+	default:
+		if marshaler, isMarshaler := msg.(encoding.BinaryMarshaler); isMarshaler {
+			var err error
+			if data, err = marshaler.MarshalBinary(); err != nil {
+				logger.Errorf("failed to marshal message %v for remote scope: %v", msg.Type(), err)
+				return
+			}
+			ok = true
+		}
+	}
+	return
+}
+
+func (RemoteCodec) Decode(cmd int, data []byte, reply func(data []byte)) (evt *event.Event, ok bool) {
+	switch MessageType(cmd) {
+	case MtRawByte:
+		metaLen, n := binary.Uvarint(data)
+		if n <= 0 || uint64(len(data)-n) < metaLen {
+			return
+		}
+		msg := &RawByteMessage{Data: data[n+int(metaLen):]}
+		if metaLen > 0 {
+			msg.Meta = data[n : n+int(metaLen)]
+		}
+		return msg.AsEvent(), true
+	case MtLinkPointRequest:
+		if len(data) < 8 || reply == nil {
+			return
+		}
+		msg := &LinkPointRequestMessage{LinkIdentity: LinkIdentityType(binary.BigEndian.Uint64(data))}
+		for _, name := range strings.Split(string(data[8:]), ",") {
+			if mt, exist := MessageTraitOfName(name); exist {
+				msg.PreferredTrait = append(msg.PreferredTrait, mt)
+			}
+		}
+		msg.C = make(chan *MessageTrait, 1)
+		go func() {
+			var agreed []byte
+			select {
+			case mt := <-msg.C:
+				if mt != nil {
+					agreed = []byte(mt.Name())
+				}
+			case <-time.After(remoteLinkPointTimeout):
+			}
+			reply(agreed)
+		}()
+		return msg.AsEvent(), true
+	}
+	mt, exist := MessageTraitOfType(MessageType(cmd))
+	if !exist || !RemoteTransferable(mt) {
+		return
+	}
+	v := reflect.New(mt.Type).Interface()
+	if err := v.(encoding.BinaryUnmarshaler).UnmarshalBinary(data); err != nil {
+		logger.Errorf("failed to unmarshal message %v from remote scope: %v", mt.Name(), err)
+		return
+	}
+	return v.(Message).AsEvent(), true
+}
@@ -15,6 +15,8 @@ type ChanSink struct {
 
 func (n *ChanSink) handleRawByte(msg *RawByteMessage) {
 	logger.Tracef("%v raw byte", n)
+	// data leaves graph and is owned by channel receiver, make sure it is not shared with others
+	msg.MakeWritable()
 	select {
 	case n.C <- msg.Data:
 	default:
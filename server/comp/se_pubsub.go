@@ -5,7 +5,8 @@ import (
 )
 
 // this node accepts input data (from pub, one or many), make multiple copy of it then send them to
-// all subscriber (to sub). subscriber can be added or removed dynamically by input commands or api.
+// all subscriber (to sub). shareable messages are not copied, subscribers share the same buffer until one of them
+// writes to it. subscriber can be added or removed dynamically by input commands or api.
 // the node will actively remove a subscriber once event is not successfully delivered to it.
 //
 // the input message type is used as output type when node negotiation, if multiple inputs available, they MUST
@@ -85,6 +86,15 @@ func (n *Pubsub) handleInputStream(evt *event.Event) {
 	if len(linkPoint) == 1 {
 		// no need to clone
 		linkPoint[0].SendMessage(msg)
+	} else if shareable := MessageTo[Shareable](msg); shareable != nil {
+		// the last subscriber takes over the original reference
+		for i, lp := range linkPoint {
+			if i == len(linkPoint)-1 {
+				lp.SendMessage(msg)
+			} else {
+				lp.SendMessage(shareable.Share().(Message))
+			}
+		}
 	} else {
 		for _, lp := range linkPoint {
 			cloned := cloneableMessage.Clone()
@@ -49,7 +49,7 @@ type SessionNode struct {
 
 	messageTypeMatch []MessageType
 	messageHandler   []MessageHandler
-	linkPoint        []LinkPoint // grow only array
+	linkPoint        []LinkPoint // copied when removing, readers may hold the old one
 
 	// TODO: put post processor to link level
 	messagePostProcessor MessagePostProcessor
@@ -83,6 +83,7 @@ func (s *SessionNode) OnEnter(delegate *event.NodeDelegate) {
 
 	// provide default negotiation behaviour handlers
 	s.SetMessageHandler(MtLinkPointRequest, ChainDefaultHandler(s._handleLinkPointRequest))
+	s.SetMessageHandler(MtUserEvent, ChainDefaultHandler(s._handleUserEvent))
 }
 
 func (s *SessionNode) OnExit() {
@@ -109,11 +110,10 @@ func (s *SessionNode) OnLinkDown(linkId int, scope string, nodeName string) {
 	for i, l := range s.linkPoint {
 		if l.LinkId() == linkId {
 			logger.Debugf("node %v delete link id %v", s, linkId)
-			if newLp, err := utils.RemoveElementFromArray(s.linkPoint, i); err != nil {
-				logger.Errorf("node %v remove link point with index %v failed", s, linkId)
-			} else {
-				s.linkPoint = newLp
-			}
+			// readers(i.e. pubsub) iterate the slice got under mutex without holding it, so never shift it in place
+			newLp := make([]LinkPoint, 0, len(s.linkPoint)-1)
+			newLp = append(newLp, s.linkPoint[:i]...)
+			s.linkPoint = append(newLp, s.linkPoint[i+1:]...)
 			return
 		}
 	}
@@ -238,6 +238,13 @@ func (s *SessionNode) GetLinkPointOfType(messageType MessageType) (lp LinkPoint)
 // 1. node initialized but not in work state(i.e. no stream is flowing), such as in composer phase
 // 2. outside the node's event goroutine after node has started working
 func (s *SessionNode) StreamTo(session, name string, preferredOffer []MessageType) (lp LinkPoint, err error) {
+	return s.StreamToWithOverflow(session, name, preferredOffer, event.OverflowTimeout)
+}
+
+// StreamToWithOverflow is StreamTo with overflow policy of the link, the policy is applied once the link point is
+// agreed, so negotiation itself is never dropped
+func (s *SessionNode) StreamToWithOverflow(session, name string, preferredOffer []MessageType,
+	overflow event.OverflowPolicy) (lp LinkPoint, err error) {
 	var linkId int
 	var offeredTraits []*MessageTrait
 
@@ -293,6 +300,11 @@ func (s *SessionNode) StreamTo(session, name string, preferredOffer []MessageTyp
 			}
 			return nil
 		}
+		if overflow != event.OverflowTimeout {
+			if err = s.delegate.SetLinkOverflow(linkId, overflow); err != nil {
+				return
+			}
+		}
 		lp = NewLinkPad(s, linkId, linkIdentity, agreedTrait, sendFunc)
 		s.addLinkPoint(lp)
 		logger.Infof("new stream connection %v{link:%x} --->[%v]---> (%v@%v)",
@@ -304,6 +316,17 @@ func (s *SessionNode) StreamTo(session, name string, preferredOffer []MessageTyp
 	return
 }
 
+// _handleUserEvent passes user event to the node in its own event loop
+func (s *SessionNode) _handleUserEvent(evt *event.Event) {
+	msg, ok := EventToMessage[*UserEventMessage](evt)
+	if !ok {
+		return
+	}
+	if r, ok1 := s.Self.(UserEventReceiver); ok1 {
+		r.OnUserEvent(msg.Event)
+	}
+}
+
 //--------------------------- Facility methods --------------------------------
 
 // SetMessageHandler calls chainer to get the new handler and replace the previous one if exists
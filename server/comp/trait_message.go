@@ -18,6 +18,24 @@ type Cloneable interface {
 	Clone() Cloneable
 }
 
+// Releasable messages hold pooled buffers, the final receiver calls Release once done with it. a message that is
+// never released is reclaimed by GC, but it must not be used after released
+type Releasable interface {
+	MessageTraitTag
+	Release()
+}
+
+// Shareable messages can be fanned out without copying payload, Share returns a message referring the same
+// reference-counted buffer. the buffer is read-only while shared, a receiver calls MakeWritable before modifying it,
+// which copies the buffer only if it is still shared (copy-on-write)
+type Shareable interface {
+	MessageTraitTag
+	Cloneable
+	Releasable
+	Share() Cloneable
+	MakeWritable()
+}
+
 // MessageTo convert message to specific trait object
 func MessageTo[T MessageTraitTag](m Message) (v T) {
 	if msg, ok := m.(T); ok {
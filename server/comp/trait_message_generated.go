@@ -11,7 +11,9 @@ const (
 	MrPostComposer                 = uint64(1) << 3
 	MrInitializingNode             = uint64(1) << 4
 	MrUnInitializingNode           = uint64(1) << 5
-	UserMessageTraitEnumShiftBegin = 6
+	MrReleasable                   = uint64(1) << 6
+	MrShareable                    = uint64(1) << 7
+	UserMessageTraitEnumShiftBegin = 8
 )
 
 // Message Type Enum
@@ -19,6 +21,7 @@ const (
 	MtRawByte = iota
 	MtLinkPointRequest
 	MtChannelLinkRequest
+	MtUserEvent
 	MtUserMessageBegin
 )
 
@@ -34,6 +37,10 @@ type ChannelLinkRequestConvertable interface {
 	AsChannelLinkRequestMessage() *ChannelLinkRequestMessage
 }
 
+type UserEventConvertable interface {
+	AsUserEventMessage() *UserEventMessage
+}
+
 // --------Message Implementation Begin--------
 func (m *RawByteMessage) Type() MessageType {
 	return MtRawByte
@@ -59,6 +66,14 @@ func (m *ChannelLinkRequestMessage) AsEvent() *event.Event {
 	return event.NewEvent(MtChannelLinkRequest, m)
 }
 
+func (m *UserEventMessage) Type() MessageType {
+	return MtUserEvent
+}
+
+func (m *UserEventMessage) AsEvent() *event.Event {
+	return event.NewEvent(MtUserEvent, m)
+}
+
 // --------Message Implementation End--------
 
 func initMessageTraits() {
@@ -66,6 +81,7 @@ func initMessageTraits() {
 		MT[RawByteMessage](MetaType[RawByteConvertable]()),
 		MT[LinkPointRequestMessage](MetaType[LinkPointRequestConvertable]()),
 		MT[ChannelLinkRequestMessage](MetaType[ChannelLinkRequestConvertable]()),
+		MT[UserEventMessage](MetaType[UserEventConvertable]()),
 	)
 }
 
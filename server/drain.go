@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"github.com/appcrash/media/server/channel"
+	"github.com/appcrash/media/server/rpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultDrainTimeout is used if neither Drain rpc nor Config specifies it
+	DefaultDrainTimeout = 5 * time.Minute
+	drainCheckInterval  = 100 * time.Millisecond
+)
+
+// errServerDraining is retryable, clients should prepare the session on another server
+var errServerDraining = status.Error(codes.Unavailable, "media server is draining")
+
+// drainState makes server stop accepting new sessions and waits existing ones to end for rolling upgrade
+type drainState struct {
+	mutex    sync.Mutex
+	draining bool
+	deadline time.Time
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+func newDrainState() *drainState {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &drainState{ctx: ctx, cancel: cancel}
+}
+
+func (ds *drainState) isDraining() bool {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+	return ds.draining
+}
+
+// drain enters drain mode if not yet, it is idempotent and the first deadline is kept
+func (srv *MediaServer) drain(timeout time.Duration) *rpc.DrainStatus {
+	ds := srv.drainState
+	ds.mutex.Lock()
+	if !ds.draining {
+		if timeout <= 0 {
+			timeout = srv.drainTimeout
+		}
+		ds.draining = true
+		ds.deadline = time.Now().Add(timeout)
+		logger.Infof("media server enters drain mode, deadline: %v", ds.deadline)
+		deadline := strconv.FormatInt(unixMilli(ds.deadline), 10)
+		if err := channel.GetSystemChannel().BroadcastInstance(&rpc.SystemEvent{
+			Cmd:   rpc.SystemCommand_DRAIN,
+			Event: deadline,
+		}); err != nil {
+			logger.Errorf("failed to broadcast drain notice: %v", err)
+		}
+		go srv.drainLoop(ds.ctx, ds.deadline)
+	}
+	deadline := ds.deadline
+	ds.mutex.Unlock()
+	return &rpc.DrainStatus{
+		Draining:          true,
+		Deadline:          unixMilli(deadline),
+		RemainingSessions: uint32(len(srv.getSessions())),
+	}
+}
+
+// drainLoop waits all sessions to end, force-stops the remaining ones when deadline passed
+func (srv *MediaServer) drainLoop(ctx context.Context, deadline time.Time) {
+	ticker := time.NewTicker(drainCheckInterval)
+	defer ticker.Stop()
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if len(srv.getSessions()) == 0 {
+				logger.Infof("media server is drained")
+				return
+			}
+		case <-timer.C:
+			sessions := srv.getSessions()
+			logger.Warnf("drain deadline passed, force-stop %v sessions", len(sessions))
+			var wg sync.WaitGroup
+			for _, session := range sessions {
+				wg.Add(1)
+				go func(s *MediaSession) {
+					defer wg.Done()
+					s.stop(rpc.StopReason_SERVER_DRAIN)
+				}(session)
+			}
+			wg.Wait()
+			logger.Infof("media server is drained")
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package server_test
+
+import (
+	"context"
+	"github.com/appcrash/media/server"
+	"github.com/appcrash/media/server/rpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+func TestDrain(t *testing.T) {
+	client := startTestServer(t, &server.Config{
+		RtpIp: "127.0.0.1",
+	}).client
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sysStream, err := client.SystemChannel(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sysStream.Send(&rpc.SystemEvent{Cmd: rpc.SystemCommand_REGISTER, InstanceId: "drain"})
+	notices := make(chan *rpc.SystemEvent, 1)
+	go func() {
+		for {
+			evt, err := sysStream.Recv()
+			if err != nil {
+				return
+			}
+			if evt.Cmd == rpc.SystemCommand_DRAIN {
+				notices <- evt
+			}
+		}
+	}()
+	watch, err := client.WatchSessions(ctx, &rpc.WatchParam{InstanceId: "drain"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stopped := make(chan *rpc.SessionEvent, 4)
+	go func() {
+		for {
+			evt, err := watch.Recv()
+			if err != nil {
+				return
+			}
+			if evt.Type == rpc.SessionEventType_SESSION_STOPPED {
+				stopped <- evt
+			}
+		}
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	prepare := func() (*rpc.Session, error) {
+		return client.PrepareSession(ctx, &rpc.CreateParam{
+			PeerIp:   "127.0.0.1",
+			PeerPort: 44030,
+			Codecs: []*rpc.CodecInfo{{
+				PayloadNumber: 8,
+				PayloadType:   rpc.CodecType_PCM_ALAW,
+			}},
+			GraphDesc:  "[echo]",
+			InstanceId: "drain",
+		})
+	}
+	s1, err := prepare()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2, err := prepare()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds, err := client.Drain(ctx, &rpc.DrainParam{Timeout: 1})
+	if err != nil || !ds.Draining || ds.RemainingSessions != 2 {
+		t.Fatalf("invalid drain status: %v %v", ds, err)
+	}
+	select {
+	case <-notices:
+	case <-time.After(time.Second):
+		t.Fatal("no drain notice received")
+	}
+	if _, err = prepare(); status.Code(err) != codes.Unavailable {
+		t.Fatalf("draining server should reject new session with unavailable: %v", err)
+	}
+	if ds2, _ := client.Drain(ctx, &rpc.DrainParam{Timeout: 100}); ds2.Deadline != ds.Deadline {
+		t.Fatal("drain deadline should not be changed")
+	}
+
+	client.StopSession(ctx, &rpc.StopParam{SessionId: s1.SessionId})
+	if evt := <-stopped; evt.SessionId != s1.SessionId || evt.StopReason != rpc.StopReason_EXPLICIT_STOP {
+		t.Fatalf("unexpected stop event: %v", evt)
+	}
+	select {
+	case evt := <-stopped:
+		if evt.SessionId != s2.SessionId || evt.StopReason != rpc.StopReason_SERVER_DRAIN {
+			t.Fatalf("unexpected stop event: %v", evt)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("remaining session should be stopped when drain deadline passed")
+	}
+}
@@ -0,0 +1,199 @@
+package event_test
+
+import (
+	"fmt"
+	"github.com/appcrash/media/server/event"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testShards = 8
+
+func countEventually(count func() int, expected int) bool {
+	for i := 0; i < 100; i++ {
+		if count() == expected {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
+
+// sessions link to nodes of their own and to a hub of another scope, most links are across shards
+func TestShardedLink(t *testing.T) {
+	const sessions = 64
+	graph := event.NewShardedEventGraph(testShards)
+	received := make(chan int, sessions)
+	hub := &testNode{scope: "hub", name: "mixer", onEvent: func(_ *testNode, evt *event.Event) {
+		received <- evt.GetObj().(int)
+	}}
+	hub.SetMaxLink(sessions)
+	graph.AddNode(hub)
+
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	linkDown := make(map[string]int)
+	srcs := make([]*testNode, sessions)
+	for i := 0; i < sessions; i++ {
+		scope := fmt.Sprintf("session%v", i)
+		srcs[i] = &testNode{scope: scope, name: "src", onLinkDown: func(_ *testNode, _ int, scope, name string) {
+			mutex.Lock()
+			linkDown[scope+":"+name]++
+			mutex.Unlock()
+		}}
+		wg.Add(1)
+		go func(i int, src *testNode) {
+			defer wg.Done()
+			if !graph.AddNode(src) || !graph.AddNode(&testNode{scope: src.scope, name: "sink"}) {
+				t.Errorf("failed to add nodes of %v", src.scope)
+				return
+			}
+			if src.delegate.RequestLinkUp(src.scope, "sink") < 0 {
+				t.Errorf("failed to link sink of %v", src.scope)
+			}
+			linkId := src.delegate.RequestLinkUp("hub", "mixer")
+			if linkId < 0 {
+				t.Errorf("failed to link hub from %v", src.scope)
+				return
+			}
+			if src.delegate.RequestLinkUp("hub", "mixer") >= 0 {
+				t.Errorf("duplicated link from %v", src.scope)
+			}
+			src.delegate.Deliver(linkId, event.NewEvent(cmd_nothing, i))
+		}(i, srcs[i])
+	}
+	wg.Wait()
+	if graph.NodeCount() != 2*sessions+1 || graph.LinkCount() != 2*sessions {
+		t.Fatalf("wrong graph size: %v nodes %v links", graph.NodeCount(), graph.LinkCount())
+	}
+	for i := 0; i < sessions; i++ {
+		select {
+		case <-received:
+		case <-time.After(time.Second):
+			t.Fatalf("hub only received %v events", i)
+		}
+	}
+	si := graph.DescribeScope("hub")
+	if si == nil || len(si.Nodes) != 1 || len(si.Links) != sessions {
+		t.Fatalf("wrong hub snapshot: %v", si)
+	}
+
+	// receiver in another shard exits, all senders get link down
+	_ = hub.delegate.RequestNodeExit()
+	if !countEventually(graph.LinkCount, sessions) {
+		t.Fatalf("links to hub are not down: %v", graph.LinkCount())
+	}
+	mutex.Lock()
+	if len(linkDown) != 1 || linkDown["hub:mixer"] != sessions {
+		t.Fatalf("wrong link down: %v", linkDown)
+	}
+	mutex.Unlock()
+
+	// senders exit, links from them are removed from receivers
+	for _, src := range srcs {
+		_ = src.delegate.RequestNodeExit()
+	}
+	if !countEventually(graph.LinkCount, 0) || !countEventually(graph.NodeCount, sessions) {
+		t.Fatalf("wrong graph size after exiting: %v nodes %v links", graph.NodeCount(), graph.LinkCount())
+	}
+	if si = graph.DescribeScope("session0"); si == nil || len(si.Nodes) != 1 || len(si.Links) != 0 {
+		t.Fatalf("wrong session snapshot: %v", si)
+	}
+}
+
+// links up while receivers exit, either the link is down later or it is refused
+func TestShardedLinkRace(t *testing.T) {
+	const rounds = 200
+	graph := event.NewShardedEventGraph(testShards)
+	downs := 0
+	var mutex sync.Mutex
+	src := &testNode{scope: "race", name: "src", onLinkDown: func(_ *testNode, _ int, _, _ string) {
+		mutex.Lock()
+		downs++
+		mutex.Unlock()
+	}}
+	graph.AddNode(src)
+	ups := 0
+	for i := 0; i < rounds; i++ {
+		sink := &testNode{scope: fmt.Sprintf("sink%v", i%testShards), name: "sink"}
+		graph.AddNode(sink)
+		go sink.delegate.RequestNodeExit()
+		if src.delegate.RequestLinkUp(sink.scope, sink.name) >= 0 {
+			ups++
+		}
+		countEventually(func() int {
+			if si := graph.DescribeScope(sink.scope); si != nil {
+				return len(si.Nodes)
+			}
+			return -1
+		}, 0)
+	}
+	if !countEventually(graph.LinkCount, 0) || !countEventually(graph.NodeCount, 1) {
+		t.Fatalf("wrong graph size: %v nodes %v links", graph.NodeCount(), graph.LinkCount())
+	}
+	if !countEventually(func() int {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return downs
+	}, ups) {
+		t.Fatalf("%v links up but %v down", ups, downs)
+	}
+}
+
+func benchmarkGraphSetup(b *testing.B, shards int, sessions int) {
+	graph := event.NewShardedEventGraph(shards)
+	var elapsed time.Duration
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		var wg sync.WaitGroup
+		nodes := make([]*testNode, 0, 3*sessions)
+		var mutex sync.Mutex
+		start := time.Now()
+		for i := 0; i < sessions; i++ {
+			wg.Add(1)
+			go func(scope string) {
+				defer wg.Done()
+				src := &testNode{scope: scope, name: "src"}
+				echo := &testNode{scope: scope, name: "echo"}
+				sink := &testNode{scope: scope, name: "sink"}
+				for _, nd := range []*testNode{src, echo, sink} {
+					if !graph.AddNode(nd) {
+						b.Errorf("failed to add node to %v", scope)
+						return
+					}
+				}
+				if src.delegate.RequestLinkUp(scope, "echo") < 0 || echo.delegate.RequestLinkUp(scope, "sink") < 0 {
+					b.Errorf("failed to link nodes of %v", scope)
+				}
+				mutex.Lock()
+				nodes = append(nodes, src, echo, sink)
+				mutex.Unlock()
+			}(fmt.Sprintf("session%v", i))
+		}
+		wg.Wait()
+		elapsed += time.Since(start)
+
+		b.StopTimer()
+		for _, nd := range nodes {
+			_ = nd.delegate.RequestNodeExit()
+		}
+		for graph.NodeCount() > 0 {
+			time.Sleep(time.Millisecond)
+		}
+		b.StartTimer()
+	}
+	b.ReportMetric(float64(b.N*sessions)/elapsed.Seconds(), "sessions/s")
+}
+
+// set up thousands of sessions concurrently, each adds 3 nodes and links them one by one
+func BenchmarkGraphSetup(b *testing.B) {
+	for _, sessions := range []int{1000, 4000} {
+		for _, shards := range []int{1, testShards} {
+			b.Run(fmt.Sprintf("sessions=%v/shards=%v", sessions, shards), func(b *testing.B) {
+				benchmarkGraphSetup(b, shards, sessions)
+			})
+		}
+	}
+}
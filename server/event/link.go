@@ -1,5 +1,7 @@
 package event
 
+import "sync"
+
 // dlink is directed arrow that connect two nodes, event can only flow
 // in one direction, if dlink is not used anymore, call tear down to
 // notify the other side releasing it
@@ -12,6 +14,14 @@ type dlink struct {
 	// index of nodeInfo's dlink array
 	fromIndex int
 	toIndex   int
+
+	overflow int32  // OverflowPolicy, accessed atomically
+	dropped  uint64 // events dropped by overflow policy, accessed atomically
+
+	// events of OverflowDropOldest waiting for room of receiver, only events of this link are evicted from it
+	queueMutex sync.Mutex
+	queue      []*Event
+	forwarding bool // a goroutine is moving queue to receiver
 }
 
 func generateLinkName(fromScope string, fromNodeName string, toScope string, toNodeName string) string {
@@ -19,7 +19,7 @@ type NodeDelegate struct {
 	ctrlC           chan *Event
 	dataC           chan *Event
 	userEventDoneC  chan int
-	graph           *Graph
+	shard           *shard
 	inExit          atomic.Value
 	deliveryTimeout time.Duration // in milliseconds
 
@@ -52,12 +52,12 @@ const (
 	defaultExitDelay       = 50 * time.Millisecond
 )
 
-func newNodeDelegate(graph *Graph, node Node, maxLink int) *NodeDelegate {
+func newNodeDelegate(shard *shard, node Node, maxLink int) *NodeDelegate {
 	delegate := &NodeDelegate{
 		nodeImpl:       node,
 		ctrlC:          make(chan *Event),
 		userEventDoneC: make(chan int),
-		graph:          graph,
+		shard:          shard,
 	}
 	delegate.id = node.GetNodeScope() + ":" + node.GetNodeName()
 	delegate.inExit.Store(false)
@@ -405,7 +405,7 @@ func (nd *NodeDelegate) RequestLinkUp(scope string, nodeName string) (linkId int
 	}
 	c := make(chan int, 1)
 	evt := newLinkUpRequest(nd, scope, nodeName, c)
-	nd.graph.deliveryEvent(evt)
+	nd.shard.deliveryEvent(evt)
 	linkId = <-c
 	return
 }
@@ -428,7 +428,7 @@ func (nd *NodeDelegate) RequestLinkDown(linkId int) (err error) {
 		return
 	}
 	evt := newLinkDownRequest(link)
-	nd.graph.deliveryEvent(evt)
+	nd.shard.deliveryEvent(evt)
 	return
 }
 
@@ -443,12 +443,16 @@ func (nd *NodeDelegate) RequestNodeExit() (err error) {
 	// out of the graph, send exit request to graph, and graph will handle
 	// all of this
 	nd.setExiting()
-	nd.graph.deliveryEvent(newNodeExitRequest(nd))
+	nd.shard.deliveryEvent(newNodeExitRequest(nd))
 	return
 }
 
 // DeliverWithTimeout [SYNC] return true if successfully delivered
 func (nd *NodeDelegate) DeliverWithTimeout(linkId int, evt *Event, timeout time.Duration) bool {
+	if linkId < 0 || linkId >= len(nd.links) {
+		// i.e. linkId of a failed link-up request
+		return false
+	}
 	link := nd.links[linkId].Load().(*dlink)
 	if link == nullLink {
 		return false
@@ -457,7 +461,22 @@ func (nd *NodeDelegate) DeliverWithTimeout(linkId int, evt *Event, timeout time.
 		// if it is an input dlink
 		return false
 	}
-	return link.toNode.receiveData(evt, timeout)
+	return link.toNode.receiveLinkData(link, evt, timeout)
+}
+
+// SetLinkOverflow changes what the output link does when its receiver doesn't catch up, OverflowTimeout by default
+func (nd *NodeDelegate) SetLinkOverflow(linkId int, policy OverflowPolicy) (err error) {
+	if linkId < 0 || linkId >= len(nd.links) {
+		err = errors.New("linkId out of range")
+		return
+	}
+	link := nd.links[linkId].Load().(*dlink)
+	if link.fromNode != nd {
+		err = errors.New("wrong linkId with different fromNode(not you)")
+		return
+	}
+	atomic.StoreInt32(&link.overflow, int32(policy))
+	return
 }
 
 func (nd *NodeDelegate) Deliver(linkId int, evt *Event) bool {
@@ -125,8 +125,10 @@ func TestMaxLink(t *testing.T) {
 			if tn.delegate.RequestLinkUp("test", "node2") >= 0 {
 				count++
 			}
-			if tn.delegate.RequestLinkUp("test", "node3") >= 0 {
+			if linkId := tn.delegate.RequestLinkUp("test", "node3"); linkId >= 0 {
 				count++
+			} else if tn.delegate.Deliver(linkId, event.NewEvent(0, nil)) {
+				count++ // delivered through a failed link
 			}
 		},
 	}
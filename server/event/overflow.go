@@ -0,0 +1,161 @@
+package event
+
+import (
+	"github.com/appcrash/media/server/prom"
+	"sync/atomic"
+	"time"
+)
+
+// OverflowPolicy decides what a link does when event channel of its receiver is full
+type OverflowPolicy int32
+
+const (
+	// OverflowTimeout waits for the delivery timeout then fails, it is the default
+	OverflowTimeout OverflowPolicy = iota
+	// OverflowBlock waits until receiver has room or is gone, fit for control messages
+	OverflowBlock
+	// OverflowDropNewest fails at once, i.e. the event being delivered is dropped, fit for analytics taps
+	OverflowDropNewest
+	// OverflowDropOldest queues events of the link in front of receiver, as many as its data channel size, and drops
+	// the oldest ones of the link to make room, fit for real-time audio. objects of dropped events are released if
+	// they have Release method, as nobody owns them anymore
+	OverflowDropOldest
+)
+
+var overflowPolicyNames = []string{"timeout", "block", "drop_newest", "drop_oldest"}
+
+func (p OverflowPolicy) String() string {
+	if p >= 0 && int(p) < len(overflowPolicyNames) {
+		return overflowPolicyNames[p]
+	}
+	return "unknown"
+}
+
+// ParseOverflowPolicy converts name such as "drop_oldest" to the policy
+func ParseOverflowPolicy(name string) (OverflowPolicy, bool) {
+	for i, n := range overflowPolicyNames {
+		if n == name {
+			return OverflowPolicy(i), true
+		}
+	}
+	return OverflowTimeout, false
+}
+
+type releasable interface {
+	Release()
+}
+
+func (l *dlink) overflowPolicy() OverflowPolicy {
+	return OverflowPolicy(atomic.LoadInt32(&l.overflow))
+}
+
+func (l *dlink) countDropped(policy OverflowPolicy) {
+	atomic.AddUint64(&l.dropped, 1)
+	prom.NodeGraphLinkDropped.WithLabelValues(policy.String()).Inc()
+}
+
+// receiveLinkData puts event from the link to data channel by overflow policy of the link
+func (nd *NodeDelegate) receiveLinkData(l *dlink, evt *Event, timeout time.Duration) (ok bool) {
+	policy := l.overflowPolicy()
+	if policy == OverflowTimeout {
+		if ok = nd.receiveData(evt, timeout); !ok && !nd.isExiting() {
+			l.countDropped(policy)
+		}
+		return
+	}
+	if nd.isExiting() {
+		return false
+	}
+	atomic.AddInt32(&nd.deliveryCount, 1)
+	defer func() {
+		atomic.AddInt32(&nd.deliveryCount, -1)
+	}()
+	if policy == OverflowDropOldest {
+		return nd.queueLinkData(l, evt)
+	}
+
+	select {
+	case nd.dataC <- evt:
+		return true
+	default:
+	}
+	switch policy {
+	case OverflowBlock:
+		// receiver keeps draining data channel until exited, only gives up if its user loop is over
+		select {
+		case nd.dataC <- evt:
+			ok = true
+		case <-nd.userEventDoneC:
+		}
+	case OverflowDropNewest:
+		l.countDropped(policy)
+	}
+	return
+}
+
+// queueLinkData puts event to data channel directly if nothing of the link is waiting, otherwise to queue of the
+// link and starts forwarding if not yet. events of other links are never touched, so they keep their own policies
+func (nd *NodeDelegate) queueLinkData(l *dlink, evt *Event) bool {
+	l.queueMutex.Lock()
+	defer l.queueMutex.Unlock()
+	if !l.forwarding {
+		// queue is empty as well, no event of the link can be overtaken
+		select {
+		case nd.dataC <- evt:
+			return true
+		default:
+		}
+	}
+	if len(l.queue) >= cap(nd.dataC) {
+		old := l.queue[0]
+		l.queue[0] = nil
+		l.queue = l.queue[1:]
+		if r, isReleasable := old.obj.(releasable); isReleasable {
+			r.Release()
+		}
+		l.countDropped(OverflowDropOldest)
+	}
+	l.queue = append(l.queue, evt)
+	if !l.forwarding {
+		l.forwarding = true
+		// counted before this delivery ends, so that receiver doesn't close data channel in between
+		atomic.AddInt32(&nd.deliveryCount, 1)
+		go nd.forwardLinkData(l)
+	}
+	return true
+}
+
+// forwardLinkData moves queued events of the link to data channel until the queue is empty, the event being moved
+// is not in queue anymore thus never dropped
+func (nd *NodeDelegate) forwardLinkData(l *dlink) {
+	defer atomic.AddInt32(&nd.deliveryCount, -1)
+	for {
+		l.queueMutex.Lock()
+		if len(l.queue) == 0 {
+			l.forwarding = false
+			l.queueMutex.Unlock()
+			return
+		}
+		evt := l.queue[0]
+		l.queue[0] = nil
+		l.queue = l.queue[1:]
+		l.queueMutex.Unlock()
+
+		select {
+		case nd.dataC <- evt:
+		case <-nd.userEventDoneC:
+			// receiver is gone, nobody owns the rest
+			l.queueMutex.Lock()
+			dropped := append(l.queue, evt)
+			l.queue = nil
+			l.forwarding = false
+			l.queueMutex.Unlock()
+			for _, e := range dropped {
+				if r, isReleasable := e.obj.(releasable); isReleasable {
+					r.Release()
+				}
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package event_test
+
+import (
+	"github.com/appcrash/media/server/event"
+	"testing"
+	"time"
+)
+
+func TestLinkOverflow(t *testing.T) {
+	graph := event.NewEventGraph()
+	cases := []struct {
+		policy   event.OverflowPolicy
+		received []int
+		dropped  uint64
+	}{
+		{event.OverflowTimeout, []int{0, 1, 2}, 5},
+		{event.OverflowDropNewest, []int{0, 1, 2}, 5},
+		// 1 and 2 are in data channel, 3 is being forwarded while 4 and 5 are dropped from queue of the link
+		{event.OverflowDropOldest, []int{0, 1, 2, 3, 6, 7}, 2},
+		{event.OverflowBlock, []int{0, 1, 2, 3, 4, 5, 6, 7}, 0},
+	}
+	for _, c := range cases {
+		// receiver holds the first event until released, so that following events fill up its data channel
+		scope := c.policy.String()
+		received := make(chan int, 16)
+		release, entered := make(chan struct{}), make(chan struct{})
+		receiver := &testNode{scope: scope, name: "receiver", onEvent: func(_ *testNode, evt *event.Event) {
+			n := evt.GetObj().(int)
+			if n == 0 {
+				close(entered)
+				<-release
+			}
+			received <- n
+		}}
+		receiver.SetDataChannelSize(2)
+		sender := &testNode{scope: scope, name: "sender"}
+		sender.SetDeliveryTimeout(10 * time.Millisecond)
+		graph.AddNode(receiver)
+		graph.AddNode(sender)
+		linkId := sender.delegate.RequestLinkUp(scope, "receiver")
+		if linkId < 0 {
+			t.Fatalf("%v: failed to link up", scope)
+		}
+		if err := sender.delegate.SetLinkOverflow(linkId, c.policy); err != nil {
+			t.Fatal(err)
+		}
+
+		sender.delegate.Deliver(linkId, event.NewEvent(cmd_nothing, 0))
+		<-entered
+		delivered := make(chan bool)
+		go func() {
+			for i := 1; i < 8; i++ {
+				sender.delegate.Deliver(linkId, event.NewEvent(cmd_nothing, i))
+				time.Sleep(5 * time.Millisecond)
+			}
+			close(delivered)
+		}()
+		select {
+		case <-delivered:
+			if c.policy == event.OverflowBlock {
+				t.Fatalf("%v: delivery should block until receiver has room", scope)
+			}
+		case <-time.After(200 * time.Millisecond):
+			if c.policy != event.OverflowBlock {
+				t.Fatalf("%v: delivery should not block", scope)
+			}
+		}
+		close(release)
+		for _, expected := range c.received {
+			select {
+			case n := <-received:
+				if n != expected {
+					t.Fatalf("%v: received %v but expected %v", scope, n, expected)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("%v: not received %v", scope, expected)
+			}
+		}
+		<-delivered
+		si := graph.DescribeScope(scope)
+		if si == nil || len(si.Links) != 1 || si.Links[0].Overflow != c.policy || si.Links[0].Dropped != c.dropped {
+			t.Fatalf("%v: wrong link snapshot %v", scope, si)
+		}
+	}
+
+	if _, ok := event.ParseOverflowPolicy("drop_oldest"); !ok {
+		t.Fatal("failed to parse policy name")
+	}
+	if _, ok := event.ParseOverflowPolicy("drop_all"); ok {
+		t.Fatal("unknown policy name should not be parsed")
+	}
+}
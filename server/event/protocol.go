@@ -6,6 +6,17 @@ const (
 	reqLinkDown
 	reqNodeAdd
 	reqNodeExit
+	reqScopeQuery
+	reqRemoteAttach
+	reqRemoteDetach
+)
+
+// peer events between shards for links across them
+const (
+	peerLinkAttach = iota + 5000
+	peerLinkAttached
+	peerLinkDetach
+	peerLinkBroken
 )
 
 const (
@@ -43,6 +54,22 @@ type nodeExitRequest struct {
 	delegate *NodeDelegate
 }
 
+type scopeQueryRequest struct {
+	scope string
+	c     chan *ScopeInfo
+}
+
+type remoteRequest struct {
+	remote *Remote
+	c      chan int
+}
+
+type linkAttachResponse struct {
+	req   *linkUpRequest
+	link  *dlink
+	state int
+}
+
 /* ------- response structs ------- */
 type linkUpResponse struct {
 	state    int
@@ -94,6 +121,35 @@ func newNodeExitRequest(node *NodeDelegate) *Event {
 	return NewEvent(reqNodeExit, &nodeExitRequest{node})
 }
 
+func newScopeQueryRequest(scope string, c chan *ScopeInfo) *Event {
+	return NewEvent(reqScopeQuery, &scopeQueryRequest{scope, c})
+}
+
+func newRemoteAttachRequest(r *Remote, c chan int) *Event {
+	return NewEvent(reqRemoteAttach, &remoteRequest{r, c})
+}
+
+func newRemoteDetachRequest(r *Remote) *Event {
+	return NewEvent(reqRemoteDetach, &remoteRequest{r, nil})
+}
+
+/* ---------------PEER------------------- */
+func newPeerLinkAttach(req *linkUpRequest) *Event {
+	return NewEvent(peerLinkAttach, req)
+}
+
+func newPeerLinkAttached(req *linkUpRequest, link *dlink, state int) *Event {
+	return NewEvent(peerLinkAttached, &linkAttachResponse{req, link, state})
+}
+
+func newPeerLinkDetach(link *dlink) *Event {
+	return NewEvent(peerLinkDetach, &linkDownRequest{link})
+}
+
+func newPeerLinkBroken(link *dlink) *Event {
+	return NewEvent(peerLinkBroken, &linkDownRequest{link})
+}
+
 /* ---------------RESPONSE------------------- */
 func newLinkUpResponse(resp *dlink, state int, scope string, name string, c chan int) *Event {
 	return NewEvent(respLinkUp, &linkUpResponse{state, resp, scope, name, c})
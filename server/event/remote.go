@@ -0,0 +1,517 @@
+package event
+
+import (
+	"fmt"
+	"github.com/appcrash/media/server/prom"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// remote scopes link nodes across media servers. a Remote is one end of the transport to a peer server, both ends
+// advertise scopes of their local nodes to each other. once a node requests link up to a scope hosted by peer, graph
+// creates a proxy node standing for the remote node, so sender delivers to proxy as usual. the proxy forwards
+// serialized events to an ingress node on peer which delivers them into the real target. if the target exits or the
+// transport breaks, proxy exits graph and senders get link-down just like local links.
+
+const (
+	FrameScopeAdd    = iota + 1 // Scope is hosted by sender of the frame
+	FrameScopeRemove            // Scope is no longer hosted by sender of the frame
+	FrameLinkUp                 // proxy LinkId wants to link to node Scope:Name
+	FrameUnlink                 // proxy LinkId exits, its ingress should exit too
+	FrameLinkDown               // link of proxy LinkId is down on receiving side
+	FrameData                   // event of Cmd to proxy LinkId, the encoded event expects reply if ReplyId not zero
+	FrameReply                  // reply of the event with ReplyId
+	FrameKeepalive
+)
+
+const (
+	// RemoteIngressScope is scope of ingress nodes that deliver events from peers
+	RemoteIngressScope = "@remote"
+
+	RemoteKeepaliveInterval = 2 * time.Second
+	RemoteKeepaliveTimeout  = 3 * RemoteKeepaliveInterval
+	// RemoteReplyTimeout is how long an event waits for reply from peer, onReply gets nil data after it
+	RemoteReplyTimeout = 5 * time.Second
+
+	remoteSendQueueSize = 1024
+)
+
+// RemoteFrame is what Remote exchanges with its peer, transport maps it to the wire format
+type RemoteFrame struct {
+	Type    int
+	LinkId  uint64
+	Scope   string
+	Name    string
+	Cmd     int
+	ReplyId uint64
+	Data    []byte
+}
+
+// RemoteCodec serializes events delivered across media servers
+type RemoteCodec interface {
+	// Encode returns payload of the event or false if it can not leave this server, onReply is not nil if the
+	// event expects a reply from peer. onReply is called once, with nil data if peer doesn't reply in
+	// RemoteReplyTimeout or remote is closed
+	Encode(evt *Event) (data []byte, onReply func(data []byte), ok bool)
+	// Decode rebuilds the event on receiving server, reply is not nil if the encoding side expects a reply
+	Decode(cmd int, data []byte, reply func(data []byte)) (evt *Event, ok bool)
+}
+
+var remoteSeq uint64
+
+// Remote is the local end of transport to a peer media server
+type Remote struct {
+	graph    *Graph
+	codec    RemoteCodec
+	id       uint64
+	name     string
+	lastSeen int64 // unix nano of the last frame from peer, accessed atomically
+
+	sendC     chan *RemoteFrame
+	advertC   chan struct{}
+	doneC     chan struct{}
+	writtenC  chan struct{} // closed once no more frame is passed to send
+	closeOnce sync.Once
+
+	mutex     sync.Mutex
+	closed    bool
+	nextId    uint64
+	scopes    map[string]bool
+	adverts   []*RemoteFrame
+	proxies   map[uint64]*remoteProxy   // by link id
+	ingresses map[uint64]*remoteIngress // by link id of peer's proxy
+	replies   map[uint64]*remoteReply
+}
+
+// remoteReply is an event forwarded to peer and waiting for reply
+type remoteReply struct {
+	onReply func(data []byte)
+	timer   *time.Timer
+}
+
+// remoteNode is implemented by proxies and ingresses, their scopes are never advertised to peers
+type remoteNode interface {
+	remoteOf() *Remote
+}
+
+func isRemoteNode(nd *NodeDelegate) bool {
+	_, ok := nd.nodeImpl.(remoteNode)
+	return ok
+}
+
+type remoteOpenEvent struct{}
+
+// remoteProxy stands for node Scope:Name hosted by peer
+type remoteProxy struct {
+	remote      *Remote
+	id          uint64
+	scope, name string
+	delegate    *NodeDelegate
+	down        int32 // link down reported by peer, accessed atomically
+}
+
+func (p *remoteProxy) GetNodeName() string {
+	return p.name
+}
+
+func (p *remoteProxy) GetNodeScope() string {
+	return p.scope
+}
+
+func (p *remoteProxy) OnEvent(evt *Event) {
+	if _, ok := evt.obj.(*remoteOpenEvent); ok {
+		p.remote.send(&RemoteFrame{Type: FrameLinkUp, LinkId: p.id, Scope: p.scope, Name: p.name})
+		return
+	}
+	p.remote.forward(p.id, evt)
+}
+
+func (p *remoteProxy) OnLinkDown(_ int, _ string, _ string) {}
+
+func (p *remoteProxy) OnEnter(_ *NodeDelegate) {}
+
+func (p *remoteProxy) OnExit() {
+	p.remote.proxyExit(p)
+}
+
+func (p *remoteProxy) remoteOf() *Remote {
+	return p.remote
+}
+
+// remoteIngress links to the target node on behalf of a proxy on peer
+type remoteIngress struct {
+	remote   *Remote
+	id       uint64
+	name     string
+	delegate *NodeDelegate
+	linkId   int
+	unlinked int32 // proxy on peer has exited, accessed atomically
+}
+
+func (i *remoteIngress) GetNodeName() string {
+	return i.name
+}
+
+func (i *remoteIngress) GetNodeScope() string {
+	return RemoteIngressScope
+}
+
+func (i *remoteIngress) OnEvent(_ *Event) {}
+
+func (i *remoteIngress) OnLinkDown(_ int, _ string, _ string) {
+	i.remote.ingressDown(i)
+}
+
+func (i *remoteIngress) OnEnter(delegate *NodeDelegate) {
+	i.delegate = delegate
+}
+
+func (i *remoteIngress) OnExit() {}
+
+func (i *remoteIngress) remoteOf() *Remote {
+	return i.remote
+}
+
+// NewRemote [SYNC] attaches a peer media server to graph, name identifies the peer in logs. frames to peer are
+// passed to send one by one in a dedicated goroutine, transport calls Receive for frames from peer and Close once
+// it is broken
+func (eg *Graph) NewRemote(name string, codec RemoteCodec, send func(*RemoteFrame) error) *Remote {
+	r := &Remote{
+		graph:     eg,
+		codec:     codec,
+		id:        atomic.AddUint64(&remoteSeq, 1),
+		name:      name,
+		lastSeen:  time.Now().UnixNano(),
+		sendC:     make(chan *RemoteFrame, remoteSendQueueSize),
+		advertC:   make(chan struct{}, 1),
+		doneC:     make(chan struct{}),
+		writtenC:  make(chan struct{}),
+		scopes:    make(map[string]bool),
+		proxies:   make(map[uint64]*remoteProxy),
+		ingresses: make(map[uint64]*remoteIngress),
+		replies:   make(map[uint64]*remoteReply),
+	}
+	// every shard advertises its scopes and looks up scopes of the remote
+	c := make(chan int, len(eg.shards))
+	for _, s := range eg.shards {
+		s.deliveryEvent(newRemoteAttachRequest(r, c))
+	}
+	for range eg.shards {
+		<-c
+	}
+	prom.NodeGraphRemotePeers.Inc()
+	go r.writeLoop(send)
+	return r
+}
+
+func (s *shard) onRemoteAttach(req *remoteRequest) {
+	r := req.remote
+	s.remotes = append(s.remotes, r)
+	for scope := range s.localScopes {
+		r.advertise(FrameScopeAdd, scope)
+	}
+	req.c <- 0
+}
+
+func (s *shard) onRemoteDetach(req *remoteRequest) {
+	for i, r := range s.remotes {
+		if r == req.remote {
+			s.remotes = append(s.remotes[:i], s.remotes[i+1:]...)
+			return
+		}
+	}
+}
+
+// findRemoteNode creates proxy of the node if its scope is hosted by any peer, called in loop of shard of the scope
+func (s *shard) findRemoteNode(scope string, name string) *NodeDelegate {
+	for _, r := range s.remotes {
+		if r.hosts(scope) {
+			return r.newProxy(s, scope, name)
+		}
+	}
+	return nil
+}
+
+func (r *Remote) String() string {
+	return fmt.Sprintf("remote(%v)", r.name)
+}
+
+// Done is closed once the remote is closed
+func (r *Remote) Done() <-chan struct{} {
+	return r.doneC
+}
+
+// Wait blocks until the remote is closed and send is not called anymore, so transport can be released
+func (r *Remote) Wait() {
+	<-r.writtenC
+}
+
+// Close detaches the remote from graph, all links across it are down and pending replies get nil data
+func (r *Remote) Close() {
+	r.closeOnce.Do(func() {
+		r.mutex.Lock()
+		r.closed = true
+		proxies, ingresses, replies := r.proxies, r.ingresses, r.replies
+		r.proxies, r.ingresses = make(map[uint64]*remoteProxy), make(map[uint64]*remoteIngress)
+		r.replies = make(map[uint64]*remoteReply)
+		r.mutex.Unlock()
+		close(r.doneC)
+		for _, pr := range replies {
+			pr.timer.Stop()
+			pr.onReply(nil)
+		}
+		for _, s := range r.graph.shards {
+			s.deliveryEvent(newRemoteDetachRequest(r))
+		}
+		prom.NodeGraphRemotePeers.Dec()
+		for _, p := range proxies {
+			_ = p.delegate.RequestNodeExit()
+		}
+		for _, i := range ingresses {
+			_ = i.delegate.RequestNodeExit()
+		}
+		if logger != nil {
+			logger.Infof("%v closed with %v proxies and %v ingresses", r, len(proxies), len(ingresses))
+		}
+	})
+}
+
+// Receive handles a frame from peer, frames must be passed in the order peer sent them
+func (r *Remote) Receive(f *RemoteFrame) {
+	atomic.StoreInt64(&r.lastSeen, time.Now().UnixNano())
+	switch f.Type {
+	case FrameScopeAdd, FrameScopeRemove:
+		r.mutex.Lock()
+		if f.Type == FrameScopeAdd {
+			r.scopes[f.Scope] = true
+		} else {
+			delete(r.scopes, f.Scope)
+		}
+		r.mutex.Unlock()
+	case FrameLinkUp:
+		r.onLinkUp(f)
+	case FrameUnlink:
+		r.mutex.Lock()
+		i := r.ingresses[f.LinkId]
+		delete(r.ingresses, f.LinkId)
+		r.mutex.Unlock()
+		if i != nil {
+			atomic.StoreInt32(&i.unlinked, 1)
+			_ = i.delegate.RequestNodeExit()
+		}
+	case FrameLinkDown:
+		r.mutex.Lock()
+		p := r.proxies[f.LinkId]
+		r.mutex.Unlock()
+		if p != nil {
+			atomic.StoreInt32(&p.down, 1)
+			_ = p.delegate.RequestNodeExit()
+		}
+	case FrameData:
+		r.onData(f)
+	case FrameReply:
+		if onReply := r.takeReply(f.ReplyId); onReply != nil {
+			onReply(f.Data)
+		}
+	}
+}
+
+func (r *Remote) hosts(scope string) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return !r.closed && r.scopes[scope]
+}
+
+// advertise queues scope change to peer without blocking graph loop
+func (r *Remote) advertise(frameType int, scope string) {
+	r.mutex.Lock()
+	r.adverts = append(r.adverts, &RemoteFrame{Type: frameType, Scope: scope})
+	r.mutex.Unlock()
+	select {
+	case r.advertC <- struct{}{}:
+	default:
+	}
+}
+
+// send queues the frame, it blocks if the queue is full until remote closed
+func (r *Remote) send(f *RemoteFrame) bool {
+	select {
+	case r.sendC <- f:
+		return true
+	case <-r.doneC:
+		return false
+	}
+}
+
+func (r *Remote) writeLoop(send func(*RemoteFrame) error) {
+	defer close(r.writtenC)
+	ticker := time.NewTicker(RemoteKeepaliveInterval)
+	defer ticker.Stop()
+	var err error
+	for err == nil {
+		select {
+		case f := <-r.sendC:
+			err = send(f)
+		case <-r.advertC:
+			r.mutex.Lock()
+			adverts := r.adverts
+			r.adverts = nil
+			r.mutex.Unlock()
+			for _, f := range adverts {
+				if err = send(f); err != nil {
+					break
+				}
+			}
+		case <-ticker.C:
+			if time.Since(time.Unix(0, atomic.LoadInt64(&r.lastSeen))) > RemoteKeepaliveTimeout {
+				err = fmt.Errorf("keepalive timeout")
+			} else {
+				err = send(&RemoteFrame{Type: FrameKeepalive})
+			}
+		case <-r.doneC:
+			return
+		}
+	}
+	if logger != nil {
+		logger.Errorf("%v is broken: %v", r, err)
+	}
+	r.Close()
+}
+
+// newProxy adds proxy of the remote node to graph, called in loop of the shard
+func (r *Remote) newProxy(s *shard, scope string, name string) *NodeDelegate {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.closed {
+		return nil
+	}
+	r.nextId++
+	p := &remoteProxy{remote: r, id: r.nextId, scope: scope, name: name}
+	p.delegate = s.spawnNode(p, nil)
+	r.proxies[p.id] = p
+	// the first event of proxy asks peer to link up, so it always precedes data
+	p.delegate.dataC <- NewEvent(0, &remoteOpenEvent{})
+	return p.delegate
+}
+
+func (r *Remote) proxyExit(p *remoteProxy) {
+	r.mutex.Lock()
+	delete(r.proxies, p.id)
+	closed := r.closed
+	r.mutex.Unlock()
+	if !closed && atomic.LoadInt32(&p.down) == 0 {
+		r.send(&RemoteFrame{Type: FrameUnlink, LinkId: p.id})
+	}
+}
+
+// forward sends event delivered to proxy to its ingress on peer
+func (r *Remote) forward(linkId uint64, evt *Event) {
+	data, onReply, ok := r.codec.Encode(evt)
+	if !ok {
+		prom.NodeGraphRemoteDropped.WithLabelValues("codec").Inc()
+		return
+	}
+	f := &RemoteFrame{Type: FrameData, LinkId: linkId, Cmd: evt.cmd, Data: data}
+	if onReply != nil {
+		r.mutex.Lock()
+		if r.closed {
+			r.mutex.Unlock()
+			onReply(nil)
+			return
+		}
+		r.nextId++
+		id := r.nextId
+		f.ReplyId = id
+		r.replies[id] = &remoteReply{onReply: onReply, timer: time.AfterFunc(RemoteReplyTimeout, func() {
+			if onReply := r.takeReply(id); onReply != nil {
+				onReply(nil)
+			}
+		})}
+		r.mutex.Unlock()
+	}
+	if !r.send(f) && f.ReplyId != 0 {
+		if onReply = r.takeReply(f.ReplyId); onReply != nil {
+			onReply(nil)
+		}
+	}
+}
+
+// takeReply removes the pending reply, nil if it is already replied, timed out or closed
+func (r *Remote) takeReply(id uint64) func(data []byte) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	pr, ok := r.replies[id]
+	if !ok {
+		return nil
+	}
+	delete(r.replies, id)
+	pr.timer.Stop()
+	return pr.onReply
+}
+
+// onLinkUp creates ingress for proxy on peer and links it to the target
+func (r *Remote) onLinkUp(f *RemoteFrame) {
+	i := &remoteIngress{remote: r, id: f.LinkId, name: fmt.Sprintf("%v-%v", r.id, f.LinkId), linkId: -1}
+	if !r.graph.AddNode(i) {
+		r.send(&RemoteFrame{Type: FrameLinkDown, LinkId: f.LinkId})
+		return
+	}
+	r.mutex.Lock()
+	if r.closed {
+		r.mutex.Unlock()
+		_ = i.delegate.RequestNodeExit()
+		return
+	}
+	r.ingresses[i.id] = i
+	r.mutex.Unlock()
+	if i.linkId = i.delegate.RequestLinkUp(f.Scope, f.Name); i.linkId < 0 {
+		if logger != nil {
+			logger.Debugf("%v can not link to %v:%v", r, f.Scope, f.Name)
+		}
+		r.ingressDown(i)
+	}
+}
+
+// ingressDown tells peer the link is down and removes the ingress
+func (r *Remote) ingressDown(i *remoteIngress) {
+	r.mutex.Lock()
+	if r.ingresses[i.id] == i {
+		delete(r.ingresses, i.id)
+	}
+	closed := r.closed
+	r.mutex.Unlock()
+	if !closed && atomic.LoadInt32(&i.unlinked) == 0 {
+		r.send(&RemoteFrame{Type: FrameLinkDown, LinkId: i.id})
+	}
+	_ = i.delegate.RequestNodeExit()
+}
+
+func (r *Remote) onData(f *RemoteFrame) {
+	var reply func(data []byte)
+	if replyId := f.ReplyId; replyId != 0 {
+		reply = func(data []byte) {
+			r.send(&RemoteFrame{Type: FrameReply, ReplyId: replyId, Data: data})
+		}
+	}
+	r.mutex.Lock()
+	i := r.ingresses[f.LinkId]
+	r.mutex.Unlock()
+	if i == nil {
+		if reply != nil {
+			reply(nil)
+		}
+		return
+	}
+	evt, ok := r.codec.Decode(f.Cmd, f.Data, reply)
+	if !ok {
+		prom.NodeGraphRemoteDropped.WithLabelValues("codec").Inc()
+		if reply != nil {
+			reply(nil)
+		}
+		return
+	}
+	if !i.delegate.Deliver(i.linkId, evt) {
+		prom.NodeGraphRemoteDropped.WithLabelValues("deliver").Inc()
+	}
+}
@@ -0,0 +1,125 @@
+package event_test
+
+import (
+	"github.com/appcrash/media/server/event"
+	"testing"
+	"time"
+)
+
+type bytesCodec struct{}
+
+func (bytesCodec) Encode(evt *event.Event) (data []byte, onReply func(data []byte), ok bool) {
+	if replyC, isReply := evt.GetObj().(chan []byte); isReply {
+		// expects reply that peer never sends
+		return []byte{}, func(data []byte) { replyC <- data }, true
+	}
+	data, ok = evt.GetObj().([]byte)
+	return
+}
+
+func (bytesCodec) Decode(cmd int, data []byte, _ func(data []byte)) (*event.Event, bool) {
+	return event.NewEvent(cmd, data), true
+}
+
+// connect two graphs as if they were in different media servers
+func connectGraph(g1, g2 *event.Graph) (r1, r2 *event.Remote) {
+	c1, c2 := make(chan *event.RemoteFrame, 64), make(chan *event.RemoteFrame, 64)
+	r1 = g1.NewRemote("g2", bytesCodec{}, func(f *event.RemoteFrame) error { c2 <- f; return nil })
+	r2 = g2.NewRemote("g1", bytesCodec{}, func(f *event.RemoteFrame) error { c1 <- f; return nil })
+	pump := func(c chan *event.RemoteFrame, r *event.Remote) {
+		for {
+			select {
+			case f := <-c:
+				r.Receive(f)
+			case <-r.Done():
+				return
+			}
+		}
+	}
+	go pump(c1, r1)
+	go pump(c2, r2)
+	return
+}
+
+func linkUpEventually(nd *event.NodeDelegate, scope, name string) (linkId int) {
+	for i := 0; i < 50; i++ {
+		if linkId = nd.RequestLinkUp(scope, name); linkId >= 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return
+}
+
+func TestRemoteLink(t *testing.T) {
+	g1, g2 := event.NewEventGraph(), event.NewEventGraph()
+	r1, _ := connectGraph(g1, g2)
+	received := make(chan string, 1)
+	linkDown := make(chan string, 2)
+	mixer := &testNode{scope: "room1", name: "mixer", onEvent: func(_ *testNode, evt *event.Event) {
+		received <- string(evt.GetObj().([]byte))
+	}}
+	sender := &testNode{scope: "s1", name: "sender", onLinkDown: func(_ *testNode, _ int, scope, name string) {
+		linkDown <- scope + ":" + name
+	}}
+	g2.AddNode(mixer)
+	g1.AddNode(sender)
+
+	if sender.delegate.RequestLinkUp("room2", "mixer") >= 0 {
+		t.Fatal("should not link to scope hosted by nobody")
+	}
+	linkId := linkUpEventually(sender.delegate, "room1", "mixer")
+	if linkId < 0 {
+		t.Fatal("failed to link to remote scope")
+	}
+	if !sender.delegate.Deliver(linkId, event.NewEvent(cmd_nothing, []byte("hello"))) {
+		t.Fatal("failed to deliver to remote node")
+	}
+	select {
+	case data := <-received:
+		if data != "hello" {
+			t.Fatalf("wrong data received: %v", data)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("remote node didn't receive event")
+	}
+
+	// target exits, sender gets link down
+	_ = mixer.delegate.RequestNodeExit()
+	select {
+	case loc := <-linkDown:
+		if loc != "room1:mixer" {
+			t.Fatalf("wrong link down: %v", loc)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no link down after remote node exits")
+	}
+
+	// transport to peer breaks, sender gets link down
+	g2.AddNode(&testNode{scope: "room1", name: "mixer2"})
+	if linkId = linkUpEventually(sender.delegate, "room1", "mixer2"); linkId < 0 {
+		t.Fatal("failed to link to remote scope again")
+	}
+	replyC := make(chan []byte, 1)
+	if !sender.delegate.Deliver(linkId, event.NewEvent(cmd_nothing, replyC)) {
+		t.Fatal("failed to deliver event expecting reply")
+	}
+	time.Sleep(100 * time.Millisecond)
+	r1.Close()
+	select {
+	case loc := <-linkDown:
+		if loc != "room1:mixer2" {
+			t.Fatalf("wrong link down: %v", loc)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no link down after peer is closed")
+	}
+	select {
+	case data := <-replyC:
+		if data != nil {
+			t.Fatalf("pending reply should get nil data: %v", data)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("pending reply is not called after remote closed")
+	}
+}
@@ -0,0 +1,491 @@
+package event
+
+import (
+	"github.com/appcrash/media/server/prom"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+type scopeMapType map[string]map[string]*NodeDelegate // scope => name => node
+type linkSetType map[string]*dlink
+type nodeMapType map[string]*nodeInfo
+
+// shard owns the scopes hashed to it, i.e. their nodes and the bookkeeping of links from/to them, all changes are
+// made in its own loop. a link is owned by the shard of its sender and registered as input link by the shard of its
+// receiver, the two shards talk by peer events only when they differ:
+//
+//	link up:   sender shard reserves the link --attach--> receiver shard links receiver --attached--> sender shard
+//	link down: sender shard drops the link --detach--> receiver shard
+//	exit:      receiver shard drops the link --broken--> sender shard drops the link and notifies sender
+//
+// peer events are queued without bound so that loops never wait for each other, and they are handled in the order
+// they were posted by the same shard
+type shard struct {
+	graph *Graph
+	index int
+
+	scopeMap scopeMapType
+	nodeMap  nodeMapType // nodeId -> nodeInfo
+	linkSet  linkSetType // alive links whose sender is in this shard
+	pending  map[string]bool
+
+	remotes     []*Remote      // peers in attaching order, the first one hosting a scope wins
+	localScopes map[string]int // scope => number of local nodes, remote proxies and ingresses excluded
+
+	eventChannel chan *Event
+
+	peerMutex  sync.Mutex
+	peerEvents []*Event
+	peerC      chan struct{}
+}
+
+type nodeInfo struct {
+	inputLinks  []*dlink
+	outputLinks []*dlink
+	maxLink     int
+	pending     int // output links waiting for receiver shard
+}
+
+func newShard(graph *Graph, index int) *shard {
+	return &shard{
+		graph:        graph,
+		index:        index,
+		scopeMap:     make(scopeMapType),
+		nodeMap:      make(nodeMapType),
+		linkSet:      make(linkSetType),
+		pending:      make(map[string]bool),
+		localScopes:  make(map[string]int),
+		eventChannel: make(chan *Event),
+		peerC:        make(chan struct{}, 1),
+	}
+}
+
+func (s *shard) addNodeStats(delta int) {
+	atomic.AddInt64(&s.graph.nbNode, int64(delta))
+	prom.NodeGraphNodes.Add(float64(delta))
+}
+
+func (s *shard) addLinkStats(delta int) {
+	atomic.AddInt64(&s.graph.nbLink, int64(delta))
+	prom.NodeGraphLinks.Add(float64(delta))
+}
+
+func (s *shard) findNode(scope string, name string) *NodeDelegate {
+	return s.scopeMap[scope][name]
+}
+
+func (s *shard) addNode(nd *NodeDelegate, maxLink int) {
+	scope := nd.getNodeScope()
+	nodes, ok := s.scopeMap[scope]
+	if !ok {
+		nodes = make(map[string]*NodeDelegate)
+		s.scopeMap[scope] = nodes
+	}
+	nodes[nd.getNodeName()] = nd
+	s.nodeMap[nd.getId()] = &nodeInfo{maxLink: maxLink}
+	s.addNodeStats(1)
+	if !isRemoteNode(nd) {
+		if s.localScopes[scope]++; s.localScopes[scope] == 1 {
+			for _, r := range s.remotes {
+				r.advertise(FrameScopeAdd, scope)
+			}
+		}
+	}
+}
+
+func (s *shard) delNode(nd *NodeDelegate) {
+	scope := nd.getNodeScope()
+	if nodes, ok := s.scopeMap[scope]; ok && nodes[nd.getNodeName()] == nd {
+		delete(nodes, nd.getNodeName())
+		if len(nodes) == 0 {
+			delete(s.scopeMap, scope)
+		}
+	}
+	delete(s.nodeMap, nd.getId())
+	s.addNodeStats(-1)
+	if !isRemoteNode(nd) {
+		if s.localScopes[scope]--; s.localScopes[scope] <= 0 {
+			delete(s.localScopes, scope)
+			for _, r := range s.remotes {
+				r.advertise(FrameScopeRemove, scope)
+			}
+		}
+	}
+}
+
+func (s *shard) getNodeInfo(nodeId string) *nodeInfo {
+	if info, exist := s.nodeMap[nodeId]; exist {
+		return info
+	}
+	return nil
+}
+
+// associate dlink to the sender
+func (s *shard) addOutputLink(l *dlink) {
+	if info := s.getNodeInfo(l.fromNode.getId()); info != nil {
+		l.fromIndex = len(info.outputLinks)
+		info.outputLinks = append(info.outputLinks, l)
+	}
+}
+
+// associate dlink to the receiver
+func (s *shard) addInputLink(l *dlink) {
+	if info := s.getNodeInfo(l.toNode.getId()); info != nil {
+		l.toIndex = len(info.inputLinks)
+		info.inputLinks = append(info.inputLinks, l)
+	}
+}
+
+// tear down a dlink in the sender, it is ok if already torn down
+func (s *shard) delOutputLink(l *dlink) {
+	if info := s.getNodeInfo(l.fromNode.getId()); info != nil {
+		if index := l.fromIndex; index >= 0 && index < len(info.outputLinks) && info.outputLinks[index] == l {
+			last := info.outputLinks[len(info.outputLinks)-1]
+			last.fromIndex = index
+			info.outputLinks[index] = last
+			info.outputLinks = info.outputLinks[:len(info.outputLinks)-1]
+			l.fromIndex = -1
+		}
+	}
+}
+
+// tear down a dlink in the receiver, it is ok if already torn down
+func (s *shard) delInputLink(l *dlink) {
+	if info := s.getNodeInfo(l.toNode.getId()); info != nil {
+		if index := l.toIndex; index >= 0 && index < len(info.inputLinks) && info.inputLinks[index] == l {
+			last := info.inputLinks[len(info.inputLinks)-1]
+			last.toIndex = index
+			info.inputLinks[index] = last
+			info.inputLinks = info.inputLinks[:len(info.inputLinks)-1]
+			l.toIndex = -1
+		}
+	}
+}
+
+func (s *shard) deliveryEvent(evt *Event) {
+	s.eventChannel <- evt
+}
+
+// post queues a peer event from another shard, never blocks
+func (s *shard) post(evt *Event) {
+	s.peerMutex.Lock()
+	s.peerEvents = append(s.peerEvents, evt)
+	s.peerMutex.Unlock()
+	select {
+	case s.peerC <- struct{}{}:
+	default:
+	}
+}
+
+// send passes the peer event to the shard, handle it right now if that is myself
+func (s *shard) send(to *shard, evt *Event) {
+	if to == s {
+		s.onEvent(evt)
+	} else {
+		to.post(evt)
+	}
+}
+
+// simply loop forever
+func (s *shard) startEventLoop(c chan int) {
+	go func() {
+		c <- 0
+		for {
+			select {
+			case evt := <-s.eventChannel:
+				s.onEvent(evt)
+			case <-s.peerC:
+				s.peerMutex.Lock()
+				events := s.peerEvents
+				s.peerEvents = nil
+				s.peerMutex.Unlock()
+				for _, evt := range events {
+					s.onEvent(evt)
+				}
+			}
+		}
+	}()
+}
+
+func (s *shard) onEvent(evt *Event) {
+	var ok bool
+	switch evt.cmd {
+	case reqNodeAdd:
+		var req *nodeAddRequest
+		if req, ok = evt.obj.(*nodeAddRequest); !ok {
+			return
+		}
+		s.onAddNode(req)
+	case reqNodeExit:
+		var req *nodeExitRequest
+		if req, ok = evt.obj.(*nodeExitRequest); !ok {
+			return
+		}
+		s.onExitNode(req)
+	case reqLinkUp:
+		var req *linkUpRequest
+		if req, ok = evt.obj.(*linkUpRequest); !ok {
+			return
+		}
+		s.onLinkUp(req)
+	case reqLinkDown:
+		var req *linkDownRequest
+		if req, ok = evt.obj.(*linkDownRequest); !ok {
+			return
+		}
+		s.onLinkDown(req)
+	case reqScopeQuery:
+		var req *scopeQueryRequest
+		if req, ok = evt.obj.(*scopeQueryRequest); !ok {
+			return
+		}
+		s.onScopeQuery(req)
+	case reqRemoteAttach:
+		var req *remoteRequest
+		if req, ok = evt.obj.(*remoteRequest); !ok {
+			return
+		}
+		s.onRemoteAttach(req)
+	case reqRemoteDetach:
+		var req *remoteRequest
+		if req, ok = evt.obj.(*remoteRequest); !ok {
+			return
+		}
+		s.onRemoteDetach(req)
+	case peerLinkAttach:
+		var req *linkUpRequest
+		if req, ok = evt.obj.(*linkUpRequest); !ok {
+			return
+		}
+		s.onLinkAttach(req)
+	case peerLinkAttached:
+		var resp *linkAttachResponse
+		if resp, ok = evt.obj.(*linkAttachResponse); !ok {
+			return
+		}
+		s.onLinkAttached(resp)
+	case peerLinkDetach:
+		var req *linkDownRequest
+		if req, ok = evt.obj.(*linkDownRequest); !ok {
+			return
+		}
+		s.delInputLink(req.link)
+	case peerLinkBroken:
+		var req *linkDownRequest
+		if req, ok = evt.obj.(*linkDownRequest); !ok {
+			return
+		}
+		s.onLinkBroken(req)
+	}
+}
+
+// add node to graph, and send node-add response to this node immediately
+func (s *shard) onAddNode(req *nodeAddRequest) {
+	s.spawnNode(req.node, req.cb)
+}
+
+func (s *shard) spawnNode(node Node, cb Callback) *NodeDelegate {
+	maxLink := defaultMaxLink
+	ps := reflect.ValueOf(node)
+
+	elem := ps.Elem()
+	if elem.Kind() == reflect.Struct {
+		field := elem.FieldByName("maxLink") // CAVEAT: change the name once NodeProperty field change accordingly
+		if field.IsValid() {
+			switch field.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				if ml := int(field.Int()); ml > 0 {
+					maxLink = ml
+				}
+			}
+		}
+	}
+	delegate := newNodeDelegate(s, node, maxLink)
+	s.addNode(delegate, maxLink)
+	// all gears up, rock it
+	go func(nd *NodeDelegate, cb Callback) {
+		nd.startEventLoop()
+		resp := newNodeAddResponse(nd, cb)
+		nd.receiveCtrl(resp)
+	}(delegate, cb)
+	return delegate
+}
+
+// node requests exiting the graph, notify all senders linking to this node
+func (s *shard) onExitNode(req *nodeExitRequest) {
+	nd := req.delegate
+	nodeInfo := s.getNodeInfo(nd.getId())
+	if nodeInfo == nil {
+		// concurrently call RequestNodeExit can have more than one
+		// exit request, but it is ok as only one exit response would
+		// be sent
+		return
+	}
+
+	// tear down all input/output links of the node, but only notify
+	// senders link-down state, as for receivers just remove links
+	// from their nodeInfo without any notification
+	for _, link := range append([]*dlink(nil), nodeInfo.inputLinks...) {
+		s.delInputLink(link)
+		s.send(link.fromNode.shard, newPeerLinkBroken(link))
+	}
+	for _, link := range append([]*dlink(nil), nodeInfo.outputLinks...) {
+		if s.linkSet[link.name] == link {
+			delete(s.linkSet, link.name)
+			s.delOutputLink(link)
+			s.addLinkStats(-1)
+			s.send(link.toNode.shard, newPeerLinkDetach(link))
+		}
+	}
+	if len(nodeInfo.inputLinks) > 0 || len(nodeInfo.outputLinks) > 0 {
+		logger.Errorf("[node]: (%v) is exiting but have inputLinks:%v,outputLinks:%v\n",
+			nd.getNodeName(), len(nodeInfo.inputLinks), len(nodeInfo.outputLinks))
+		panic("node still have active links")
+	}
+	s.delNode(nd)
+	// finally, send the last ctrl message for this node
+	nd.receiveCtrl(newNodeExitResponse())
+}
+
+// replyLinkUp notifies the sender, or the requester directly if the sender already exited the graph as its ctrl loop
+// is over
+func (s *shard) replyLinkUp(req *linkUpRequest, link *dlink, state int) {
+	if s.getNodeInfo(req.fromNode.getId()) == nil {
+		req.c <- -1
+		return
+	}
+	req.fromNode.receiveCtrl(newLinkUpResponse(link, state, req.scope, req.nodeName, req.c))
+}
+
+// request dlink to other node, decline if the sender has no free slot or dlink is duplicated, otherwise reserve it and
+// ask shard of the receiver to link up
+func (s *shard) onLinkUp(req *linkUpRequest) {
+	fromNode := req.fromNode
+	ni := s.getNodeInfo(fromNode.getId())
+	if ni == nil {
+		req.c <- -1
+		return
+	}
+	if ni.maxLink == len(ni.outputLinks)+ni.pending {
+		s.replyLinkUp(req, nil, stateNodeExceedMaxLink)
+		return
+	}
+	name := generateLinkName(fromNode.getNodeScope(), fromNode.getNodeName(), req.scope, req.nodeName)
+	if _, exist := s.linkSet[name]; exist || s.pending[name] {
+		// duplicated dlink, notify sender
+		s.replyLinkUp(req, nil, stateLinkDuplicated)
+		return
+	}
+	s.pending[name] = true
+	ni.pending++
+	s.send(s.graph.shardOf(req.scope), newPeerLinkAttach(req))
+}
+
+// decline if the receiver doesn't exist or is exiting, otherwise link the receiver, called in shard of the receiver
+func (s *shard) onLinkAttach(req *linkUpRequest) {
+	var link *dlink
+	state := stateSuccess
+	toNode := s.findNode(req.scope, req.nodeName)
+	if toNode == nil {
+		// not a local node, the peer hosting the scope is asked to link to it
+		toNode = s.findRemoteNode(req.scope, req.nodeName)
+	}
+	if toNode == nil {
+		state = stateNodeNotExist
+	} else if toNode.isExiting() {
+		// the requested node wouldn't accept this dlink-up request
+		state = stateLinkRefuse
+	} else {
+		link = newLink(s.graph, req.fromNode, toNode)
+		s.addInputLink(link)
+	}
+	s.send(req.fromNode.shard, newPeerLinkAttached(req, link, state))
+}
+
+// receiver shard replied the reserved link, called in shard of the sender
+func (s *shard) onLinkAttached(resp *linkAttachResponse) {
+	req := resp.req
+	fromNode := req.fromNode
+	delete(s.pending, generateLinkName(fromNode.getNodeScope(), fromNode.getNodeName(), req.scope, req.nodeName))
+	ni := s.getNodeInfo(fromNode.getId())
+	if ni == nil {
+		// sender exited while linking
+		if resp.link != nil {
+			s.send(resp.link.toNode.shard, newPeerLinkDetach(resp.link))
+		}
+		req.c <- -1
+		return
+	}
+	ni.pending--
+	if resp.state != stateSuccess {
+		s.replyLinkUp(req, nil, resp.state)
+		return
+	}
+	link := resp.link
+	s.addOutputLink(link)
+	s.linkSet[link.name] = link
+	s.addLinkStats(1)
+	s.replyLinkUp(req, link, stateSuccess)
+}
+
+// request breaking a dlink, such as A ----> B
+// this request comes from A(user code) who initiates the operation
+// when A don't want to send message to B anymore, called in shard of A
+func (s *shard) onLinkDown(req *linkDownRequest) {
+	link := req.link
+	fromNode := link.fromNode
+	// ensure every dlink can be torn down only once
+	if s.linkSet[link.name] != link {
+		fromNode.receiveCtrl(newLinkDownResponse(stateLinkNotExist, link))
+		return
+	}
+	delete(s.linkSet, link.name)
+	s.delOutputLink(link)
+	s.addLinkStats(-1)
+	s.send(link.toNode.shard, newPeerLinkDetach(link))
+	fromNode.receiveCtrl(newLinkDownResponse(stateSuccess, link))
+}
+
+// receiver B of A ----> B exited, notify A if the link is still alive, called in shard of A
+func (s *shard) onLinkBroken(req *linkDownRequest) {
+	link := req.link
+	if s.linkSet[link.name] != link {
+		return
+	}
+	delete(s.linkSet, link.name)
+	s.delOutputLink(link)
+	s.addLinkStats(-1)
+	link.fromNode.receiveCtrl(newLinkDownResponse(stateSuccess, link))
+}
+
+// take a snapshot of the scope, the result channel is buffered so never block graph loop
+func (s *shard) onScopeQuery(req *scopeQueryRequest) {
+	si := &ScopeInfo{}
+	seen := make(map[string]bool)
+	addLinks := func(links []*dlink) {
+		for _, l := range links {
+			if seen[l.name] {
+				continue
+			}
+			seen[l.name] = true
+			si.Links = append(si.Links, LinkInfo{
+				From:     NodeLocation{l.fromNode.getNodeScope(), l.fromNode.getNodeName()},
+				To:       NodeLocation{l.toNode.getNodeScope(), l.toNode.getNodeName()},
+				Overflow: l.overflowPolicy(),
+				Dropped:  atomic.LoadUint64(&l.dropped),
+			})
+		}
+	}
+	for _, nd := range s.scopeMap[req.scope] {
+		si.Nodes = append(si.Nodes, NodeLocation{nd.getNodeScope(), nd.getNodeName()})
+		if info := s.getNodeInfo(nd.getId()); info != nil {
+			addLinks(info.outputLinks)
+			addLinks(info.inputLinks)
+		}
+	}
+	// nodes are not kept in order of adding, sort them so the snapshot is stable
+	sort.Slice(si.Nodes, func(i, j int) bool { return si.Nodes[i].Name < si.Nodes[j].Name })
+	req.c <- si
+}
@@ -0,0 +1,229 @@
+package server
+
+import (
+	"github.com/appcrash/media/server/channel"
+	"github.com/appcrash/media/server/prom"
+	"github.com/appcrash/media/server/rpc"
+	"github.com/appcrash/media/server/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sync"
+	"time"
+)
+
+// DefaultFailoverGrace is how long a lost instance can take to re-register before its sessions are adopted by
+// another instance of its failover group
+const DefaultFailoverGrace = channel.KeepAliveTimeout
+
+const (
+	adoptTriggerRpc   = "rpc"
+	adoptTriggerGroup = "group"
+)
+
+// failover remembers the group each instance registers system channel with. when an instance is lost and doesn't
+// come back in grace, its sessions are adopted by the least loaded registered instance of the same group. sessions
+// of an instance without group wait for AdoptSessions rpc until watchdog stops them.
+type failover struct {
+	grace time.Duration // negative disables failover by group
+
+	mutex  sync.Mutex
+	closed bool
+	groups map[string]string      // instance id => group, kept after the instance is lost
+	timers map[string]*time.Timer // lost instances in grace
+}
+
+func newFailover(grace time.Duration) *failover {
+	if grace == 0 {
+		grace = DefaultFailoverGrace
+	}
+	return &failover{
+		grace:  grace,
+		groups: make(map[string]string),
+		timers: make(map[string]*time.Timer),
+	}
+}
+
+// join records group of the registered instance, and cancels its failover if it is back in grace
+func (f *failover) join(instanceId, group string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if t, exist := f.timers[instanceId]; exist {
+		t.Stop()
+		delete(f.timers, instanceId)
+		logger.Infof("instance(%v) is back in failover grace", instanceId)
+	}
+	if group == "" {
+		delete(f.groups, instanceId)
+	} else {
+		f.groups[instanceId] = group
+	}
+}
+
+// lost calls fn after grace unless the instance joins again, nothing happens if the instance has no group
+func (f *failover) lost(instanceId string, fn func()) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.closed || f.grace < 0 || f.groups[instanceId] == "" {
+		return
+	}
+	if t, exist := f.timers[instanceId]; exist {
+		t.Stop()
+	}
+	var timer *time.Timer
+	timer = time.AfterFunc(f.grace, func() {
+		f.mutex.Lock()
+		current := f.timers[instanceId] == timer && !f.closed
+		if current {
+			delete(f.timers, instanceId)
+		}
+		f.mutex.Unlock()
+		if current {
+			fn()
+		}
+	})
+	f.timers[instanceId] = timer
+}
+
+// sameGroup tells whether both instances have registered with the same failover group
+func (f *failover) sameGroup(instanceId, otherId string) bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	group := f.groups[instanceId]
+	return group != "" && group == f.groups[otherId]
+}
+
+// peers returns group of the instance and other instances in it
+func (f *failover) peers(instanceId string) (group string, peers []string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if group = f.groups[instanceId]; group == "" {
+		return
+	}
+	for id, g := range f.groups {
+		if g == group && id != instanceId {
+			peers = append(peers, id)
+		}
+	}
+	return
+}
+
+func (f *failover) close() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.closed = true
+	for id, t := range f.timers {
+		t.Stop()
+		delete(f.timers, id)
+	}
+}
+
+// OnInstanceLost implements channel.InstanceListener
+func (srv *MediaServer) OnInstanceLost(instanceId string) {
+	srv.failover.lost(instanceId, func() {
+		srv.failoverInstance(instanceId)
+	})
+}
+
+// failoverInstance hands sessions of the lost instance to the least loaded registered instance of its group
+func (srv *MediaServer) failoverInstance(instanceId string) {
+	sc := channel.GetSystemChannel()
+	if sc.HasInstance(instanceId) {
+		return
+	}
+	load := make(map[string]int)
+	for _, session := range srv.getSessions() {
+		load[session.GetInstanceId()]++
+	}
+	if load[instanceId] == 0 {
+		return
+	}
+	group, peers := srv.failover.peers(instanceId)
+	var adopter string
+	for _, peer := range peers {
+		if sc.HasInstance(peer) && (adopter == "" || load[peer] < load[adopter]) {
+			adopter = peer
+		}
+	}
+	if adopter == "" {
+		logger.Warnf("no instance of group(%v) can adopt %v sessions of lost instance(%v)", group,
+			load[instanceId], instanceId)
+		return
+	}
+	adopted := srv.adoptSessions(instanceId, nil, adopter, adoptTriggerGroup)
+	logger.Infof("instance(%v) of group(%v) adopted %v sessions of lost instance(%v)", adopter, group,
+		len(adopted), instanceId)
+}
+
+// adopt validates AdoptSessions rpc, the previous owner must be lost and the adopter must be registered so that it
+// can receive OWNERSHIP_TRANSFER. the adopter must also be in failover group of the previous owner if grouped
+func (srv *MediaServer) adopt(param *rpc.AdoptParam, grouped bool) ([]string, error) {
+	from, to := param.GetFromInstanceId(), param.GetInstanceId()
+	if from == "" || to == "" || from == to {
+		return nil, status.Error(codes.InvalidArgument, "adopter and previous owner must be different instances")
+	}
+	if grouped && !srv.failover.sameGroup(from, to) {
+		return nil, status.Errorf(codes.PermissionDenied, "instance(%v) is not in failover group of instance(%v)",
+			to, from)
+	}
+	sc := channel.GetSystemChannel()
+	if sc.HasInstance(from) {
+		return nil, status.Errorf(codes.FailedPrecondition, "instance(%v) is still registered", from)
+	}
+	if !sc.HasInstance(to) {
+		return nil, status.Errorf(codes.FailedPrecondition, "adopter instance(%v) is not registered", to)
+	}
+	return srv.adoptSessions(from, param.GetSessionId(), to, adoptTriggerRpc), nil
+}
+
+// adoptSessions transfers sessions owned by from to the adopter, only the given ones if sessionIds is not empty
+func (srv *MediaServer) adoptSessions(from string, sessionIds []string, to string, trigger string) (adopted []string) {
+	wanted := utils.NewSet[string]()
+	for _, id := range sessionIds {
+		wanted.Add(id)
+	}
+	for _, session := range srv.getSessions() {
+		id := session.sessionId.String()
+		if wanted.Size() > 0 && !wanted.Contain(id) {
+			continue
+		}
+		if !session.adopt(from, to) {
+			continue
+		}
+		adopted = append(adopted, id)
+		prom.SessionAdopted.WithLabelValues(trigger).Inc()
+		logger.Infof("session(%v) is adopted by instance(%v) from instance(%v)", id, to, from)
+		srv.lifecycle.publish(session, sessionStatusAdopted)
+		if err := channel.GetSystemChannel().NotifyInstance(&rpc.SystemEvent{
+			Cmd:        rpc.SystemCommand_OWNERSHIP_TRANSFER,
+			InstanceId: to,
+			SessionId:  id,
+			Event:      from,
+		}); err != nil {
+			logger.Errorf("session(%v): failed to notify adopter: %v", id, err)
+		}
+	}
+	return
+}
+
+// adopt changes owner of the session if it is still running and owned by from, the adopter takes over admission
+// slot and gets a full instance timeout of watchdog to report the session
+func (s *MediaSession) adopt(from, to string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.status == sessionStatusStopped {
+		return false
+	}
+	s.ownerMutex.Lock()
+	if s.instanceId != from {
+		s.ownerMutex.Unlock()
+		return false
+	}
+	s.instanceId = to
+	s.ownerMutex.Unlock()
+	if s.admitted {
+		s.server.admission.transfer(from, to)
+	}
+	s.composer.SetInstanceId(to)
+	s.watchdog.reportSessionInfo(nil)
+	return true
+}
@@ -0,0 +1,378 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/appcrash/media/server/rpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// http gateway maps json requests onto rpc methods of MediaServer, bodies are protojson of the rpc messages:
+//
+//	POST /v1/sessions                    CreateParam -> Session
+//	GET  /v1/sessions?instance_id=&status=        -> SessionList
+//	GET  /v1/sessions/{id}                        -> SessionDescription
+//	POST /v1/sessions/{id}/update        UpdateParam -> Status
+//	POST /v1/sessions/{id}/start                  -> Status
+//	POST /v1/sessions/{id}/stop                   -> Status
+//	POST /v1/sessions/{id}/actions       Action -> ActionResult
+//	GET  /v1/sessions/{id}/notify?cmd=&cmd_arg=   -> server-sent events of ActionEvent
+//	GET  /v1/events?instance_id=                  -> server-sent events of SessionEvent
+//	GET  /v1/capacity                             -> CapacityReport
+//
+// errors are responded as {"code":"PermissionDenied","message":"..."} with http status mapped from grpc code
+const (
+	gatewaySessionPath  = "/v1/sessions"
+	gatewayEventPath    = "/v1/events"
+	gatewayCapacityPath = "/v1/capacity"
+
+	gatewayMaxBodySize     = 1 << 20
+	gatewayShutdownTimeout = 5 * time.Second
+)
+
+var (
+	gatewayMarshal   = protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+	gatewayUnmarshal = protojson.UnmarshalOptions{}
+)
+
+type gateway struct {
+	srv    *MediaServer
+	auth   Authenticator
+	lis    net.Listener
+	server *http.Server
+
+	// ctx is the base of all requests, cancelled when gateway stops so that event streams end
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newGateway(srv *MediaServer, c *Config) (g *gateway, err error) {
+	var tc *tls.Config
+	if c.TLS != nil {
+		if tc, err = c.TLS.tlsConfig(); err != nil {
+			return
+		}
+	}
+	g = &gateway{srv: srv, auth: c.Authenticator}
+	if g.lis, err = net.Listen("tcp", fmt.Sprintf("%s:%d", c.HttpIp, c.HttpPort)); err != nil {
+		return
+	}
+	if tc != nil {
+		g.lis = tls.NewListener(g.lis, tc)
+	}
+	g.ctx, g.cancel = context.WithCancel(context.Background())
+	mux := http.NewServeMux()
+	mux.Handle(gatewaySessionPath, g.authenticated(g.handleSessions))
+	mux.Handle(gatewaySessionPath+"/", g.authenticated(g.handleSession))
+	mux.Handle(gatewayEventPath, g.authenticated(g.handleEvents))
+	mux.Handle(gatewayCapacityPath, g.authenticated(g.handleCapacity))
+	g.server = &http.Server{
+		Handler:     mux,
+		BaseContext: func(net.Listener) context.Context { return g.ctx },
+	}
+	return
+}
+
+func (g *gateway) serve() {
+	logger.Infof("http gateway serves on %v", g.lis.Addr())
+	if err := g.server.Serve(g.lis); err != nil && err != http.ErrServerClosed {
+		logger.Errorf("http gateway stopped with error: %v", err)
+	}
+}
+
+func (g *gateway) stop() {
+	g.cancel()
+	ctx, cancel := context.WithTimeout(context.Background(), gatewayShutdownTimeout)
+	defer cancel()
+	if err := g.server.Shutdown(ctx); err != nil {
+		logger.Warnf("http gateway shutdown: %v", err)
+	}
+}
+
+// gatewayHandler returns reply of rpc method, event streams write response by themselves and return nil reply
+type gatewayHandler func(ctx context.Context, w http.ResponseWriter, r *http.Request) (proto.Message, error)
+
+// authenticated makes request context look like an incoming rpc, so that the same authenticator and authorization
+// checks apply, i.e. "Authorization" header as metadata and tls state as peer auth info
+func (g *gateway) authenticated(h gatewayHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if g.auth != nil {
+			var err error
+			md := metadata.MD{}
+			if auth := r.Header.Get("Authorization"); auth != "" {
+				md.Set("authorization", auth)
+			}
+			p := &peer.Peer{}
+			p.Addr, _ = net.ResolveTCPAddr("tcp", r.RemoteAddr)
+			if r.TLS != nil {
+				p.AuthInfo = credentials.TLSInfo{State: *r.TLS}
+			}
+			ctx = peer.NewContext(metadata.NewIncomingContext(ctx, md), p)
+			if ctx, err = authenticate(g.auth, ctx); err != nil {
+				writeGatewayError(w, err)
+				return
+			}
+		}
+		reply, err := h(ctx, w, r)
+		if err != nil {
+			writeGatewayError(w, err)
+			return
+		}
+		if reply != nil {
+			writeGatewayReply(w, reply)
+		}
+	})
+}
+
+var errMethodNotAllowed = errors.New("method not allowed")
+
+// handleSessions serves the collection of sessions
+func (g *gateway) handleSessions(ctx context.Context, w http.ResponseWriter, r *http.Request) (proto.Message, error) {
+	switch r.Method {
+	case http.MethodPost:
+		param := &rpc.CreateParam{}
+		if err := readGatewayBody(r, param); err != nil {
+			return nil, err
+		}
+		return g.srv.PrepareSession(ctx, param)
+	case http.MethodGet:
+		query := r.URL.Query()
+		param := &rpc.ListParam{InstanceId: query.Get("instance_id")}
+		if s := query.Get("status"); s != "" {
+			param.Status = strings.Split(s, ",")
+		}
+		return g.srv.ListSessions(ctx, param)
+	}
+	return nil, errMethodNotAllowed
+}
+
+// handleSession serves /v1/sessions/{id}[/{op}]
+func (g *gateway) handleSession(ctx context.Context, w http.ResponseWriter, r *http.Request) (proto.Message, error) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, gatewaySessionPath+"/"), "/")
+	sessionId, op := parts[0], ""
+	if len(parts) > 2 || sessionId == "" {
+		return nil, status.Errorf(codes.NotFound, "no such path: %v", r.URL.Path)
+	}
+	if len(parts) == 2 {
+		op = parts[1]
+	}
+	if op == "" || op == "notify" {
+		if r.Method != http.MethodGet {
+			return nil, errMethodNotAllowed
+		}
+	} else if r.Method != http.MethodPost {
+		return nil, errMethodNotAllowed
+	}
+
+	switch op {
+	case "":
+		return g.srv.DescribeSession(ctx, &rpc.DescribeParam{SessionId: sessionId})
+	case "update":
+		param := &rpc.UpdateParam{}
+		if err := readGatewayBody(r, param); err != nil {
+			return nil, err
+		}
+		param.SessionId = sessionId
+		return g.srv.UpdateSession(ctx, param)
+	case "start":
+		return g.srv.StartSession(ctx, &rpc.StartParam{SessionId: sessionId})
+	case "stop":
+		return g.srv.StopSession(ctx, &rpc.StopParam{SessionId: sessionId})
+	case "actions":
+		action := &rpc.Action{}
+		if err := readGatewayBody(r, action); err != nil {
+			return nil, err
+		}
+		action.SessionId = sessionId
+		return g.srv.ExecuteAction(ctx, action)
+	case "notify":
+		query := r.URL.Query()
+		action := &rpc.Action{SessionId: sessionId, Cmd: query.Get("cmd"), CmdArg: query.Get("cmd_arg")}
+		es := newEventStream(ctx, w)
+		return nil, es.finish(g.srv.ExecuteActionWithNotify(action, notifyEventStream{es}))
+	}
+	return nil, status.Errorf(codes.NotFound, "no such path: %v", r.URL.Path)
+}
+
+func (g *gateway) handleEvents(ctx context.Context, w http.ResponseWriter, r *http.Request) (proto.Message, error) {
+	if r.Method != http.MethodGet {
+		return nil, errMethodNotAllowed
+	}
+	param := &rpc.WatchParam{InstanceId: r.URL.Query().Get("instance_id")}
+	if id := identityFrom(ctx); id != nil && !id.Admin && param.InstanceId == "" {
+		param.InstanceId = id.InstanceId
+	}
+	es := newEventStream(ctx, w)
+	return nil, es.finish(g.srv.WatchSessions(param, sessionEventStream{es}))
+}
+
+func (g *gateway) handleCapacity(ctx context.Context, _ http.ResponseWriter, r *http.Request) (proto.Message, error) {
+	if r.Method != http.MethodGet {
+		return nil, errMethodNotAllowed
+	}
+	return g.srv.GetCapacity(ctx, &rpc.Empty{})
+}
+
+func readGatewayBody(r *http.Request, msg proto.Message) error {
+	body, err := io.ReadAll(io.LimitReader(r.Body, gatewayMaxBodySize))
+	if err != nil {
+		return err
+	}
+	if len(body) == 0 {
+		return nil
+	}
+	if err = gatewayUnmarshal.Unmarshal(body, msg); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid json body: %v", err)
+	}
+	return nil
+}
+
+func writeGatewayReply(w http.ResponseWriter, reply proto.Message) {
+	data, err := gatewayMarshal.Marshal(reply)
+	if err != nil {
+		writeGatewayError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+func gatewayStatusOf(code codes.Code) int {
+	switch code {
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.FailedPrecondition:
+		return http.StatusPreconditionFailed
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.Canceled:
+		return 499 // client closed request
+	}
+	return http.StatusInternalServerError
+}
+
+func writeGatewayError(w http.ResponseWriter, err error) {
+	var httpStatus int
+	st := status.Convert(err)
+	if err == errMethodNotAllowed {
+		httpStatus = http.StatusMethodNotAllowed
+	} else {
+		httpStatus = gatewayStatusOf(st.Code())
+	}
+	data, _ := json.Marshal(struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}{st.Code().String(), st.Message()})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus)
+	w.Write(data)
+}
+
+// eventStream adapts server-sent events to grpc.ServerStream, so stream rpc methods can be reused. response header
+// is written at the first event, so that error before it can still be responded as normal json error.
+type eventStream struct {
+	ctx     context.Context
+	w       http.ResponseWriter
+	started bool
+}
+
+func newEventStream(ctx context.Context, w http.ResponseWriter) *eventStream {
+	return &eventStream{ctx: ctx, w: w}
+}
+
+func (es *eventStream) SetHeader(metadata.MD) error  { return nil }
+func (es *eventStream) SendHeader(metadata.MD) error { return nil }
+func (es *eventStream) SetTrailer(metadata.MD)       {}
+func (es *eventStream) Context() context.Context     { return es.ctx }
+func (es *eventStream) RecvMsg(interface{}) error    { return io.EOF }
+
+func (es *eventStream) SendMsg(m interface{}) error {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return fmt.Errorf("can not send %T as event", m)
+	}
+	if err := es.ctx.Err(); err != nil {
+		return err
+	}
+	data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	es.start()
+	if _, err = fmt.Fprintf(es.w, "data: %s\n\n", data); err != nil {
+		return err
+	}
+	if f, ok := es.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+func (es *eventStream) start() {
+	if !es.started {
+		h := es.w.Header()
+		h.Set("Content-Type", "text/event-stream")
+		h.Set("Cache-Control", "no-cache")
+		es.w.WriteHeader(http.StatusOK)
+		es.started = true
+	}
+}
+
+// finish returns error of the stream rpc if no event is sent yet, otherwise the stream just ends
+func (es *eventStream) finish(err error) error {
+	if es.started {
+		if err != nil {
+			logger.Warnf("http gateway event stream ends with error: %v", err)
+		}
+		return nil
+	}
+	if err == nil {
+		es.start()
+	}
+	return err
+}
+
+type notifyEventStream struct {
+	*eventStream
+}
+
+func (s notifyEventStream) Send(evt *rpc.ActionEvent) error {
+	return s.SendMsg(evt)
+}
+
+type sessionEventStream struct {
+	*eventStream
+}
+
+func (s sessionEventStream) Send(evt *rpc.SessionEvent) error {
+	return s.SendMsg(evt)
+}
@@ -0,0 +1,146 @@
+package server_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/appcrash/media/server"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHttpGateway(t *testing.T) {
+	httpPort := freePort(t)
+	startTestServer(t, &server.Config{
+		RtpIp:          "127.0.0.1",
+		PortQuarantine: -1,
+		HttpIp:         grpcIp,
+		HttpPort:       httpPort,
+		Authenticator: server.TokenAuthenticator{
+			"token_a": {InstanceId: "http_a"},
+			"token_b": {InstanceId: "http_b"},
+		},
+		Admission: server.AdmissionPolicy{MaxSessions: 1},
+	})
+	time.Sleep(100 * time.Millisecond)
+
+	request := func(method, path, token, body string) (*http.Response, map[string]interface{}) {
+		t.Helper()
+		req, _ := http.NewRequest(method, fmt.Sprintf("http://%v:%v%v", grpcIp, httpPort, path), strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		reply := make(map[string]interface{})
+		data, _ := io.ReadAll(resp.Body)
+		json.Unmarshal(data, &reply)
+		return resp, reply
+	}
+	expectStatus := func(resp *http.Response, reply map[string]interface{}, code int) {
+		t.Helper()
+		if resp.StatusCode != code {
+			t.Fatalf("expect http status %v but got %v: %v", code, resp.StatusCode, reply)
+		}
+	}
+	createBody := `{"peer_ip":"127.0.0.1","peer_port":44060,"graph_desc":"[echo]",
+		"codecs":[{"payload_type":"PCM_ALAW","payload_number":8}]}`
+
+	resp, reply := request(http.MethodGet, "/v1/sessions", "", "")
+	expectStatus(resp, reply, http.StatusUnauthorized)
+	if reply["code"] != "Unauthenticated" {
+		t.Fatalf("error should be in json: %v", reply)
+	}
+	resp, reply = request(http.MethodPost, "/v1/sessions", "token_a", `{"no_such_field":1}`)
+	expectStatus(resp, reply, http.StatusBadRequest)
+	resp, reply = request(http.MethodPost, "/v1/sessions", "token_a", createBody)
+	expectStatus(resp, reply, http.StatusOK)
+	sessionId, _ := reply["session_id"].(string)
+	if sessionId == "" || reply["local_rtp_port"] == nil {
+		t.Fatalf("invalid session: %v", reply)
+	}
+	resp, reply = request(http.MethodPost, "/v1/sessions", "token_b", createBody)
+	expectStatus(resp, reply, http.StatusTooManyRequests)
+	resp, reply = request(http.MethodGet, "/v1/capacity", "token_b", "")
+	expectStatus(resp, reply, http.StatusOK)
+	if reply["sessions"] != float64(1) {
+		t.Fatalf("invalid capacity report: %v", reply)
+	}
+
+	// watch events of instance, then stop the session by another instance and then its owner
+	eventC := make(chan string, 8)
+	go func() {
+		req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("http://%v:%v/v1/events", grpcIp, httpPort), nil)
+		req.Header.Set("Authorization", "token_a")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			close(eventC)
+			return
+		}
+		defer resp.Body.Close()
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if line := scanner.Text(); strings.HasPrefix(line, "data: ") {
+				eventC <- strings.TrimPrefix(line, "data: ")
+			}
+		}
+		close(eventC)
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	resp, reply = request(http.MethodPost, "/v1/sessions/"+sessionId+"/actions", "token_a",
+		`{"cmd":"exec","cmd_arg":"[echo] <-- 'hello'"}`)
+	expectStatus(resp, reply, http.StatusOK)
+	if reply["session_id"] != sessionId {
+		t.Fatalf("invalid action result: %v", reply)
+	}
+	resp, reply = request(http.MethodGet, "/v1/sessions/"+sessionId, "token_b", "")
+	expectStatus(resp, reply, http.StatusForbidden)
+	resp, reply = request(http.MethodGet, "/v1/sessions/"+sessionId+"/stop", "token_a", "")
+	expectStatus(resp, reply, http.StatusMethodNotAllowed)
+	resp, reply = request(http.MethodPost, "/v1/sessions/"+sessionId+"/stop", "token_a", "")
+	expectStatus(resp, reply, http.StatusOK)
+
+	select {
+	case evt, ok := <-eventC:
+		if !ok {
+			t.Fatal("event stream closed")
+		}
+		if !strings.Contains(evt, sessionId) || !strings.Contains(evt, "SESSION_STOPPED") {
+			t.Fatalf("unexpected event: %v", evt)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no session event received")
+	}
+}
+
+func TestHttpGatewayFailure(t *testing.T) {
+	// gateway fails to listen on port of grpc, everything made before should be released
+	port := freePort(t)
+	startPort, endPort := rtpPorts(100)
+	_, _, err := server.NewServer(&server.Config{
+		RtpIp:          "127.0.0.1",
+		StartPort:      startPort,
+		EndPort:        endPort,
+		PortQuarantine: -1,
+		GrpcIp:         grpcIp,
+		GrpcPort:       port,
+		HttpIp:         grpcIp,
+		HttpPort:       port,
+	})
+	if err == nil {
+		t.Fatal("server should fail if gateway can not listen")
+	}
+	lis, err := net.Listen("tcp", fmt.Sprintf("%v:%v", grpcIp, port))
+	if err != nil {
+		t.Fatalf("grpc port is not released: %v", err)
+	}
+	lis.Close()
+}
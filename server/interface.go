@@ -30,14 +30,16 @@ type CommandExecute interface {
 }
 
 // RtpPacketProvider provides data for RTP session
-// RTP send loop creates new packets from RtpPacketList then send them.
+// RTP send loop creates new packets from RtpPacketList then send them. packet lists pushed to the channel are owned
+// by send loop, which releases them after sending, the provider must not use them any more.
 type RtpPacketProvider interface {
 	comp.NodeTraitTag
 	PullPacketChannel() <-chan *utils.RtpPacketList
 }
 
 // RtpPacketConsumer consumes data from RTP session
-// receive loop fetches rtp data packet and feeds it to consumer
+// receive loop fetches rtp data packet and feeds it to consumer, the consumer owns received packet lists and should
+// release them when done, or hand them over to others(e.g. push back to send loop)
 type RtpPacketConsumer interface {
 	comp.NodeTraitTag
 	HandlePacketChannel() chan<- *utils.RtpPacketList
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"context"
+	"github.com/appcrash/media/server/comp"
+	"github.com/appcrash/media/server/event"
+	"github.com/appcrash/media/server/rpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/peer"
+	"sync"
+	"time"
+)
+
+// PeerRedialInterval is how long to wait before dialing a peer again once its link is broken
+const PeerRedialInterval = time.Second
+
+type peerStream interface {
+	Send(*rpc.PeerFrame) error
+	Recv() (*rpc.PeerFrame, error)
+}
+
+// peerLinks connects event graph of this server to peer media servers by PeerLink rpc, so that nodes can stream to
+// nodes of sessions hosted by peers, i.e. [mixer@room1] where room1 is a session of peer. it keeps dialing peers in
+// config and serves peers dialing in, the link is bidirectional whichever side dials.
+type peerLinks struct {
+	graph  *event.Graph
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mutex   sync.Mutex
+	remotes map[*event.Remote]bool
+}
+
+func newPeerLinks(graph *event.Graph) *peerLinks {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &peerLinks{
+		graph:   graph,
+		ctx:     ctx,
+		cancel:  cancel,
+		remotes: make(map[*event.Remote]bool),
+	}
+}
+
+// dial keeps a link to the peer until closed
+func (p *peerLinks) dial(address string, opts []grpc.DialOption) {
+	if len(opts) == 0 {
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+	conn, err := grpc.DialContext(p.ctx, address, opts...)
+	if err != nil {
+		logger.Errorf("failed to dial peer(%v): %v", address, err)
+		return
+	}
+	defer conn.Close()
+	api := rpc.NewMediaApiClient(conn)
+	for {
+		ctx, cancel := context.WithCancel(p.ctx)
+		if stream, err := api.PeerLink(ctx); err == nil {
+			p.serve(address, stream)
+		} else {
+			logger.Debugf("failed to link peer(%v): %v", address, err)
+		}
+		cancel()
+		select {
+		case <-time.After(PeerRedialInterval):
+		case <-p.ctx.Done():
+			return
+		}
+	}
+}
+
+// serve runs the stream as transport of a remote until either side breaks it
+func (p *peerLinks) serve(name string, stream peerStream) {
+	r := p.graph.NewRemote(name, comp.RemoteCodec{}, func(f *event.RemoteFrame) error {
+		return stream.Send(&rpc.PeerFrame{
+			Type:    uint32(f.Type),
+			LinkId:  f.LinkId,
+			Scope:   f.Scope,
+			Name:    f.Name,
+			Cmd:     int32(f.Cmd),
+			ReplyId: f.ReplyId,
+			Data:    f.Data,
+		})
+	})
+	p.mutex.Lock()
+	p.remotes[r] = true
+	closed := p.ctx.Err() != nil
+	p.mutex.Unlock()
+	if closed {
+		r.Close()
+	}
+	logger.Infof("peer(%v) is linked", name)
+
+	go func() {
+		for {
+			in, err := stream.Recv()
+			if err != nil {
+				logger.Infof("peer(%v) link is broken: %v", name, err)
+				r.Close()
+				return
+			}
+			r.Receive(&event.RemoteFrame{
+				Type:    int(in.Type),
+				LinkId:  in.LinkId,
+				Scope:   in.Scope,
+				Name:    in.Name,
+				Cmd:     int(in.Cmd),
+				ReplyId: in.ReplyId,
+				Data:    in.Data,
+			})
+		}
+	}()
+	<-r.Done()
+	r.Wait()
+	p.mutex.Lock()
+	delete(p.remotes, r)
+	p.mutex.Unlock()
+}
+
+// close stops dialing and breaks all links, senders to remote scopes get link down
+func (p *peerLinks) close() {
+	p.mutex.Lock()
+	p.cancel()
+	var remotes []*event.Remote
+	for r := range p.remotes {
+		remotes = append(remotes, r)
+	}
+	p.mutex.Unlock()
+	for _, r := range remotes {
+		r.Close()
+	}
+}
+
+func (srv *MediaServer) PeerLink(stream rpc.MediaApi_PeerLinkServer) error {
+	if err := authorizeAdmin(stream.Context()); err != nil {
+		return err
+	}
+	name := "unknown"
+	if pr, ok := peer.FromContext(stream.Context()); ok {
+		name = pr.Addr.String()
+	}
+	srv.peers.serve(name, stream)
+	return nil
+}
@@ -0,0 +1,134 @@
+package server_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/appcrash/media/server"
+	"github.com/appcrash/media/server/rpc"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPeerLink(t *testing.T) {
+	serverA := startTestServer(t, &server.Config{
+		RtpIp:          "127.0.0.1",
+		PortQuarantine: -1,
+	})
+	serverB := startTestServer(t, &server.Config{
+		RtpIp:          "127.0.0.1",
+		PortQuarantine: -1,
+		PeerAddresses:  []string{fmt.Sprintf("%v:%v", grpcIp, serverA.port)},
+	})
+	// servers can not stop gracefully until streams are closed
+	streamCtx, streamCancel := context.WithCancel(context.Background())
+	defer streamCancel()
+
+	clientA, clientB := serverA.client, serverB.client
+	ctx := context.Background()
+	prepare := func(c rpc.MediaApiClient, graph string) (*rpc.Session, error) {
+		return c.PrepareSession(ctx, &rpc.CreateParam{
+			PeerIp:   "127.0.0.1",
+			PeerPort: 44080,
+			Codecs: []*rpc.CodecInfo{{
+				PayloadNumber: 8,
+				PayloadType:   rpc.CodecType_PCM_ALAW,
+			}},
+			GraphDesc:  graph,
+			InstanceId: "peer",
+		})
+	}
+
+	sessionA, err := prepare(clientA, "[echo];[sink:chan_sink]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pull, err := clientA.ExecuteActionWithNotify(streamCtx, &rpc.Action{
+		SessionId: sessionA.SessionId,
+		Cmd:       "pull_stream",
+		CmdArg:    "<-chan sink",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sessionB, err := prepare(clientB, "[echo];[src:chan_src]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clientB.StopSession(ctx, &rpc.StopParam{SessionId: sessionB.SessionId})
+	// nmd scope must be an identifier, so link to session of peer at runtime. retry until peers are linked and
+	// scopes are advertised
+	var result *rpc.ActionResult
+	for i := 0; i < 50; i++ {
+		result, err = clientB.ExecuteAction(ctx, &rpc.Action{
+			SessionId: sessionB.SessionId,
+			Cmd:       "exec",
+			CmdArg:    fmt.Sprintf("[src] <-> 'conn %v sink drop_oldest'", sessionA.SessionId),
+		})
+		if err == nil && strings.HasPrefix(result.State, "ok") {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if err != nil || !strings.HasPrefix(result.State, "ok") {
+		t.Fatalf("failed to link session of peer: %v %v", result, err)
+	}
+	desc, err := clientB.DescribeSession(ctx, &rpc.DescribeParam{SessionId: sessionB.SessionId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(desc.Links) != 1 || desc.Links[0].From != "src" || desc.Links[0].To != "sink@"+sessionA.SessionId ||
+		desc.Links[0].Overflow != "drop_oldest" {
+		t.Fatalf("invalid links to remote scope: %v", desc.Links)
+	}
+
+	push, err := clientB.ExecuteActionWithPush(streamCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = push.Send(&rpc.PushData{SessionId: sessionB.SessionId, Cmd: "push_stream", NodeName: "src"}); err != nil {
+		t.Fatal(err)
+	}
+	received := make(chan string, 8)
+	go func() {
+		for {
+			evt, err := pull.Recv()
+			if err != nil {
+				close(received)
+				return
+			}
+			received <- evt.Event
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		push.Send(&rpc.PushData{SessionId: sessionB.SessionId, Cmd: "push_stream", NodeName: "src",
+			Data: []byte("cross server")})
+		select {
+		case data := <-received:
+			if data != "cross server" {
+				t.Fatalf("wrong data from peer: %v", data)
+			}
+			i = 20
+		case <-time.After(100 * time.Millisecond):
+			if i == 19 {
+				t.Fatal("data is not streamed to peer")
+			}
+		}
+	}
+
+	// the peer dies, link to remote scope is down
+	report, err := clientB.GetCapacity(ctx, &rpc.Empty{})
+	if err != nil || report.GraphLinks != 1 {
+		t.Fatalf("session should have a link to remote scope: %v %v", report, err)
+	}
+	streamCancel()
+	serverA.stop()
+	for i := 0; i < 20 && report.GetGraphLinks() != 0; i++ {
+		time.Sleep(100 * time.Millisecond)
+		report, _ = clientB.GetCapacity(ctx, &rpc.Empty{})
+	}
+	if report.GetGraphLinks() != 0 {
+		t.Fatalf("link to remote scope should be down after peer stopped: %v", report)
+	}
+}
@@ -2,20 +2,43 @@ package server
 
 import (
 	"github.com/appcrash/media/server/utils"
+	"math/rand"
 	"sync"
+	"time"
 )
 
+// PortRange is rtp ports in [Start,End)
+type PortRange struct {
+	Start, End uint16
+}
+
+type quarantinedPort struct {
+	port  uint16
+	until time.Time
+}
+
 func NewPortPool() *PortPool {
 	return &PortPool{
-		freePortSet: utils.NewSet[uint16](),
+		freeIndex:     make(map[uint16]int),
+		quarantineSet: utils.NewSet[uint16](),
+		reservedSet:   utils.NewSet[uint16](),
 	}
 }
 
+// PortPool hands out rtp ports randomly. a port put back is quarantined for a while before it can be reused, so
+// late packets of the previous session don't leak into the next one.
 type PortPool struct {
 	mutex sync.Mutex
 
-	freePortSet *utils.Set[uint16] // store rtp ports (even number)
-	start, end  uint16
+	free          []uint16 // store rtp ports (even number) ready for use
+	freeIndex     map[uint16]int
+	quarantine    []quarantinedPort // ordered by quarantine end time
+	quarantineSet *utils.Set[uint16]
+	reservedSet   *utils.Set[uint16]
+
+	quarantineTime time.Duration
+	validator      func(port uint16) error
+	start, end     uint16
 }
 
 func (p *PortPool) Init(start uint16, end uint16) {
@@ -29,32 +52,159 @@ func (p *PortPool) Init(start uint16, end uint16) {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
 	for i := start; i < end; i += 2 {
-		p.freePortSet.Add(i)
+		if !p.reservedSet.Contain(i) {
+			p.addFree(i)
+		}
 	}
 
 	p.start = start
 	p.end = end
 }
 
-func (p *PortPool) Get() (port uint16) {
+// SetQuarantine sets how long a port put back waits before reuse, zero disables quarantine
+func (p *PortPool) SetQuarantine(d time.Duration) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.quarantineTime = d
+}
+
+// SetValidator sets the function checking a port before handing it out, i.e. whether rtp/rtcp ports are bindable.
+// a port failed validation is quarantined then retried later.
+func (p *PortPool) SetValidator(f func(port uint16) error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.validator = f
+}
+
+// Reserve excludes ports in range from allocation, it can be called before or after Init
+func (p *PortPool) Reserve(r PortRange) {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
-	// if pool is empty, return 0 as this port wouldn't be used by applications
+	start := r.Start
+	if start&0x01 != 0 {
+		start += 1
+	}
+	for i := uint32(start); i < uint32(r.End); i += 2 {
+		port := uint16(i)
+		p.reservedSet.Add(port)
+		p.removeFree(port)
+	}
+}
 
-	if p.freePortSet.Size() == 0 {
-		goto noPort
+// Capacity is the number of port pairs in the pool except reserved ones
+func (p *PortPool) Capacity() (n int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for i := uint32(p.start); i < uint32(p.end); i += 2 {
+		if !p.reservedSet.Contain(uint16(i)) {
+			n++
+		}
 	}
-	port = p.freePortSet.GetAndRemove()
-noPort:
 	return
 }
 
+// Free is the number of port pairs ready for allocation
+func (p *PortPool) Free() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.releaseQuarantine(time.Now())
+	return len(p.free)
+}
+
+// Quarantined is the number of ports waiting for reuse
+func (p *PortPool) Quarantined() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.releaseQuarantine(time.Now())
+	return len(p.quarantine)
+}
+
+// overlap checks whether two pools have common port
+func (p *PortPool) overlap(other *PortPool) bool {
+	return p.start < other.end && other.start < p.end
+}
+
+func (p *PortPool) Get() (port uint16) {
+	for {
+		var validator func(port uint16) error
+		if port, validator = p.take(); port == 0 || validator == nil {
+			return
+		}
+		// validation binds sockets, don't hold the lock meanwhile
+		err := validator(port)
+		if err == nil {
+			return
+		}
+		logger.Warnf("rtp port %v is not usable: %v", port, err)
+		p.mutex.Lock()
+		p.addQuarantine(port, time.Now())
+		p.mutex.Unlock()
+	}
+}
+
+// take removes a random port from free list, if pool is empty, return 0 as this port wouldn't be used by applications
+func (p *PortPool) take() (port uint16, validator func(port uint16) error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.releaseQuarantine(time.Now())
+	if len(p.free) == 0 {
+		return 0, nil
+	}
+	port = p.free[rand.Intn(len(p.free))]
+	p.removeFree(port)
+	return port, p.validator
+}
+
 func (p *PortPool) Put(port uint16) {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
-	if p.freePortSet.Contain(port) {
+	if _, ok := p.freeIndex[port]; ok || p.quarantineSet.Contain(port) {
 		logger.Errorf("put port: %v to pool but it is already in it", port)
 		return
 	}
-	p.freePortSet.Add(port)
+	if p.reservedSet.Contain(port) {
+		return
+	}
+	if p.quarantineTime > 0 {
+		p.addQuarantine(port, time.Now())
+	} else {
+		p.addFree(port)
+	}
+}
+
+func (p *PortPool) addFree(port uint16) {
+	p.freeIndex[port] = len(p.free)
+	p.free = append(p.free, port)
+}
+
+func (p *PortPool) removeFree(port uint16) {
+	i, ok := p.freeIndex[port]
+	if !ok {
+		return
+	}
+	last := len(p.free) - 1
+	p.free[i] = p.free[last]
+	p.freeIndex[p.free[i]] = i
+	p.free = p.free[:last]
+	delete(p.freeIndex, port)
+}
+
+func (p *PortPool) addQuarantine(port uint16, now time.Time) {
+	p.quarantine = append(p.quarantine, quarantinedPort{port, now.Add(p.quarantineTime)})
+	p.quarantineSet.Add(port)
+}
+
+// releaseQuarantine moves ports whose quarantine ends to free list
+func (p *PortPool) releaseQuarantine(now time.Time) {
+	i := 0
+	for ; i < len(p.quarantine) && !now.Before(p.quarantine[i].until); i++ {
+		port := p.quarantine[i].port
+		p.quarantineSet.Remove(port)
+		if !p.reservedSet.Contain(port) {
+			p.addFree(port)
+		}
+	}
+	if i > 0 {
+		p.quarantine = append(p.quarantine[:0], p.quarantine[i:]...)
+	}
 }
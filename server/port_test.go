@@ -0,0 +1,93 @@
+package server
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPortPoolRandomAndReserve(t *testing.T) {
+	p := NewPortPool()
+	p.Reserve(PortRange{Start: 10010, End: 10020})
+	p.Init(10000, 10100)
+	if c := p.Capacity(); c != 45 {
+		t.Fatalf("capacity should be 45, got %v", c)
+	}
+	var order []uint16
+	got := make(map[uint16]bool)
+	for {
+		port := p.Get()
+		if port == 0 {
+			break
+		}
+		if port&0x01 != 0 || port < 10000 || port >= 10100 || (port >= 10010 && port < 10020) || got[port] {
+			t.Fatalf("invalid port allocated: %v", port)
+		}
+		got[port] = true
+		order = append(order, port)
+	}
+	if len(order) != 45 {
+		t.Fatalf("should allocate 45 ports, got %v", len(order))
+	}
+	sequential := true
+	for i := 1; i < len(order); i++ {
+		if order[i] != order[i-1]+2 {
+			sequential = false
+		}
+	}
+	if sequential {
+		t.Fatal("ports should be allocated randomly")
+	}
+	p.Put(10012)
+	if port := p.Get(); port != 0 {
+		t.Fatalf("reserved port should not be reused: %v", port)
+	}
+}
+
+func TestPortPoolQuarantine(t *testing.T) {
+	p := NewPortPool()
+	p.Init(10000, 10002)
+	p.SetQuarantine(100 * time.Millisecond)
+	port := p.Get()
+	if port != 10000 {
+		t.Fatalf("invalid port: %v", port)
+	}
+	p.Put(port)
+	p.Put(port)
+	if p.Quarantined() != 1 {
+		t.Fatalf("port should be quarantined once")
+	}
+	if p.Get() != 0 {
+		t.Fatal("quarantined port should not be allocated")
+	}
+	time.Sleep(150 * time.Millisecond)
+	if p.Quarantined() != 0 {
+		t.Fatal("expired quarantine should not be counted")
+	}
+	if p.Get() != port {
+		t.Fatal("port should be released after quarantine")
+	}
+	p.SetQuarantine(0)
+	p.Put(port)
+	if p.Quarantined() != 0 || p.Get() != port {
+		t.Fatal("zero quarantine should disable it")
+	}
+}
+
+func TestPortPoolValidator(t *testing.T) {
+	p := NewPortPool()
+	p.Init(10000, 10004)
+	p.SetQuarantine(time.Hour)
+	p.SetValidator(func(port uint16) error {
+		if port == 10000 {
+			return errors.New("address in use")
+		}
+		return nil
+	})
+	if port := p.Get(); port != 10002 {
+		t.Fatalf("only valid port should be allocated: %v", port)
+	}
+	if p.Get() != 0 || p.Quarantined() != 1 {
+		t.Fatal("invalid port should be quarantined")
+	}
+}
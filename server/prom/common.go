@@ -2,6 +2,7 @@ package prom
 
 import (
 	"github.com/prometheus/client_golang/prometheus"
+	"sync"
 )
 
 var (
@@ -21,6 +22,18 @@ var (
 		Name: "node_graph_links",
 		Help: "Link number in all graph",
 	})
+	NodeGraphRemotePeers = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "node_graph_remote_peers",
+		Help: "Peer media servers connected to graph for remote scopes",
+	})
+	NodeGraphRemoteDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "node_graph_remote_dropped",
+		Help: "Events dropped on remote links, as not serializable or receiver not catching up",
+	}, []string{"reason"})
+	NodeGraphLinkDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "node_graph_link_dropped",
+		Help: "Events dropped on links as receiver not catching up, by overflow policy of the link",
+	}, []string{"policy"})
 	CreatedSession = prometheus.NewGauge(prometheus.GaugeOpts{
 		Name: "created_session",
 		Help: "Created session",
@@ -38,6 +51,10 @@ var (
 		Name: "session_action",
 		Help: "Executed action on session",
 	}, []string{"cmd", "type"})
+	SessionRtpDropped = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "session_rtp_dropped",
+		Help: "Received rtp packets dropped as graph not catching up, the newest packet is dropped",
+	})
 	SessionGoroutine = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "session_goroutine",
 		Help: "goroutine for session(send,recv,recv_ctrl)",
@@ -46,6 +63,55 @@ var (
 		Name: "used_port_pair",
 		Help: "Port pairs allocated",
 	})
+	RtpInterfacePortUsed = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rtp_interface_port_used",
+		Help: "Port pairs allocated on each rtp interface",
+	}, []string{"interface"})
+	RtpInterfacePortCapacity = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rtp_interface_port_capacity",
+		Help: "Port pairs configured on each rtp interface",
+	}, []string{"interface"})
+	// RtpInterfacePortQuarantined is computed when scraped, as ports leave quarantine without anyone touching the pool
+	RtpInterfacePortQuarantined = NewGaugeFuncVec(prometheus.GaugeOpts{
+		Name: "rtp_interface_port_quarantined",
+		Help: "Released port pairs waiting for reuse on each rtp interface",
+	}, "interface")
+	RtpInterfacePortBindFailed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "rtp_interface_port_bind_failed",
+		Help: "Port pairs failed bind validation on each rtp interface",
+	}, []string{"interface"})
+	RtpInterfacePortExhausted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "rtp_interface_port_exhausted",
+		Help: "Sessions failed to create as rtp interface runs out of port",
+	}, []string{"interface"})
+	AdmissionRejected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "admission_rejected",
+		Help: "Sessions rejected by admission control",
+	}, []string{"reason"})
+	UserEventDelivery = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "user_event_delivery",
+		Help: "User events requiring ack sent to instances, by acked or timeout",
+	}, []string{"result"})
+	SystemChannelUnacked = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "system_channel_unacked",
+		Help: "Events kept in retransmit buffer of each instance",
+	}, []string{"instance"})
+	SystemChannelQueueFull = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "system_channel_queue_full",
+		Help: "Events failed to enter send queue of each instance as it is full",
+	}, []string{"instance"})
+	SystemChannelOverflow = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "system_channel_overflow",
+		Help: "Unacked events dropped as retransmit buffer of each instance is full",
+	}, []string{"instance"})
+	SystemChannelRetransmit = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "system_channel_retransmit",
+		Help: "Events sent again to each instance",
+	}, []string{"instance"})
+	SessionAdopted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "session_adopted",
+		Help: "Sessions of lost instances adopted by another instance, by rpc or failover group",
+	}, []string{"trigger"})
 )
 
 func InitCollector() {
@@ -54,15 +120,70 @@ func InitCollector() {
 		NodeUserEventException,
 		NodeGraphNodes,
 		NodeGraphLinks,
+		NodeGraphRemotePeers,
+		NodeGraphRemoteDropped,
+		NodeGraphLinkDropped,
 
 		CreatedSession,
 		StartedSession,
 		AllSession,
 		SessionAction,
 		SessionGoroutine,
+		SessionRtpDropped,
 		UsedPortPair,
+		RtpInterfacePortUsed,
+		RtpInterfacePortCapacity,
+		RtpInterfacePortQuarantined,
+		RtpInterfacePortBindFailed,
+		RtpInterfacePortExhausted,
+		AdmissionRejected,
+		UserEventDelivery,
+		SystemChannelUnacked,
+		SystemChannelQueueFull,
+		SystemChannelOverflow,
+		SystemChannelRetransmit,
+		SessionAdopted,
 	}
 	for _, c := range cs {
 		prometheus.MustRegister(c)
 	}
 }
+
+// GaugeFuncVec is a gauge with one label whose values are computed by functions when collected
+type GaugeFuncVec struct {
+	desc  *prometheus.Desc
+	mutex sync.Mutex
+	funcs map[string]func() float64
+}
+
+func NewGaugeFuncVec(opts prometheus.GaugeOpts, label string) *GaugeFuncVec {
+	return &GaugeFuncVec{
+		desc:  prometheus.NewDesc(prometheus.BuildFQName(opts.Namespace, opts.Subsystem, opts.Name), opts.Help, []string{label}, opts.ConstLabels),
+		funcs: make(map[string]func() float64),
+	}
+}
+
+// Set registers the function computing value of the label, it replaces the previous one
+func (v *GaugeFuncVec) Set(label string, f func() float64) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	v.funcs[label] = f
+}
+
+func (v *GaugeFuncVec) Delete(label string) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	delete(v.funcs, label)
+}
+
+func (v *GaugeFuncVec) Describe(ch chan<- *prometheus.Desc) {
+	ch <- v.desc
+}
+
+func (v *GaugeFuncVec) Collect(ch chan<- prometheus.Metric) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	for label, f := range v.funcs {
+		ch <- prometheus.MustNewConstMetric(v.desc, prometheus.GaugeValue, f(), label)
+	}
+}
@@ -0,0 +1,338 @@
+package server
+
+import (
+	"encoding/binary"
+	"errors"
+	"github.com/appcrash/media/server/rpc"
+	"github.com/appcrash/media/server/utils"
+	"math/rand"
+	"time"
+)
+
+// IOModel decides how rtp/rtcp packets of sessions are received and sent
+type IOModel int
+
+const (
+	// IOModelGoroutine runs a GoRTP stack with its own goroutines plus recv/send/rtcp loops for every started session
+	IOModelGoroutine IOModel = iota
+	// IOModelReactor multiplexes sockets of all sessions on a small pool of workers, no goroutine is spawned for
+	// any session. the graph edge(RtpPacketProvider/RtpPacketConsumer) and watchdog reporting remain the same.
+	IOModelReactor
+)
+
+// RtpTransport decides how reactor workers move packets through sockets
+type RtpTransport int
+
+const (
+	// RtpTransportUDP moves one datagram per syscall, it is always rtp.TransportUDP in goroutine io model
+	RtpTransportUDP RtpTransport = iota
+	// RtpTransportBatch moves batches of rtp packets per syscall with recvmmsg/sendmmsg(linux only), sends can be
+	// further offloaded with gso. it requires IOModelReactor.
+	RtpTransportBatch
+)
+
+func (t RtpTransport) String() string {
+	switch t {
+	case RtpTransportUDP:
+		return "udp"
+	case RtpTransportBatch:
+		return "batch"
+	}
+	return "unknown"
+}
+
+const (
+	// reactorPollInterval is the longest time a worker waits for socket events, packets pulled from graph are
+	// sent at least once in this interval
+	reactorPollInterval = 5 * time.Millisecond
+	reactorRtcpInterval = 5 * time.Second
+	// reactorPumpLimit is the max packet lists pulled from one session in one round, to be fair to other sessions
+	reactorPumpLimit     = 64
+	reactorMaxPacketSize = 1500
+)
+
+const (
+	rtpVersion      = 2
+	rtpHeaderLength = 12
+	rtcpSR          = 200
+	rtcpRR          = 201
+	rtcpSdes        = 202
+	rtcpBye         = 203
+	rtcpSdesCname   = 1
+	// seconds between 1900(ntp epoch) and 1970(unix epoch)
+	ntpEpochOffset = 2208988800
+)
+
+var errInvalidRtpPacket = errors.New("invalid rtp packet")
+
+// reactorStream is the rtp endpoint of a session in reactor io model, it is exclusively accessed by the worker
+// it is registered to, except opening/closing the connection
+type reactorStream struct {
+	session *MediaSession
+	conn    *reactorConn
+	cname   string
+
+	handleC chan<- *utils.RtpPacketList
+	pullC   <-chan *utils.RtpPacketList
+
+	// sending state, the same as what GoRTP does for an output stream
+	ssrc                      uint32
+	sequence                  uint16
+	initialStamp              uint32
+	lastStamp                 uint32
+	nbSentPacket, nbSentOctet uint32
+	lastRtcpTime              time.Time
+	sendBuffer                []byte
+
+	nbRecvReport, nbSendReport int
+	byeReceived                bool
+
+	round uint64        // the last round of worker that found this stream ready
+	doneC chan struct{} // closed by worker once the stream is removed
+}
+
+func newReactorStream(s *MediaSession, conn *reactorConn) *reactorStream {
+	return &reactorStream{
+		session:      s,
+		conn:         conn,
+		cname:        "media@" + s.localIp.String(),
+		ssrc:         rand.Uint32(),
+		sequence:     uint16(rand.Uint32()),
+		initialStamp: rand.Uint32() & 0xfffffff,
+		sendBuffer:   make([]byte, reactorMaxPacketSize),
+		doneC:        make(chan struct{}),
+	}
+}
+
+// attach binds the stream to graph edges, it is called right before registering to a worker
+func (rs *reactorStream) attach() {
+	rs.handleC = rs.session.handleC
+	rs.pullC = rs.session.pullC
+	rs.lastRtcpTime = time.Now()
+	rs.session.watchdog.reportLoopInfo(receiveLoop)
+	rs.session.watchdog.reportLoopInfo(sendLoop)
+}
+
+// detach is called by worker when the stream is removed, notify packet handler like receive loop does
+func (rs *reactorStream) detach() {
+	rs.flush()
+	if !rs.byeReceived {
+		rs.sendRtcp(time.Now(), true)
+	}
+	if rs.handleC != nil {
+		close(rs.handleC)
+		rs.handleC = nil
+		rs.session.handleC = nil
+	}
+	rs.pullC = nil
+}
+
+// onData parses received rtp packet and nonblock pushes it to handler
+func (rs *reactorStream) onData(packet []byte) {
+	if rs.handleC == nil {
+		return
+	}
+	pl, err := parseRtpPacket(packet)
+	if err != nil {
+		return
+	}
+	rs.session.pushReceived(rs.handleC, pl)
+	rs.nbRecvReport++
+	if rs.nbRecvReport > ReportInfoPacketInterval {
+		rs.nbRecvReport = 0
+		rs.session.watchdog.reportLoopInfo(receiveLoop)
+	}
+}
+
+// onCtrl checks compound rtcp packet, only BYE is interested
+func (rs *reactorStream) onCtrl(packet []byte) {
+	for len(packet) >= 4 {
+		if packet[0]>>6 != rtpVersion {
+			return
+		}
+		length := (int(binary.BigEndian.Uint16(packet[2:])) + 1) * 4
+		if length > len(packet) {
+			return
+		}
+		if packet[1] == rtcpBye && !rs.byeReceived {
+			// peer send bye, stop the session
+			logger.Debugf("session: %v rtp peer says bye", rs.session.sessionId)
+			rs.byeReceived = true
+			go rs.session.stop(rpc.StopReason_PEER_BYE) // CAVEAT: stop() waits for the worker removing this stream
+			return
+		}
+		packet = packet[length:]
+	}
+}
+
+// hasOutput tells whether packets pulled from graph are waiting to be sent
+func (rs *reactorStream) hasOutput() bool {
+	return len(rs.pullC) > 0
+}
+
+// pump drains packets pulled from graph without blocking, then sends them out
+func (rs *reactorStream) pump() {
+	defer rs.flush()
+	for i := 0; i < reactorPumpLimit && rs.pullC != nil; i++ {
+		select {
+		case packetList, more := <-rs.pullC:
+			if !more {
+				rs.pullC = nil
+				return
+			}
+			if packetList == nil {
+				continue
+			}
+			// for video, a frame can have more than one packet with same timestamp
+			packetList.Iterate(func(p *utils.RtpPacketList) {
+				if p.Payload != nil {
+					rs.sendData(p)
+				}
+				rs.nbSendReport++
+			})
+			packetList.Release()
+			if rs.nbSendReport > ReportInfoPacketInterval {
+				rs.nbSendReport = 0
+				rs.session.watchdog.reportLoopInfo(sendLoop)
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (rs *reactorStream) flush() {
+	if err := rs.conn.flush(); err != nil {
+		rs.session.watchdog.reportLoopError(sendLoop, err)
+	}
+}
+
+func (rs *reactorStream) sendData(p *utils.RtpPacketList) {
+	if rtpHeaderLength+len(p.Payload) > len(rs.sendBuffer) {
+		rs.session.watchdog.reportLoopError(sendLoop, errors.New("rtp payload too large"))
+		return
+	}
+	buf := rs.sendBuffer
+	buf[0] = rtpVersion << 6
+	//maybe update pt by sip/sdp after create graph
+	buf[1] = rs.session.avPayloadNumber & 0x7f
+	if p.Marker {
+		buf[1] |= 0x80
+	}
+	stamp := p.Pts + rs.initialStamp
+	binary.BigEndian.PutUint16(buf[2:], rs.sequence)
+	binary.BigEndian.PutUint32(buf[4:], stamp)
+	binary.BigEndian.PutUint32(buf[8:], rs.ssrc)
+	n := copy(buf[rtpHeaderLength:], p.Payload)
+	if err := rs.conn.writeData(buf[:rtpHeaderLength+n]); err != nil {
+		rs.session.watchdog.reportLoopError(sendLoop, err)
+		return
+	}
+	rs.sequence++
+	rs.lastStamp = stamp
+	rs.nbSentPacket++
+	rs.nbSentOctet += uint32(n)
+	rs.session.stats.sent(n)
+}
+
+// tick sends rtcp report periodically
+func (rs *reactorStream) tick(now time.Time) {
+	if now.Sub(rs.lastRtcpTime) >= reactorRtcpInterval {
+		rs.sendRtcp(now, false)
+	}
+}
+
+// sendRtcp sends compound packet of SR(or empty RR if nothing sent), SDES with CNAME, and optional BYE
+func (rs *reactorStream) sendRtcp(now time.Time, bye bool) {
+	rs.lastRtcpTime = now
+	buf := rs.sendBuffer
+	var n int
+	if rs.nbSentPacket > 0 {
+		sec := uint64(now.Unix()) + ntpEpochOffset
+		frac := (uint64(now.Nanosecond()) << 32) / uint64(time.Second)
+		buf[0] = rtpVersion << 6
+		buf[1] = rtcpSR
+		binary.BigEndian.PutUint16(buf[2:], 6)
+		binary.BigEndian.PutUint32(buf[4:], rs.ssrc)
+		binary.BigEndian.PutUint32(buf[8:], uint32(sec))
+		binary.BigEndian.PutUint32(buf[12:], uint32(frac))
+		binary.BigEndian.PutUint32(buf[16:], rs.lastStamp)
+		binary.BigEndian.PutUint32(buf[20:], rs.nbSentPacket)
+		binary.BigEndian.PutUint32(buf[24:], rs.nbSentOctet)
+		n = 28
+	} else {
+		buf[0] = rtpVersion << 6
+		buf[1] = rtcpRR
+		binary.BigEndian.PutUint16(buf[2:], 1)
+		binary.BigEndian.PutUint32(buf[4:], rs.ssrc)
+		n = 8
+	}
+
+	// SDES: one chunk with CNAME item, padded to 32-bit boundary with at least one null octet
+	cname := rs.cname
+	if len(cname) > 255 {
+		cname = cname[:255]
+	}
+	sdesLength := 4 + 4 + 2 + len(cname) + 1
+	sdesLength = (sdesLength + 3) &^ 3
+	sdes := buf[n : n+sdesLength]
+	for i := range sdes {
+		sdes[i] = 0
+	}
+	sdes[0] = rtpVersion<<6 | 1
+	sdes[1] = rtcpSdes
+	binary.BigEndian.PutUint16(sdes[2:], uint16(sdesLength/4-1))
+	binary.BigEndian.PutUint32(sdes[4:], rs.ssrc)
+	sdes[8] = rtcpSdesCname
+	sdes[9] = byte(len(cname))
+	copy(sdes[10:], cname)
+	n += sdesLength
+
+	if bye {
+		buf[n] = rtpVersion<<6 | 1
+		buf[n+1] = rtcpBye
+		binary.BigEndian.PutUint16(buf[n+2:], 1)
+		binary.BigEndian.PutUint32(buf[n+4:], rs.ssrc)
+		n += 8
+	}
+	if err := rs.conn.writeCtrl(buf[:n]); err != nil {
+		logger.Debugf("session:%v send rtcp error: %v", rs.session.sessionId, err)
+	}
+}
+
+// parseRtpPacket copies packet to a pooled buffer as it is in worker's receiving buffer
+func parseRtpPacket(packet []byte) (pl *utils.RtpPacketList, err error) {
+	if len(packet) < rtpHeaderLength || packet[0]>>6 != rtpVersion {
+		return nil, errInvalidRtpPacket
+	}
+	offset := rtpHeaderLength + int(packet[0]&0x0f)*4
+	if packet[0]&0x10 != 0 {
+		// header extension
+		if offset+4 > len(packet) {
+			return nil, errInvalidRtpPacket
+		}
+		offset += 4 + int(binary.BigEndian.Uint16(packet[offset+2:]))*4
+	}
+	end := len(packet)
+	if packet[0]&0x20 != 0 {
+		// padding, the last octet is the count
+		end -= int(packet[end-1])
+	}
+	if offset > end {
+		return nil, errInvalidRtpPacket
+	}
+
+	pl = utils.NewPacketListFromBuffer(utils.NewPacketBufferFrom(packet), offset, end)
+	raw := pl.RawBuffer
+	pl.PayloadType = raw[1] & 0x7f
+	pl.Pts = binary.BigEndian.Uint32(raw[4:])
+	pl.Marker = raw[1]&0x80 != 0
+	pl.Ssrc = binary.BigEndian.Uint32(raw[8:])
+	if nbCsrc := int(raw[0] & 0x0f); nbCsrc > 0 {
+		pl.Csrc = make([]uint32, nbCsrc)
+		for i := range pl.Csrc {
+			pl.Csrc[i] = binary.BigEndian.Uint32(raw[rtpHeaderLength+i*4:])
+		}
+	}
+	return
+}
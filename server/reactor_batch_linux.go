@@ -0,0 +1,242 @@
+package server
+
+import (
+	"errors"
+	"golang.org/x/sys/unix"
+	"net"
+	"unsafe"
+)
+
+const (
+	// batchSize is the max datagrams moved by one recvmmsg/sendmmsg
+	batchSize = 32
+	// batchMaxBytes keeps queued packets in one udp datagram's limit, so they can be sent with gso
+	batchMaxBytes = 65000
+)
+
+// mmsghdr mirrors struct mmsghdr in <sys/socket.h>
+type mmsghdr struct {
+	hdr unix.Msghdr
+	len uint32
+}
+
+// probeBatchSupport checks whether kernel supports recvmmsg/sendmmsg and UDP_SEGMENT(gso, linux 4.18+)
+func probeBatchSupport() (batch, gso bool) {
+	fd, err := unix.Socket(unix.AF_INET, unix.SOCK_DGRAM|unix.SOCK_NONBLOCK|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return
+	}
+	defer unix.Close(fd)
+	var msg mmsghdr
+	_, _, e := unix.Syscall6(unix.SYS_RECVMMSG, uintptr(fd), uintptr(unsafe.Pointer(&msg)), 1,
+		unix.MSG_DONTWAIT, 0, 0)
+	batch = e != unix.ENOSYS
+	gso = unix.SetsockoptInt(fd, unix.SOL_UDP, unix.UDP_SEGMENT, 0) == nil
+	return
+}
+
+// batchReceiver reads up to batchSize datagrams with one recvmmsg, it is owned by a reactor worker
+type batchReceiver struct {
+	msgs []mmsghdr
+	iovs []unix.Iovec
+	bufs [][]byte
+}
+
+func newBatchReceiver() *batchReceiver {
+	br := &batchReceiver{
+		msgs: make([]mmsghdr, batchSize),
+		iovs: make([]unix.Iovec, batchSize),
+		bufs: make([][]byte, batchSize),
+	}
+	for i := range br.msgs {
+		br.bufs[i] = make([]byte, reactorMaxPacketSize)
+		br.iovs[i].Base = &br.bufs[i][0]
+		br.iovs[i].SetLen(reactorMaxPacketSize)
+		br.msgs[i].hdr.Iov = &br.iovs[i]
+		br.msgs[i].hdr.SetIovlen(1)
+	}
+	return br
+}
+
+// read returns the number of received datagrams, fetch them by packet()
+func (br *batchReceiver) read(fd int) (int, error) {
+	r, _, e := unix.Syscall6(unix.SYS_RECVMMSG, uintptr(fd), uintptr(unsafe.Pointer(&br.msgs[0])),
+		uintptr(len(br.msgs)), unix.MSG_DONTWAIT, 0, 0)
+	if e != 0 {
+		return 0, e
+	}
+	return int(r), nil
+}
+
+func (br *batchReceiver) packet(i int) []byte {
+	return br.bufs[i][:br.msgs[i].len]
+}
+
+// batchSender queues packets back-to-back in one buffer, then sends them with one sendmmsg. if gso is enabled,
+// every run of equal-size packets is sent as one datagram with UDP_SEGMENT and split by kernel(or nic).
+type batchSender struct {
+	fd      int
+	gso     bool
+	to      unix.Sockaddr
+	name    unix.RawSockaddrInet6 // large enough for both ipv4 and ipv6
+	namelen uint32
+
+	data    []byte
+	offsets []int // end offset of each packet in data
+	msgs    []mmsghdr
+	iovs    []unix.Iovec
+	oob     []byte
+}
+
+func newBatchSender(fd int, gso bool) *batchSender {
+	bs := &batchSender{
+		fd:      fd,
+		gso:     gso,
+		data:    make([]byte, 0, batchMaxBytes),
+		offsets: make([]int, 0, batchSize),
+		msgs:    make([]mmsghdr, batchSize),
+		iovs:    make([]unix.Iovec, batchSize),
+		oob:     make([]byte, unix.CmsgSpace(2)),
+	}
+	h := (*unix.Cmsghdr)(unsafe.Pointer(&bs.oob[0]))
+	h.Level = unix.SOL_UDP
+	h.Type = unix.UDP_SEGMENT
+	h.SetLen(unix.CmsgLen(2))
+	return bs
+}
+
+func (bs *batchSender) setRemote(ip net.IP, port int) error {
+	bs.name = unix.RawSockaddrInet6{}
+	if ip4 := ip.To4(); ip4 != nil {
+		sa := (*unix.RawSockaddrInet4)(unsafe.Pointer(&bs.name))
+		sa.Family = unix.AF_INET
+		sa.Port = htons(port)
+		copy(sa.Addr[:], ip4)
+		bs.namelen = unix.SizeofSockaddrInet4
+		to := &unix.SockaddrInet4{Port: port}
+		copy(to.Addr[:], ip4)
+		bs.to = to
+		return nil
+	}
+	if ip16 := ip.To16(); ip16 != nil {
+		bs.name.Family = unix.AF_INET6
+		bs.name.Port = htons(port)
+		copy(bs.name.Addr[:], ip16)
+		bs.namelen = unix.SizeofSockaddrInet6
+		to := &unix.SockaddrInet6{Port: port}
+		copy(to.Addr[:], ip16)
+		bs.to = to
+		return nil
+	}
+	return errors.New("invalid ip address")
+}
+
+// htons converts port to network byte order in memory
+func htons(port int) uint16 {
+	var p uint16
+	b := (*[2]byte)(unsafe.Pointer(&p))
+	b[0], b[1] = byte(port>>8), byte(port)
+	return p
+}
+
+// queue copies packet into sending buffer, packets already queued are flushed if buffer is full
+func (bs *batchSender) queue(b []byte) (err error) {
+	if len(bs.offsets) == batchSize || len(bs.data)+len(b) > batchMaxBytes {
+		err = bs.flush()
+	}
+	bs.data = append(bs.data, b...)
+	bs.offsets = append(bs.offsets, len(bs.data))
+	return
+}
+
+func (bs *batchSender) flush() (err error) {
+	if len(bs.offsets) == 0 {
+		return
+	}
+	defer func() {
+		bs.data = bs.data[:0]
+		bs.offsets = bs.offsets[:0]
+	}()
+	if bs.namelen == 0 {
+		return
+	}
+	start := 0
+	if bs.gso {
+		if start, err = bs.sendSegments(); err == nil {
+			return
+		}
+		if err != unix.EIO && err != unix.EINVAL && err != unix.ENOPROTOOPT {
+			return
+		}
+		// device or kernel can't do segmentation, disable it for this socket
+		logger.Warnf("udp gso is not usable(%v), fall back to sendmmsg", err)
+		bs.gso = false
+	}
+	return bs.sendBatch(start)
+}
+
+// sendSegments sends every run of equal-size packets(the last one can be shorter) with one sendmsg, returns index of
+// the first packet not sent if error occurs
+func (bs *batchSender) sendSegments() (int, error) {
+	i := 0
+	for i < len(bs.offsets) {
+		begin := bs.packetBegin(i)
+		segment := bs.offsets[i] - begin
+		j := i + 1
+		for j < len(bs.offsets) {
+			size := bs.offsets[j] - bs.offsets[j-1]
+			if size > segment {
+				break
+			}
+			j++
+			if size < segment {
+				// a shorter one ends the run
+				break
+			}
+		}
+		var oob []byte
+		if j-i > 1 {
+			*(*uint16)(unsafe.Pointer(&bs.oob[unix.CmsgLen(0)])) = uint16(segment)
+			oob = bs.oob
+		}
+		if _, err := unix.SendmsgN(bs.fd, bs.data[begin:bs.offsets[j-1]], oob, bs.to, 0); err != nil {
+			return i, err
+		}
+		i = j
+	}
+	return i, nil
+}
+
+// sendBatch sends packets from index start with sendmmsg
+func (bs *batchSender) sendBatch(start int) error {
+	n := len(bs.offsets) - start
+	for i := 0; i < n; i++ {
+		begin := bs.packetBegin(start + i)
+		bs.iovs[i].Base = &bs.data[begin]
+		bs.iovs[i].SetLen(bs.offsets[start+i] - begin)
+		hdr := &bs.msgs[i].hdr
+		hdr.Name = (*byte)(unsafe.Pointer(&bs.name))
+		hdr.Namelen = bs.namelen
+		hdr.Iov = &bs.iovs[i]
+		hdr.SetIovlen(1)
+	}
+	for sent := 0; sent < n; {
+		r, _, e := unix.Syscall6(unix.SYS_SENDMMSG, uintptr(bs.fd), uintptr(unsafe.Pointer(&bs.msgs[sent])),
+			uintptr(n-sent), 0, 0, 0)
+		if e != 0 {
+			if e == unix.EINTR {
+				continue
+			}
+			return e
+		}
+		sent += int(r)
+	}
+	return nil
+}
+
+func (bs *batchSender) packetBegin(i int) int {
+	if i == 0 {
+		return 0
+	}
+	return bs.offsets[i-1]
+}
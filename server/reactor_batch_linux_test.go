@@ -0,0 +1,189 @@
+package server
+
+import (
+	"golang.org/x/sys/unix"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// a PCMA frame of 20ms with rtp header
+const benchPacketSize = rtpHeaderLength + 160
+
+func benchSocketPair(b *testing.B) (sender, receiver int, port int) {
+	var err error
+	ip := net.ParseIP("127.0.0.1")
+	if receiver, err = bindUdp(ip, 0); err != nil {
+		b.Fatal(err)
+	}
+	unix.SetsockoptInt(receiver, unix.SOL_SOCKET, unix.SO_RCVBUF, 4<<20)
+	sa, _ := syscall.Getsockname(receiver)
+	port = sa.(*syscall.SockaddrInet4).Port
+	if sender, err = bindUdp(ip, 0); err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() {
+		syscall.Close(sender)
+		syscall.Close(receiver)
+	})
+	return
+}
+
+func reportPacketRate(b *testing.B, elapsed time.Duration) {
+	b.ReportMetric(float64(b.N)/elapsed.Seconds(), "pkts/s")
+}
+
+func BenchmarkSendSendto(b *testing.B) {
+	sender, _, port := benchSocketPair(b)
+	to, _, _ := toSockaddr(net.ParseIP("127.0.0.1"), port)
+	packet := make([]byte, benchPacketSize)
+	b.ResetTimer()
+	begin := time.Now()
+	for i := 0; i < b.N; i++ {
+		if err := syscall.Sendto(sender, packet, 0, to); err != nil {
+			b.Fatal(err)
+		}
+	}
+	reportPacketRate(b, time.Since(begin))
+}
+
+func benchmarkBatchSend(b *testing.B, gso bool) {
+	if _, gsoOk := probeBatchSupport(); gso && !gsoOk {
+		b.Skip("kernel doesn't support udp gso")
+	}
+	sender, _, port := benchSocketPair(b)
+	bs := newBatchSender(sender, gso)
+	bs.setRemote(net.ParseIP("127.0.0.1"), port)
+	packet := make([]byte, benchPacketSize)
+	b.ResetTimer()
+	begin := time.Now()
+	for i := 0; i < b.N; i++ {
+		if err := bs.queue(packet); err != nil {
+			b.Fatal(err)
+		}
+	}
+	if err := bs.flush(); err != nil {
+		b.Fatal(err)
+	}
+	reportPacketRate(b, time.Since(begin))
+}
+
+func BenchmarkSendSendmmsg(b *testing.B) {
+	benchmarkBatchSend(b, false)
+}
+
+func BenchmarkSendGSO(b *testing.B) {
+	benchmarkBatchSend(b, true)
+}
+
+// receiving benchmarks fill receiver's socket buffer with a round of packets, then time reading them out
+const benchRecvRound = 1024
+
+func fillReceiver(b *testing.B, sender, port, n int) {
+	to, _, _ := toSockaddr(net.ParseIP("127.0.0.1"), port)
+	packet := make([]byte, benchPacketSize)
+	for i := 0; i < n; i++ {
+		if err := syscall.Sendto(sender, packet, 0, to); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkRecv(b *testing.B, read func(fd int) int) {
+	sender, receiver, port := benchSocketPair(b)
+	var elapsed time.Duration
+	b.ResetTimer()
+	for received := 0; received < b.N; {
+		b.StopTimer()
+		round := benchRecvRound
+		if b.N-received < round {
+			round = b.N - received
+		}
+		fillReceiver(b, sender, port, round)
+		b.StartTimer()
+		begin := time.Now()
+		for n := 0; n < round; {
+			nb := read(receiver)
+			if nb == 0 {
+				b.Fatal("receiver socket drained, packets lost")
+			}
+			n += nb
+		}
+		elapsed += time.Since(begin)
+		received += round
+	}
+	reportPacketRate(b, elapsed)
+}
+
+func BenchmarkRecvRecvfrom(b *testing.B) {
+	buf := make([]byte, reactorMaxPacketSize)
+	benchmarkRecv(b, func(fd int) int {
+		if _, _, err := syscall.Recvfrom(fd, buf, 0); err != nil {
+			return 0
+		}
+		return 1
+	})
+}
+
+func BenchmarkRecvRecvmmsg(b *testing.B) {
+	br := newBatchReceiver()
+	benchmarkRecv(b, func(fd int) int {
+		n, err := br.read(fd)
+		if err != nil {
+			return 0
+		}
+		return n
+	})
+}
+
+func TestBatchSender(t *testing.T) {
+	_, gsoOk := probeBatchSupport()
+	for _, gso := range []bool{false, gsoOk} {
+		ip := net.ParseIP("127.0.0.1")
+		receiver, err := bindUdp(ip, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sender, err := bindUdp(ip, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sa, _ := syscall.Getsockname(receiver)
+		bs := newBatchSender(sender, gso)
+		bs.setRemote(ip, sa.(*syscall.SockaddrInet4).Port)
+
+		// runs of equal-size packets are split by shorter or longer ones
+		sizes := []int{100, 100, 100, 50, 100, 200, 200}
+		for i, size := range sizes {
+			packet := make([]byte, size)
+			packet[0] = byte(i)
+			if err = bs.queue(packet); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err = bs.flush(); err != nil {
+			t.Fatal(err)
+		}
+		br := newBatchReceiver()
+		var received int
+		for retry := 0; received < len(sizes) && retry < 100; retry++ {
+			n, _ := br.read(receiver)
+			for i := 0; i < n; i++ {
+				packet := br.packet(i)
+				if len(packet) != sizes[received] || packet[0] != byte(received) {
+					t.Fatalf("gso(%v): packet %v has wrong size %v or index %v", gso, received, len(packet), packet[0])
+				}
+				received++
+			}
+			if n == 0 {
+				time.Sleep(time.Millisecond)
+			}
+		}
+		if received != len(sizes) {
+			t.Fatalf("gso(%v): only %v packets received", gso, received)
+		}
+		syscall.Close(sender)
+		syscall.Close(receiver)
+	}
+}
@@ -0,0 +1,397 @@
+package server
+
+import (
+	"errors"
+	"github.com/appcrash/media/server/prom"
+	"github.com/prometheus/client_golang/prometheus"
+	"net"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+const (
+	reactorCmdAdd = iota
+	reactorCmdRemove
+	reactorCmdQuit
+)
+
+// reactorReadLimit is the max datagrams read from one socket in one round, epoll is level-triggered so the rest
+// will be reported again
+const reactorReadLimit = 32
+
+// reactorConn is a pair of nonblocking udp sockets(rtp and rtcp) of a session
+type reactorConn struct {
+	dataFd, ctrlFd         int
+	remoteData, remoteCtrl syscall.Sockaddr
+	sender                 *batchSender // rtp packets are queued then flushed in batch if not nil
+}
+
+func toSockaddr(ip net.IP, port int) (syscall.Sockaddr, int, error) {
+	if ip4 := ip.To4(); ip4 != nil {
+		sa := &syscall.SockaddrInet4{Port: port}
+		copy(sa.Addr[:], ip4)
+		return sa, syscall.AF_INET, nil
+	}
+	if ip16 := ip.To16(); ip16 != nil {
+		sa := &syscall.SockaddrInet6{Port: port}
+		copy(sa.Addr[:], ip16)
+		return sa, syscall.AF_INET6, nil
+	}
+	return nil, 0, errors.New("invalid ip address")
+}
+
+func bindUdp(ip net.IP, port int) (fd int, err error) {
+	var sa syscall.Sockaddr
+	var family int
+	if sa, family, err = toSockaddr(ip, port); err != nil {
+		return
+	}
+	if fd, err = syscall.Socket(family, syscall.SOCK_DGRAM|syscall.SOCK_NONBLOCK|syscall.SOCK_CLOEXEC, 0); err != nil {
+		return
+	}
+	if err = syscall.Bind(fd, sa); err != nil {
+		syscall.Close(fd)
+		return -1, err
+	}
+	return
+}
+
+// openConn binds rtp port and rtcp port(rtp port + 1) on local ip
+func (r *reactor) openConn(ip net.IP, port int) (c *reactorConn, err error) {
+	c = &reactorConn{dataFd: -1, ctrlFd: -1}
+	if c.dataFd, err = bindUdp(ip, port); err != nil {
+		return nil, err
+	}
+	if c.ctrlFd, err = bindUdp(ip, port+1); err != nil {
+		syscall.Close(c.dataFd)
+		return nil, err
+	}
+	if r.transport == RtpTransportBatch {
+		c.sender = newBatchSender(c.dataFd, r.gso)
+	}
+	return
+}
+
+func (c *reactorConn) setRemote(ip net.IP, port int) (err error) {
+	if c.remoteData, _, err = toSockaddr(ip, port); err != nil {
+		return
+	}
+	if c.remoteCtrl, _, err = toSockaddr(ip, port+1); err != nil {
+		return
+	}
+	if c.sender != nil {
+		err = c.sender.setRemote(ip, port)
+	}
+	return
+}
+
+func (c *reactorConn) writeData(b []byte) error {
+	if c.remoteData == nil {
+		return nil
+	}
+	if c.sender != nil {
+		return c.sender.queue(b)
+	}
+	return syscall.Sendto(c.dataFd, b, 0, c.remoteData)
+}
+
+// flush sends all queued rtp packets
+func (c *reactorConn) flush() error {
+	if c.sender == nil {
+		return nil
+	}
+	return c.sender.flush()
+}
+
+func (c *reactorConn) writeCtrl(b []byte) error {
+	if c.remoteCtrl == nil {
+		return nil
+	}
+	return syscall.Sendto(c.ctrlFd, b, 0, c.remoteCtrl)
+}
+
+func (c *reactorConn) close() {
+	if c.dataFd >= 0 {
+		syscall.Close(c.dataFd)
+		c.dataFd = -1
+	}
+	if c.ctrlFd >= 0 {
+		syscall.Close(c.ctrlFd)
+		c.ctrlFd = -1
+	}
+}
+
+type reactorCommand struct {
+	cmd    int
+	stream *reactorStream
+}
+
+type reactorFd struct {
+	stream *reactorStream
+	isCtrl bool
+}
+
+// reactorWorker owns an epoll instance, all streams registered to it are handled in its only goroutine
+type reactorWorker struct {
+	epfd     int
+	cmdC     chan reactorCommand
+	fds      map[int32]reactorFd
+	streams  map[*reactorStream]struct{}
+	nbLoad   int32 // number of streams, for load balancing
+	doneC    chan struct{}
+	receiver *batchReceiver // read rtp packets in batch if not nil
+
+	round uint64           // increased every time epoll returns
+	ready []*reactorStream // streams with sockets ready in this round
+}
+
+type reactor struct {
+	workers   []*reactorWorker
+	transport RtpTransport
+	gso       bool
+	once      sync.Once
+}
+
+// newReactor starts nbWorker workers, or the number of cpu if nbWorker is not positive. batch transport and gso
+// fall back to what kernel supports.
+func newReactor(nbWorker int, transport RtpTransport, gso bool) (r *reactor, err error) {
+	if nbWorker <= 0 {
+		nbWorker = runtime.NumCPU()
+	}
+	if transport == RtpTransportBatch {
+		batchOk, gsoOk := probeBatchSupport()
+		if !batchOk {
+			logger.Warnf("kernel doesn't support recvmmsg/sendmmsg, fall back to plain udp transport")
+			transport = RtpTransportUDP
+		}
+		if gso && !gsoOk {
+			logger.Warnf("kernel doesn't support udp gso, disable it")
+			gso = false
+		}
+	}
+	if transport != RtpTransportBatch {
+		gso = false
+	}
+	r = &reactor{transport: transport, gso: gso}
+	for i := 0; i < nbWorker; i++ {
+		var epfd int
+		if epfd, err = syscall.EpollCreate1(syscall.EPOLL_CLOEXEC); err != nil {
+			r.close()
+			return nil, err
+		}
+		w := &reactorWorker{
+			epfd:    epfd,
+			cmdC:    make(chan reactorCommand, 1024),
+			fds:     make(map[int32]reactorFd),
+			streams: make(map[*reactorStream]struct{}),
+			doneC:   make(chan struct{}),
+		}
+		if transport == RtpTransportBatch {
+			w.receiver = newBatchReceiver()
+		}
+		r.workers = append(r.workers, w)
+		go w.run()
+	}
+	logger.Infof("reactor started with %v workers, transport: %v, gso: %v", nbWorker, transport, gso)
+	return
+}
+
+// register assigns the stream to the least loaded worker
+func (r *reactor) register(rs *reactorStream) *reactorWorker {
+	var worker *reactorWorker
+	for _, w := range r.workers {
+		if worker == nil || atomic.LoadInt32(&w.nbLoad) < atomic.LoadInt32(&worker.nbLoad) {
+			worker = w
+		}
+	}
+	atomic.AddInt32(&worker.nbLoad, 1)
+	worker.cmdC <- reactorCommand{cmd: reactorCmdAdd, stream: rs}
+	return worker
+}
+
+// unregister removes the stream from its worker asynchronously, the worker closes doneC of the stream when finished
+func (r *reactor) unregister(w *reactorWorker, rs *reactorStream) {
+	w.cmdC <- reactorCommand{cmd: reactorCmdRemove, stream: rs}
+}
+
+func (r *reactor) close() {
+	r.once.Do(func() {
+		for _, w := range r.workers {
+			w.cmdC <- reactorCommand{cmd: reactorCmdQuit}
+			<-w.doneC
+		}
+	})
+}
+
+func (w *reactorWorker) run() {
+	gauge := prom.SessionGoroutine.With(prometheus.Labels{"type": "reactor"})
+	gauge.Inc()
+	defer func() {
+		gauge.Dec()
+		syscall.Close(w.epfd)
+		close(w.doneC)
+	}()
+
+	events := make([]syscall.EpollEvent, 256)
+	buf := make([]byte, reactorMaxPacketSize)
+	deadline := time.Now().Add(reactorPollInterval)
+	for {
+		timeout := int(time.Until(deadline) / time.Millisecond)
+		if timeout < 0 {
+			timeout = 0
+		}
+		n, err := syscall.EpollWait(w.epfd, events, timeout)
+		if err != nil && err != syscall.EINTR {
+			logger.Errorf("reactor epoll wait error: %v", err)
+			time.Sleep(reactorPollInterval)
+		}
+		w.round++
+		w.ready = w.ready[:0]
+		for i := 0; i < n; i++ {
+			if f, ok := w.fds[events[i].Fd]; ok {
+				w.read(f, int(events[i].Fd), buf)
+				if f.stream.round != w.round {
+					f.stream.round = w.round
+					w.ready = append(w.ready, f.stream)
+				}
+			}
+		}
+		if !w.handleCommand() {
+			return
+		}
+		// packets pulled from graph don't wake epoll up, streams received packets are likely to send some soon,
+		// the others are checked once in poll interval
+		for _, rs := range w.ready {
+			if _, ok := w.streams[rs]; ok && rs.hasOutput() {
+				w.safeCall(rs, rs.pump)
+			}
+		}
+		if now := time.Now(); !now.Before(deadline) {
+			deadline = now.Add(reactorPollInterval)
+			for rs := range w.streams {
+				w.safeCall(rs, func() {
+					if rs.hasOutput() {
+						rs.pump()
+					}
+					rs.tick(now)
+				})
+			}
+		}
+	}
+}
+
+func (w *reactorWorker) read(f reactorFd, fd int, buf []byte) {
+	if w.receiver != nil && !f.isCtrl {
+		w.readBatch(f, fd)
+		return
+	}
+	w.safeCall(f.stream, func() {
+		for i := 0; i < reactorReadLimit; i++ {
+			n, _, err := syscall.Recvfrom(fd, buf, 0)
+			if err != nil {
+				if err != syscall.EAGAIN && err != syscall.EINTR {
+					f.stream.session.watchdog.reportLoopError(receiveLoop, err)
+				}
+				return
+			}
+			if f.isCtrl {
+				f.stream.onCtrl(buf[:n])
+			} else {
+				f.stream.onData(buf[:n])
+			}
+		}
+	})
+}
+
+// readBatch reads rtp packets with recvmmsg, rtcp packets are rare so always read one by one
+func (w *reactorWorker) readBatch(f reactorFd, fd int) {
+	w.safeCall(f.stream, func() {
+		for i := 0; i < reactorReadLimit; i += batchSize {
+			n, err := w.receiver.read(fd)
+			if err != nil {
+				if err != syscall.EAGAIN && err != syscall.EINTR {
+					f.stream.session.watchdog.reportLoopError(receiveLoop, err)
+				}
+				return
+			}
+			for j := 0; j < n; j++ {
+				f.stream.onData(w.receiver.packet(j))
+			}
+			if n < batchSize {
+				// socket is drained
+				return
+			}
+		}
+	})
+}
+
+// safeCall isolates a misbehaving stream from others handled by the same worker
+func (w *reactorWorker) safeCall(rs *reactorStream, f func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("reactor: session(%v) panic %v", rs.session.sessionId, r)
+			debug.PrintStack()
+		}
+	}()
+	f()
+}
+
+// handleCommand returns false if worker should quit
+func (w *reactorWorker) handleCommand() bool {
+	for {
+		select {
+		case c := <-w.cmdC:
+			switch c.cmd {
+			case reactorCmdAdd:
+				w.add(c.stream)
+			case reactorCmdRemove:
+				w.remove(c.stream)
+			case reactorCmdQuit:
+				for rs := range w.streams {
+					w.remove(rs)
+				}
+				return false
+			}
+		default:
+			return true
+		}
+	}
+}
+
+func (w *reactorWorker) add(rs *reactorStream) {
+	conn := rs.conn
+	for _, f := range []struct {
+		fd     int
+		isCtrl bool
+	}{{conn.dataFd, false}, {conn.ctrlFd, true}} {
+		event := syscall.EpollEvent{Events: syscall.EPOLLIN, Fd: int32(f.fd)}
+		if err := syscall.EpollCtl(w.epfd, syscall.EPOLL_CTL_ADD, f.fd, &event); err != nil {
+			logger.Errorf("reactor: session(%v) add fd to epoll error: %v", rs.session.sessionId, err)
+			continue
+		}
+		w.fds[int32(f.fd)] = reactorFd{stream: rs, isCtrl: f.isCtrl}
+	}
+	w.streams[rs] = struct{}{}
+}
+
+func (w *reactorWorker) remove(rs *reactorStream) {
+	if _, ok := w.streams[rs]; !ok {
+		return
+	}
+	conn := rs.conn
+	for _, fd := range []int{conn.dataFd, conn.ctrlFd} {
+		syscall.EpollCtl(w.epfd, syscall.EPOLL_CTL_DEL, fd, nil)
+		delete(w.fds, int32(fd))
+	}
+	delete(w.streams, rs)
+	atomic.AddInt32(&w.nbLoad, -1)
+	w.safeCall(rs, func() {
+		rs.detach()
+		logger.Debugf("session:%v removed from reactor", rs.session.GetSessionId())
+	})
+	close(rs.doneC)
+}
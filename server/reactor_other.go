@@ -0,0 +1,50 @@
+//go:build !linux
+
+package server
+
+import (
+	"errors"
+	"net"
+)
+
+var errReactorNotSupported = errors.New("reactor io model is only supported on linux")
+
+type reactorConn struct{}
+
+func (c *reactorConn) setRemote(_ net.IP, _ int) error {
+	return errReactorNotSupported
+}
+
+func (c *reactorConn) writeData(_ []byte) error {
+	return errReactorNotSupported
+}
+
+func (c *reactorConn) writeCtrl(_ []byte) error {
+	return errReactorNotSupported
+}
+
+func (c *reactorConn) flush() error {
+	return errReactorNotSupported
+}
+
+func (c *reactorConn) close() {}
+
+type reactorWorker struct{}
+
+type reactor struct{}
+
+func newReactor(_ int, _ RtpTransport, _ bool) (*reactor, error) {
+	return nil, errReactorNotSupported
+}
+
+func (r *reactor) openConn(_ net.IP, _ int) (*reactorConn, error) {
+	return nil, errReactorNotSupported
+}
+
+func (r *reactor) register(_ *reactorStream) *reactorWorker {
+	return nil
+}
+
+func (r *reactor) unregister(_ *reactorWorker, _ *reactorStream) {}
+
+func (r *reactor) close() {}
@@ -0,0 +1,100 @@
+package server_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"github.com/appcrash/media/server"
+	"github.com/appcrash/media/server/rpc"
+	"net"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestReactorEchoSession(t *testing.T) {
+	testReactorEchoSession(t, server.RtpTransportUDP, false)
+}
+
+func TestReactorBatchEchoSession(t *testing.T) {
+	testReactorEchoSession(t, server.RtpTransportBatch, true)
+}
+
+func testReactorEchoSession(t *testing.T, transport server.RtpTransport, gso bool) {
+	if runtime.GOOS != "linux" {
+		t.Skip("reactor io model is only supported on linux")
+	}
+	client := startTestServer(t, &server.Config{
+		RtpIp:          "127.0.0.1",
+		IOModel:        server.IOModelReactor,
+		ReactorWorkers: 2,
+		RtpTransport:   transport,
+		RtpGSO:         gso,
+	}).client
+
+	peerData, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP("127.0.0.1")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer peerData.Close()
+	peerPort := peerData.LocalAddr().(*net.UDPAddr).Port
+
+	ctx := context.Background()
+	session, err := client.PrepareSession(ctx, &rpc.CreateParam{
+		PeerIp:   "127.0.0.1",
+		PeerPort: uint32(peerPort),
+		Codecs: []*rpc.CodecInfo{{
+			PayloadNumber: 8,
+			PayloadType:   rpc.CodecType_PCM_ALAW,
+		}},
+		GraphDesc:  "[echo]",
+		InstanceId: "reactor",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.StartSession(ctx, &rpc.StartParam{SessionId: session.SessionId}); err != nil {
+		t.Fatal(err)
+	}
+
+	remote := &net.UDPAddr{IP: net.ParseIP(session.LocalIp), Port: int(session.LocalRtpPort)}
+	payload := []byte("reactor echo payload")
+	packet := make([]byte, 12+len(payload))
+	packet[0] = 0x80
+	packet[1] = 8
+	binary.BigEndian.PutUint32(packet[8:], 0x12345678)
+	copy(packet[12:], payload)
+
+	buf := make([]byte, 1500)
+	var received int
+	for seq := 0; seq < 10; seq++ {
+		binary.BigEndian.PutUint16(packet[2:], uint16(seq))
+		binary.BigEndian.PutUint32(packet[4:], uint32(seq*160))
+		if _, err = peerData.WriteToUDP(packet, remote); err != nil {
+			t.Fatal(err)
+		}
+		peerData.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := peerData.ReadFromUDP(buf)
+		if err != nil {
+			continue
+		}
+		if n < 12 || buf[1]&0x7f != 8 || !bytes.Equal(buf[12:n], payload) {
+			t.Fatalf("invalid echoed packet: %v", buf[:n])
+		}
+		received++
+	}
+	if received == 0 {
+		t.Fatal("no packet echoed back by reactor")
+	}
+
+	// rtcp BYE from peer stops the session
+	bye := []byte{0x81, 203, 0, 1, 0x12, 0x34, 0x56, 0x78}
+	ctrlRemote := &net.UDPAddr{IP: remote.IP, Port: remote.Port + 1}
+	if _, err = peerData.WriteToUDP(bye, ctrlRemote); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if _, err = client.StopSession(ctx, &rpc.StopParam{SessionId: session.SessionId}); err == nil {
+		t.Fatal("session should have been stopped by rtcp bye")
+	}
+}
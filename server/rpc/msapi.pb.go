@@ -23,19 +23,19 @@ const (
 type Version int32
 
 const (
-	Version_DUMMY   Version = 0 // first must be zero in proto3
-	Version_DEFAULT Version = 2 // increase it every time this file being changed
+	Version_DUMMY   Version = 0  // first must be zero in proto3
+	Version_DEFAULT Version = 16 // increase it every time this file being changed
 )
 
 // Enum value maps for Version.
 var (
 	Version_name = map[int32]string{
-		0: "DUMMY",
-		2: "DEFAULT",
+		0:  "DUMMY",
+		16: "DEFAULT",
 	}
 	Version_value = map[string]int32{
 		"DUMMY":   0,
-		"DEFAULT": 2,
+		"DEFAULT": 16,
 	}
 )
 
@@ -130,28 +130,266 @@ func (CodecType) EnumDescriptor() ([]byte, []int) {
 	return file_msapi_proto_rawDescGZIP(), []int{1}
 }
 
+type WatchdogAction int32
+
+const (
+	WatchdogAction_WATCHDOG_DEFAULT WatchdogAction = 0 // use server's config
+	WatchdogAction_WATCHDOG_STOP    WatchdogAction = 1 // stop unhealthy session
+	WatchdogAction_WATCHDOG_NOTIFY  WatchdogAction = 2 // only notify instance by WATCHDOG_ALERT, keep session running
+)
+
+// Enum value maps for WatchdogAction.
+var (
+	WatchdogAction_name = map[int32]string{
+		0: "WATCHDOG_DEFAULT",
+		1: "WATCHDOG_STOP",
+		2: "WATCHDOG_NOTIFY",
+	}
+	WatchdogAction_value = map[string]int32{
+		"WATCHDOG_DEFAULT": 0,
+		"WATCHDOG_STOP":    1,
+		"WATCHDOG_NOTIFY":  2,
+	}
+)
+
+func (x WatchdogAction) Enum() *WatchdogAction {
+	p := new(WatchdogAction)
+	*p = x
+	return p
+}
+
+func (x WatchdogAction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (WatchdogAction) Descriptor() protoreflect.EnumDescriptor {
+	return file_msapi_proto_enumTypes[2].Descriptor()
+}
+
+func (WatchdogAction) Type() protoreflect.EnumType {
+	return &file_msapi_proto_enumTypes[2]
+}
+
+func (x WatchdogAction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use WatchdogAction.Descriptor instead.
+func (WatchdogAction) EnumDescriptor() ([]byte, []int) {
+	return file_msapi_proto_rawDescGZIP(), []int{2}
+}
+
+type StopReason int32
+
+const (
+	StopReason_NONE             StopReason = 0 // session is not stopped
+	StopReason_EXPLICIT_STOP    StopReason = 1 // StopSession rpc or stopped by application
+	StopReason_PEER_BYE         StopReason = 2 // rtcp BYE received from peer
+	StopReason_WATCHDOG_TIMEOUT StopReason = 3 // no packet or instance report in timeout period
+	StopReason_ERROR_THRESHOLD  StopReason = 4 // too many errors reported by rtp loops
+	StopReason_START_FAILURE    StopReason = 5
+	StopReason_SERVER_DRAIN     StopReason = 6 // still running when drain deadline passed
+)
+
+// Enum value maps for StopReason.
+var (
+	StopReason_name = map[int32]string{
+		0: "NONE",
+		1: "EXPLICIT_STOP",
+		2: "PEER_BYE",
+		3: "WATCHDOG_TIMEOUT",
+		4: "ERROR_THRESHOLD",
+		5: "START_FAILURE",
+		6: "SERVER_DRAIN",
+	}
+	StopReason_value = map[string]int32{
+		"NONE":             0,
+		"EXPLICIT_STOP":    1,
+		"PEER_BYE":         2,
+		"WATCHDOG_TIMEOUT": 3,
+		"ERROR_THRESHOLD":  4,
+		"START_FAILURE":    5,
+		"SERVER_DRAIN":     6,
+	}
+)
+
+func (x StopReason) Enum() *StopReason {
+	p := new(StopReason)
+	*p = x
+	return p
+}
+
+func (x StopReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (StopReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_msapi_proto_enumTypes[3].Descriptor()
+}
+
+func (StopReason) Type() protoreflect.EnumType {
+	return &file_msapi_proto_enumTypes[3]
+}
+
+func (x StopReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use StopReason.Descriptor instead.
+func (StopReason) EnumDescriptor() ([]byte, []int) {
+	return file_msapi_proto_rawDescGZIP(), []int{3}
+}
+
+type SessionEventType int32
+
+const (
+	SessionEventType_SESSION_CREATED SessionEventType = 0
+	SessionEventType_SESSION_UPDATED SessionEventType = 1
+	SessionEventType_SESSION_STARTED SessionEventType = 2
+	SessionEventType_SESSION_STOPPED SessionEventType = 3
+	SessionEventType_SESSION_ADOPTED SessionEventType = 4 // session is adopted from another instance by the watching one
+)
+
+// Enum value maps for SessionEventType.
+var (
+	SessionEventType_name = map[int32]string{
+		0: "SESSION_CREATED",
+		1: "SESSION_UPDATED",
+		2: "SESSION_STARTED",
+		3: "SESSION_STOPPED",
+		4: "SESSION_ADOPTED",
+	}
+	SessionEventType_value = map[string]int32{
+		"SESSION_CREATED": 0,
+		"SESSION_UPDATED": 1,
+		"SESSION_STARTED": 2,
+		"SESSION_STOPPED": 3,
+		"SESSION_ADOPTED": 4,
+	}
+)
+
+func (x SessionEventType) Enum() *SessionEventType {
+	p := new(SessionEventType)
+	*p = x
+	return p
+}
+
+func (x SessionEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SessionEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_msapi_proto_enumTypes[4].Descriptor()
+}
+
+func (SessionEventType) Type() protoreflect.EnumType {
+	return &file_msapi_proto_enumTypes[4]
+}
+
+func (x SessionEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SessionEventType.Descriptor instead.
+func (SessionEventType) EnumDescriptor() ([]byte, []int) {
+	return file_msapi_proto_rawDescGZIP(), []int{4}
+}
+
+type CommandTraitType int32
+
+const (
+	CommandTraitType_SIMPLE      CommandTraitType = 0
+	CommandTraitType_PULL_STREAM CommandTraitType = 1
+	CommandTraitType_PUSH_STREAM CommandTraitType = 2
+)
+
+// Enum value maps for CommandTraitType.
+var (
+	CommandTraitType_name = map[int32]string{
+		0: "SIMPLE",
+		1: "PULL_STREAM",
+		2: "PUSH_STREAM",
+	}
+	CommandTraitType_value = map[string]int32{
+		"SIMPLE":      0,
+		"PULL_STREAM": 1,
+		"PUSH_STREAM": 2,
+	}
+)
+
+func (x CommandTraitType) Enum() *CommandTraitType {
+	p := new(CommandTraitType)
+	*p = x
+	return p
+}
+
+func (x CommandTraitType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CommandTraitType) Descriptor() protoreflect.EnumDescriptor {
+	return file_msapi_proto_enumTypes[5].Descriptor()
+}
+
+func (CommandTraitType) Type() protoreflect.EnumType {
+	return &file_msapi_proto_enumTypes[5]
+}
+
+func (x CommandTraitType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CommandTraitType.Descriptor instead.
+func (CommandTraitType) EnumDescriptor() ([]byte, []int) {
+	return file_msapi_proto_rawDescGZIP(), []int{5}
+}
+
 type SystemCommand int32
 
 const (
-	SystemCommand_USER_EVENT   SystemCommand = 0 // used by other subsystem
-	SystemCommand_REGISTER     SystemCommand = 1
-	SystemCommand_KEEPALIVE    SystemCommand = 2
-	SystemCommand_SESSION_INFO SystemCommand = 3
+	SystemCommand_USER_EVENT         SystemCommand = 0 // used by other subsystem
+	SystemCommand_REGISTER           SystemCommand = 1
+	SystemCommand_KEEPALIVE          SystemCommand = 2
+	SystemCommand_SESSION_INFO       SystemCommand = 3
+	SystemCommand_SESSION_REPORT     SystemCommand = 4  // final report sent to instance when session stopped
+	SystemCommand_WATCHDOG_ALERT     SystemCommand = 5  // session is unhealthy but watchdog action is notify only
+	SystemCommand_DRAIN              SystemCommand = 6  // server is draining, event is the deadline in unix milliseconds
+	SystemCommand_USER_EVENT_ACK     SystemCommand = 7  // instance acks USER_EVENT with the same ack_id
+	SystemCommand_SEQ_ACK            SystemCommand = 8  // instance acks all events with seq up to the event's seq, enables replay and retransmission
+	SystemCommand_OWNERSHIP_TRANSFER SystemCommand = 9  // session is adopted by the instance, event is id of the previous owner
+	SystemCommand_CAPACITY_REPORT    SystemCommand = 10 // broadcast periodically or reply of CAPACITY_QUERY, never replayed
+	SystemCommand_CAPACITY_QUERY     SystemCommand = 11 // instance asks for CAPACITY_REPORT
 )
 
 // Enum value maps for SystemCommand.
 var (
 	SystemCommand_name = map[int32]string{
-		0: "USER_EVENT",
-		1: "REGISTER",
-		2: "KEEPALIVE",
-		3: "SESSION_INFO",
+		0:  "USER_EVENT",
+		1:  "REGISTER",
+		2:  "KEEPALIVE",
+		3:  "SESSION_INFO",
+		4:  "SESSION_REPORT",
+		5:  "WATCHDOG_ALERT",
+		6:  "DRAIN",
+		7:  "USER_EVENT_ACK",
+		8:  "SEQ_ACK",
+		9:  "OWNERSHIP_TRANSFER",
+		10: "CAPACITY_REPORT",
+		11: "CAPACITY_QUERY",
 	}
 	SystemCommand_value = map[string]int32{
-		"USER_EVENT":   0,
-		"REGISTER":     1,
-		"KEEPALIVE":    2,
-		"SESSION_INFO": 3,
+		"USER_EVENT":         0,
+		"REGISTER":           1,
+		"KEEPALIVE":          2,
+		"SESSION_INFO":       3,
+		"SESSION_REPORT":     4,
+		"WATCHDOG_ALERT":     5,
+		"DRAIN":              6,
+		"USER_EVENT_ACK":     7,
+		"SEQ_ACK":            8,
+		"OWNERSHIP_TRANSFER": 9,
+		"CAPACITY_REPORT":    10,
+		"CAPACITY_QUERY":     11,
 	}
 )
 
@@ -166,11 +404,11 @@ func (x SystemCommand) String() string {
 }
 
 func (SystemCommand) Descriptor() protoreflect.EnumDescriptor {
-	return file_msapi_proto_enumTypes[2].Descriptor()
+	return file_msapi_proto_enumTypes[6].Descriptor()
 }
 
 func (SystemCommand) Type() protoreflect.EnumType {
-	return &file_msapi_proto_enumTypes[2]
+	return &file_msapi_proto_enumTypes[6]
 }
 
 func (x SystemCommand) Number() protoreflect.EnumNumber {
@@ -179,7 +417,7 @@ func (x SystemCommand) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use SystemCommand.Descriptor instead.
 func (SystemCommand) EnumDescriptor() ([]byte, []int) {
-	return file_msapi_proto_rawDescGZIP(), []int{2}
+	return file_msapi_proto_rawDescGZIP(), []int{6}
 }
 
 type VersionNumber struct {
@@ -330,22 +568,105 @@ func (x *CodecInfo) GetCodecParam() string {
 	return ""
 }
 
+// WatchdogPolicy overrides server's config for a session, timeouts are in seconds. zero uses server's config and
+// negative disables the check
+type WatchdogPolicy struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	InstanceTimeout int32          `protobuf:"varint,1,opt,name=instance_timeout,json=instanceTimeout,proto3" json:"instance_timeout,omitempty"` // no SESSION_INFO from instance, or created but not started
+	ReceiveTimeout  int32          `protobuf:"varint,2,opt,name=receive_timeout,json=receiveTimeout,proto3" json:"receive_timeout,omitempty"`    // no rtp packet received
+	SendTimeout     int32          `protobuf:"varint,3,opt,name=send_timeout,json=sendTimeout,proto3" json:"send_timeout,omitempty"`             // no rtp packet sent
+	ErrorThreshold  int32          `protobuf:"varint,4,opt,name=error_threshold,json=errorThreshold,proto3" json:"error_threshold,omitempty"`    // errors reported by rtp loops
+	Action          WatchdogAction `protobuf:"varint,5,opt,name=action,proto3,enum=rpc.WatchdogAction" json:"action,omitempty"`
+}
+
+func (x *WatchdogPolicy) Reset() {
+	*x = WatchdogPolicy{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_msapi_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchdogPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchdogPolicy) ProtoMessage() {}
+
+func (x *WatchdogPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_msapi_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchdogPolicy.ProtoReflect.Descriptor instead.
+func (*WatchdogPolicy) Descriptor() ([]byte, []int) {
+	return file_msapi_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *WatchdogPolicy) GetInstanceTimeout() int32 {
+	if x != nil {
+		return x.InstanceTimeout
+	}
+	return 0
+}
+
+func (x *WatchdogPolicy) GetReceiveTimeout() int32 {
+	if x != nil {
+		return x.ReceiveTimeout
+	}
+	return 0
+}
+
+func (x *WatchdogPolicy) GetSendTimeout() int32 {
+	if x != nil {
+		return x.SendTimeout
+	}
+	return 0
+}
+
+func (x *WatchdogPolicy) GetErrorThreshold() int32 {
+	if x != nil {
+		return x.ErrorThreshold
+	}
+	return 0
+}
+
+func (x *WatchdogPolicy) GetAction() WatchdogAction {
+	if x != nil {
+		return x.Action
+	}
+	return WatchdogAction_WATCHDOG_DEFAULT
+}
+
 type CreateParam struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	PeerIp     string       `protobuf:"bytes,1,opt,name=peer_ip,json=peerIp,proto3" json:"peer_ip,omitempty"`        // remote rtp ip
-	PeerPort   uint32       `protobuf:"varint,2,opt,name=peer_port,json=peerPort,proto3" json:"peer_port,omitempty"` // remote rtp port
-	Codecs     []*CodecInfo `protobuf:"bytes,3,rep,name=codecs,proto3" json:"codecs,omitempty"`
-	GraphDesc  string       `protobuf:"bytes,4,opt,name=graph_desc,json=graphDesc,proto3" json:"graph_desc,omitempty"`    // used to describe event graph
-	InstanceId string       `protobuf:"bytes,5,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"` // which instance creates this session
+	PeerIp        string          `protobuf:"bytes,1,opt,name=peer_ip,json=peerIp,proto3" json:"peer_ip,omitempty"`        // remote rtp ip
+	PeerPort      uint32          `protobuf:"varint,2,opt,name=peer_port,json=peerPort,proto3" json:"peer_port,omitempty"` // remote rtp port
+	Codecs        []*CodecInfo    `protobuf:"bytes,3,rep,name=codecs,proto3" json:"codecs,omitempty"`
+	GraphDesc     string          `protobuf:"bytes,4,opt,name=graph_desc,json=graphDesc,proto3" json:"graph_desc,omitempty"`             // used to describe event graph
+	InstanceId    string          `protobuf:"bytes,5,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`          // which instance creates this session
+	InterfaceName string          `protobuf:"bytes,6,opt,name=interface_name,json=interfaceName,proto3" json:"interface_name,omitempty"` // which rtp interface the session binds to, empty for the default one
+	Watchdog      *WatchdogPolicy `protobuf:"bytes,7,opt,name=watchdog,proto3" json:"watchdog,omitempty"`                                // use server's config if not set
 }
 
 func (x *CreateParam) Reset() {
 	*x = CreateParam{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_msapi_proto_msgTypes[3]
+		mi := &file_msapi_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -358,7 +679,7 @@ func (x *CreateParam) String() string {
 func (*CreateParam) ProtoMessage() {}
 
 func (x *CreateParam) ProtoReflect() protoreflect.Message {
-	mi := &file_msapi_proto_msgTypes[3]
+	mi := &file_msapi_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -371,7 +692,7 @@ func (x *CreateParam) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateParam.ProtoReflect.Descriptor instead.
 func (*CreateParam) Descriptor() ([]byte, []int) {
-	return file_msapi_proto_rawDescGZIP(), []int{3}
+	return file_msapi_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *CreateParam) GetPeerIp() string {
@@ -409,6 +730,20 @@ func (x *CreateParam) GetInstanceId() string {
 	return ""
 }
 
+func (x *CreateParam) GetInterfaceName() string {
+	if x != nil {
+		return x.InterfaceName
+	}
+	return ""
+}
+
+func (x *CreateParam) GetWatchdog() *WatchdogPolicy {
+	if x != nil {
+		return x.Watchdog
+	}
+	return nil
+}
+
 type UpdateParam struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -423,7 +758,7 @@ type UpdateParam struct {
 func (x *UpdateParam) Reset() {
 	*x = UpdateParam{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_msapi_proto_msgTypes[4]
+		mi := &file_msapi_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -436,7 +771,7 @@ func (x *UpdateParam) String() string {
 func (*UpdateParam) ProtoMessage() {}
 
 func (x *UpdateParam) ProtoReflect() protoreflect.Message {
-	mi := &file_msapi_proto_msgTypes[4]
+	mi := &file_msapi_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -449,7 +784,7 @@ func (x *UpdateParam) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateParam.ProtoReflect.Descriptor instead.
 func (*UpdateParam) Descriptor() ([]byte, []int) {
-	return file_msapi_proto_rawDescGZIP(), []int{4}
+	return file_msapi_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *UpdateParam) GetSessionId() string {
@@ -491,7 +826,7 @@ type StartParam struct {
 func (x *StartParam) Reset() {
 	*x = StartParam{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_msapi_proto_msgTypes[5]
+		mi := &file_msapi_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -504,7 +839,7 @@ func (x *StartParam) String() string {
 func (*StartParam) ProtoMessage() {}
 
 func (x *StartParam) ProtoReflect() protoreflect.Message {
-	mi := &file_msapi_proto_msgTypes[5]
+	mi := &file_msapi_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -517,7 +852,7 @@ func (x *StartParam) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StartParam.ProtoReflect.Descriptor instead.
 func (*StartParam) Descriptor() ([]byte, []int) {
-	return file_msapi_proto_rawDescGZIP(), []int{5}
+	return file_msapi_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *StartParam) GetSessionId() string {
@@ -538,7 +873,7 @@ type StopParam struct {
 func (x *StopParam) Reset() {
 	*x = StopParam{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_msapi_proto_msgTypes[6]
+		mi := &file_msapi_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -551,7 +886,7 @@ func (x *StopParam) String() string {
 func (*StopParam) ProtoMessage() {}
 
 func (x *StopParam) ProtoReflect() protoreflect.Message {
-	mi := &file_msapi_proto_msgTypes[6]
+	mi := &file_msapi_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -564,7 +899,7 @@ func (x *StopParam) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StopParam.ProtoReflect.Descriptor instead.
 func (*StopParam) Descriptor() ([]byte, []int) {
-	return file_msapi_proto_rawDescGZIP(), []int{6}
+	return file_msapi_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *StopParam) GetSessionId() string {
@@ -585,7 +920,7 @@ type Status struct {
 func (x *Status) Reset() {
 	*x = Status{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_msapi_proto_msgTypes[7]
+		mi := &file_msapi_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -598,7 +933,7 @@ func (x *Status) String() string {
 func (*Status) ProtoMessage() {}
 
 func (x *Status) ProtoReflect() protoreflect.Message {
-	mi := &file_msapi_proto_msgTypes[7]
+	mi := &file_msapi_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -611,7 +946,7 @@ func (x *Status) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Status.ProtoReflect.Descriptor instead.
 func (*Status) Descriptor() ([]byte, []int) {
-	return file_msapi_proto_rawDescGZIP(), []int{7}
+	return file_msapi_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *Status) GetStatus() string {
@@ -626,17 +961,18 @@ type Session struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SessionId    string `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
-	LocalIp      string `protobuf:"bytes,2,opt,name=local_ip,json=localIp,proto3" json:"local_ip,omitempty"`
-	LocalRtpPort uint32 `protobuf:"varint,3,opt,name=local_rtp_port,json=localRtpPort,proto3" json:"local_rtp_port,omitempty"`
-	PeerIp       string `protobuf:"bytes,4,opt,name=peer_ip,json=peerIp,proto3" json:"peer_ip,omitempty"`
-	PeerRtpPort  uint32 `protobuf:"varint,5,opt,name=peer_rtp_port,json=peerRtpPort,proto3" json:"peer_rtp_port,omitempty"`
+	SessionId     string `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	LocalIp       string `protobuf:"bytes,2,opt,name=local_ip,json=localIp,proto3" json:"local_ip,omitempty"`
+	LocalRtpPort  uint32 `protobuf:"varint,3,opt,name=local_rtp_port,json=localRtpPort,proto3" json:"local_rtp_port,omitempty"`
+	PeerIp        string `protobuf:"bytes,4,opt,name=peer_ip,json=peerIp,proto3" json:"peer_ip,omitempty"`
+	PeerRtpPort   uint32 `protobuf:"varint,5,opt,name=peer_rtp_port,json=peerRtpPort,proto3" json:"peer_rtp_port,omitempty"`
+	InterfaceName string `protobuf:"bytes,6,opt,name=interface_name,json=interfaceName,proto3" json:"interface_name,omitempty"`
 }
 
 func (x *Session) Reset() {
 	*x = Session{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_msapi_proto_msgTypes[8]
+		mi := &file_msapi_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -649,7 +985,7 @@ func (x *Session) String() string {
 func (*Session) ProtoMessage() {}
 
 func (x *Session) ProtoReflect() protoreflect.Message {
-	mi := &file_msapi_proto_msgTypes[8]
+	mi := &file_msapi_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -662,7 +998,7 @@ func (x *Session) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Session.ProtoReflect.Descriptor instead.
 func (*Session) Descriptor() ([]byte, []int) {
-	return file_msapi_proto_rawDescGZIP(), []int{8}
+	return file_msapi_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *Session) GetSessionId() string {
@@ -700,6 +1036,13 @@ func (x *Session) GetPeerRtpPort() uint32 {
 	return 0
 }
 
+func (x *Session) GetInterfaceName() string {
+	if x != nil {
+		return x.InterfaceName
+	}
+	return ""
+}
+
 type Action struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -713,7 +1056,7 @@ type Action struct {
 func (x *Action) Reset() {
 	*x = Action{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_msapi_proto_msgTypes[9]
+		mi := &file_msapi_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -726,7 +1069,7 @@ func (x *Action) String() string {
 func (*Action) ProtoMessage() {}
 
 func (x *Action) ProtoReflect() protoreflect.Message {
-	mi := &file_msapi_proto_msgTypes[9]
+	mi := &file_msapi_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -739,7 +1082,7 @@ func (x *Action) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Action.ProtoReflect.Descriptor instead.
 func (*Action) Descriptor() ([]byte, []int) {
-	return file_msapi_proto_rawDescGZIP(), []int{9}
+	return file_msapi_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *Action) GetSessionId() string {
@@ -775,7 +1118,7 @@ type ActionResult struct {
 func (x *ActionResult) Reset() {
 	*x = ActionResult{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_msapi_proto_msgTypes[10]
+		mi := &file_msapi_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -788,7 +1131,7 @@ func (x *ActionResult) String() string {
 func (*ActionResult) ProtoMessage() {}
 
 func (x *ActionResult) ProtoReflect() protoreflect.Message {
-	mi := &file_msapi_proto_msgTypes[10]
+	mi := &file_msapi_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -801,7 +1144,7 @@ func (x *ActionResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ActionResult.ProtoReflect.Descriptor instead.
 func (*ActionResult) Descriptor() ([]byte, []int) {
-	return file_msapi_proto_rawDescGZIP(), []int{10}
+	return file_msapi_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *ActionResult) GetSessionId() string {
@@ -830,7 +1173,7 @@ type ActionEvent struct {
 func (x *ActionEvent) Reset() {
 	*x = ActionEvent{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_msapi_proto_msgTypes[11]
+		mi := &file_msapi_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -843,7 +1186,7 @@ func (x *ActionEvent) String() string {
 func (*ActionEvent) ProtoMessage() {}
 
 func (x *ActionEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_msapi_proto_msgTypes[11]
+	mi := &file_msapi_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -856,7 +1199,7 @@ func (x *ActionEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ActionEvent.ProtoReflect.Descriptor instead.
 func (*ActionEvent) Descriptor() ([]byte, []int) {
-	return file_msapi_proto_rawDescGZIP(), []int{11}
+	return file_msapi_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *ActionEvent) GetSessionId() string {
@@ -888,7 +1231,7 @@ type PushData struct {
 func (x *PushData) Reset() {
 	*x = PushData{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_msapi_proto_msgTypes[12]
+		mi := &file_msapi_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -901,7 +1244,7 @@ func (x *PushData) String() string {
 func (*PushData) ProtoMessage() {}
 
 func (x *PushData) ProtoReflect() protoreflect.Message {
-	mi := &file_msapi_proto_msgTypes[12]
+	mi := &file_msapi_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -914,7 +1257,7 @@ func (x *PushData) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PushData.ProtoReflect.Descriptor instead.
 func (*PushData) Descriptor() ([]byte, []int) {
-	return file_msapi_proto_rawDescGZIP(), []int{12}
+	return file_msapi_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *PushData) GetSessionId() string {
@@ -945,34 +1288,34 @@ func (x *PushData) GetData() []byte {
 	return nil
 }
 
-type SystemEvent struct {
+type ListParam struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Cmd        SystemCommand `protobuf:"varint,1,opt,name=cmd,proto3,enum=rpc.SystemCommand" json:"cmd,omitempty"`
-	InstanceId string        `protobuf:"bytes,2,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
-	SessionId  string        `protobuf:"bytes,3,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
-	Event      string        `protobuf:"bytes,4,opt,name=event,proto3" json:"event,omitempty"`
+	InstanceId string   `protobuf:"bytes,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"` // only sessions created by this instance if not empty
+	Status     []string `protobuf:"bytes,2,rep,name=status,proto3" json:"status,omitempty"`                           // any of "created","updated","started","stopped" if not empty
+	MinAge     uint32   `protobuf:"varint,3,opt,name=min_age,json=minAge,proto3" json:"min_age,omitempty"`            // seconds since session created
+	MaxAge     uint32   `protobuf:"varint,4,opt,name=max_age,json=maxAge,proto3" json:"max_age,omitempty"`            // no limit if zero
 }
 
-func (x *SystemEvent) Reset() {
-	*x = SystemEvent{}
+func (x *ListParam) Reset() {
+	*x = ListParam{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_msapi_proto_msgTypes[13]
+		mi := &file_msapi_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *SystemEvent) String() string {
+func (x *ListParam) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SystemEvent) ProtoMessage() {}
+func (*ListParam) ProtoMessage() {}
 
-func (x *SystemEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_msapi_proto_msgTypes[13]
+func (x *ListParam) ProtoReflect() protoreflect.Message {
+	mi := &file_msapi_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -983,16 +1326,1909 @@ func (x *SystemEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SystemEvent.ProtoReflect.Descriptor instead.
-func (*SystemEvent) Descriptor() ([]byte, []int) {
-	return file_msapi_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use ListParam.ProtoReflect.Descriptor instead.
+func (*ListParam) Descriptor() ([]byte, []int) {
+	return file_msapi_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *SystemEvent) GetCmd() SystemCommand {
+func (x *ListParam) GetInstanceId() string {
 	if x != nil {
-		return x.Cmd
+		return x.InstanceId
 	}
-	return SystemCommand_USER_EVENT
+	return ""
+}
+
+func (x *ListParam) GetStatus() []string {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+func (x *ListParam) GetMinAge() uint32 {
+	if x != nil {
+		return x.MinAge
+	}
+	return 0
+}
+
+func (x *ListParam) GetMaxAge() uint32 {
+	if x != nil {
+		return x.MaxAge
+	}
+	return 0
+}
+
+type SessionInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SessionId     string `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	InstanceId    string `protobuf:"bytes,2,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	Status        string `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	CreateTime    int64  `protobuf:"varint,4,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"` // unix milliseconds
+	InterfaceName string `protobuf:"bytes,5,opt,name=interface_name,json=interfaceName,proto3" json:"interface_name,omitempty"`
+	LocalIp       string `protobuf:"bytes,6,opt,name=local_ip,json=localIp,proto3" json:"local_ip,omitempty"`
+	LocalRtpPort  uint32 `protobuf:"varint,7,opt,name=local_rtp_port,json=localRtpPort,proto3" json:"local_rtp_port,omitempty"`
+	PeerIp        string `protobuf:"bytes,8,opt,name=peer_ip,json=peerIp,proto3" json:"peer_ip,omitempty"`
+	PeerRtpPort   uint32 `protobuf:"varint,9,opt,name=peer_rtp_port,json=peerRtpPort,proto3" json:"peer_rtp_port,omitempty"`
+}
+
+func (x *SessionInfo) Reset() {
+	*x = SessionInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_msapi_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SessionInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionInfo) ProtoMessage() {}
+
+func (x *SessionInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_msapi_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionInfo.ProtoReflect.Descriptor instead.
+func (*SessionInfo) Descriptor() ([]byte, []int) {
+	return file_msapi_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SessionInfo) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SessionInfo) GetInstanceId() string {
+	if x != nil {
+		return x.InstanceId
+	}
+	return ""
+}
+
+func (x *SessionInfo) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *SessionInfo) GetCreateTime() int64 {
+	if x != nil {
+		return x.CreateTime
+	}
+	return 0
+}
+
+func (x *SessionInfo) GetInterfaceName() string {
+	if x != nil {
+		return x.InterfaceName
+	}
+	return ""
+}
+
+func (x *SessionInfo) GetLocalIp() string {
+	if x != nil {
+		return x.LocalIp
+	}
+	return ""
+}
+
+func (x *SessionInfo) GetLocalRtpPort() uint32 {
+	if x != nil {
+		return x.LocalRtpPort
+	}
+	return 0
+}
+
+func (x *SessionInfo) GetPeerIp() string {
+	if x != nil {
+		return x.PeerIp
+	}
+	return ""
+}
+
+func (x *SessionInfo) GetPeerRtpPort() uint32 {
+	if x != nil {
+		return x.PeerRtpPort
+	}
+	return 0
+}
+
+type SessionList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Sessions []*SessionInfo `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
+}
+
+func (x *SessionList) Reset() {
+	*x = SessionList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_msapi_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SessionList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionList) ProtoMessage() {}
+
+func (x *SessionList) ProtoReflect() protoreflect.Message {
+	mi := &file_msapi_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionList.ProtoReflect.Descriptor instead.
+func (*SessionList) Descriptor() ([]byte, []int) {
+	return file_msapi_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SessionList) GetSessions() []*SessionInfo {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+type DescribeParam struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SessionId string `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+}
+
+func (x *DescribeParam) Reset() {
+	*x = DescribeParam{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_msapi_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DescribeParam) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DescribeParam) ProtoMessage() {}
+
+func (x *DescribeParam) ProtoReflect() protoreflect.Message {
+	mi := &file_msapi_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DescribeParam.ProtoReflect.Descriptor instead.
+func (*DescribeParam) Descriptor() ([]byte, []int) {
+	return file_msapi_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *DescribeParam) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type WatchdogInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// unix milliseconds, zero if never reported
+	InstanceAliveTime int64 `protobuf:"varint,1,opt,name=instance_alive_time,json=instanceAliveTime,proto3" json:"instance_alive_time,omitempty"`
+	SendAliveTime     int64 `protobuf:"varint,2,opt,name=send_alive_time,json=sendAliveTime,proto3" json:"send_alive_time,omitempty"`
+	ReceiveAliveTime  int64 `protobuf:"varint,3,opt,name=receive_alive_time,json=receiveAliveTime,proto3" json:"receive_alive_time,omitempty"`
+	RtcpAliveTime     int64 `protobuf:"varint,4,opt,name=rtcp_alive_time,json=rtcpAliveTime,proto3" json:"rtcp_alive_time,omitempty"`
+	ErrorCount        int32 `protobuf:"varint,5,opt,name=error_count,json=errorCount,proto3" json:"error_count,omitempty"`
+}
+
+func (x *WatchdogInfo) Reset() {
+	*x = WatchdogInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_msapi_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchdogInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchdogInfo) ProtoMessage() {}
+
+func (x *WatchdogInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_msapi_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchdogInfo.ProtoReflect.Descriptor instead.
+func (*WatchdogInfo) Descriptor() ([]byte, []int) {
+	return file_msapi_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *WatchdogInfo) GetInstanceAliveTime() int64 {
+	if x != nil {
+		return x.InstanceAliveTime
+	}
+	return 0
+}
+
+func (x *WatchdogInfo) GetSendAliveTime() int64 {
+	if x != nil {
+		return x.SendAliveTime
+	}
+	return 0
+}
+
+func (x *WatchdogInfo) GetReceiveAliveTime() int64 {
+	if x != nil {
+		return x.ReceiveAliveTime
+	}
+	return 0
+}
+
+func (x *WatchdogInfo) GetRtcpAliveTime() int64 {
+	if x != nil {
+		return x.RtcpAliveTime
+	}
+	return 0
+}
+
+func (x *WatchdogInfo) GetErrorCount() int32 {
+	if x != nil {
+		return x.ErrorCount
+	}
+	return 0
+}
+
+type RtpStats struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ReceivedPackets uint64 `protobuf:"varint,1,opt,name=received_packets,json=receivedPackets,proto3" json:"received_packets,omitempty"`
+	ReceivedBytes   uint64 `protobuf:"varint,2,opt,name=received_bytes,json=receivedBytes,proto3" json:"received_bytes,omitempty"`
+	DroppedPackets  uint64 `protobuf:"varint,3,opt,name=dropped_packets,json=droppedPackets,proto3" json:"dropped_packets,omitempty"` // received but not consumed by graph in time
+	SentPackets     uint64 `protobuf:"varint,4,opt,name=sent_packets,json=sentPackets,proto3" json:"sent_packets,omitempty"`
+	SentBytes       uint64 `protobuf:"varint,5,opt,name=sent_bytes,json=sentBytes,proto3" json:"sent_bytes,omitempty"`
+}
+
+func (x *RtpStats) Reset() {
+	*x = RtpStats{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_msapi_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RtpStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RtpStats) ProtoMessage() {}
+
+func (x *RtpStats) ProtoReflect() protoreflect.Message {
+	mi := &file_msapi_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RtpStats.ProtoReflect.Descriptor instead.
+func (*RtpStats) Descriptor() ([]byte, []int) {
+	return file_msapi_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *RtpStats) GetReceivedPackets() uint64 {
+	if x != nil {
+		return x.ReceivedPackets
+	}
+	return 0
+}
+
+func (x *RtpStats) GetReceivedBytes() uint64 {
+	if x != nil {
+		return x.ReceivedBytes
+	}
+	return 0
+}
+
+func (x *RtpStats) GetDroppedPackets() uint64 {
+	if x != nil {
+		return x.DroppedPackets
+	}
+	return 0
+}
+
+func (x *RtpStats) GetSentPackets() uint64 {
+	if x != nil {
+		return x.SentPackets
+	}
+	return 0
+}
+
+func (x *RtpStats) GetSentBytes() uint64 {
+	if x != nil {
+		return x.SentBytes
+	}
+	return 0
+}
+
+type GraphNode struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name  string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type  string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Scope string `protobuf:"bytes,3,opt,name=scope,proto3" json:"scope,omitempty"`
+}
+
+func (x *GraphNode) Reset() {
+	*x = GraphNode{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_msapi_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GraphNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GraphNode) ProtoMessage() {}
+
+func (x *GraphNode) ProtoReflect() protoreflect.Message {
+	mi := &file_msapi_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GraphNode.ProtoReflect.Descriptor instead.
+func (*GraphNode) Descriptor() ([]byte, []int) {
+	return file_msapi_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GraphNode) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GraphNode) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *GraphNode) GetScope() string {
+	if x != nil {
+		return x.Scope
+	}
+	return ""
+}
+
+type GraphLink struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	From        string `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"` // node name of sender, with "@scope" suffix if not in the session
+	To          string `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	MessageType string `protobuf:"bytes,3,opt,name=message_type,json=messageType,proto3" json:"message_type,omitempty"` // negotiated message trait, empty if the link is not created by composer
+	Overflow    string `protobuf:"bytes,4,opt,name=overflow,proto3" json:"overflow,omitempty"`                          // what the link does when receiver doesn't catch up: timeout, block, drop_newest or drop_oldest
+	Dropped     uint64 `protobuf:"varint,5,opt,name=dropped,proto3" json:"dropped,omitempty"`                           // events dropped by the overflow policy
+}
+
+func (x *GraphLink) Reset() {
+	*x = GraphLink{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_msapi_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GraphLink) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GraphLink) ProtoMessage() {}
+
+func (x *GraphLink) ProtoReflect() protoreflect.Message {
+	mi := &file_msapi_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GraphLink.ProtoReflect.Descriptor instead.
+func (*GraphLink) Descriptor() ([]byte, []int) {
+	return file_msapi_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GraphLink) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *GraphLink) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *GraphLink) GetMessageType() string {
+	if x != nil {
+		return x.MessageType
+	}
+	return ""
+}
+
+func (x *GraphLink) GetOverflow() string {
+	if x != nil {
+		return x.Overflow
+	}
+	return ""
+}
+
+func (x *GraphLink) GetDropped() uint64 {
+	if x != nil {
+		return x.Dropped
+	}
+	return 0
+}
+
+type SessionDescription struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Info     *SessionInfo  `protobuf:"bytes,1,opt,name=info,proto3" json:"info,omitempty"`
+	Codecs   []*CodecInfo  `protobuf:"bytes,2,rep,name=codecs,proto3" json:"codecs,omitempty"`
+	Watchdog *WatchdogInfo `protobuf:"bytes,3,opt,name=watchdog,proto3" json:"watchdog,omitempty"`
+	Stats    *RtpStats     `protobuf:"bytes,4,opt,name=stats,proto3" json:"stats,omitempty"`
+	Nodes    []*GraphNode  `protobuf:"bytes,5,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	Links    []*GraphLink  `protobuf:"bytes,6,rep,name=links,proto3" json:"links,omitempty"`
+}
+
+func (x *SessionDescription) Reset() {
+	*x = SessionDescription{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_msapi_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SessionDescription) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionDescription) ProtoMessage() {}
+
+func (x *SessionDescription) ProtoReflect() protoreflect.Message {
+	mi := &file_msapi_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionDescription.ProtoReflect.Descriptor instead.
+func (*SessionDescription) Descriptor() ([]byte, []int) {
+	return file_msapi_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *SessionDescription) GetInfo() *SessionInfo {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+func (x *SessionDescription) GetCodecs() []*CodecInfo {
+	if x != nil {
+		return x.Codecs
+	}
+	return nil
+}
+
+func (x *SessionDescription) GetWatchdog() *WatchdogInfo {
+	if x != nil {
+		return x.Watchdog
+	}
+	return nil
+}
+
+func (x *SessionDescription) GetStats() *RtpStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+func (x *SessionDescription) GetNodes() []*GraphNode {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+func (x *SessionDescription) GetLinks() []*GraphLink {
+	if x != nil {
+		return x.Links
+	}
+	return nil
+}
+
+type WatchParam struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	InstanceId string `protobuf:"bytes,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"` // only events of sessions created by this instance are sent
+}
+
+func (x *WatchParam) Reset() {
+	*x = WatchParam{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_msapi_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchParam) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchParam) ProtoMessage() {}
+
+func (x *WatchParam) ProtoReflect() protoreflect.Message {
+	mi := &file_msapi_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchParam.ProtoReflect.Descriptor instead.
+func (*WatchParam) Descriptor() ([]byte, []int) {
+	return file_msapi_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *WatchParam) GetInstanceId() string {
+	if x != nil {
+		return x.InstanceId
+	}
+	return ""
+}
+
+type SessionReport struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SessionId     string       `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	InstanceId    string       `protobuf:"bytes,2,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	StopReason    StopReason   `protobuf:"varint,3,opt,name=stop_reason,json=stopReason,proto3,enum=rpc.StopReason" json:"stop_reason,omitempty"`
+	CreateTime    int64        `protobuf:"varint,4,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"` // unix milliseconds
+	StartTime     int64        `protobuf:"varint,5,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`    // zero if never started
+	StopTime      int64        `protobuf:"varint,6,opt,name=stop_time,json=stopTime,proto3" json:"stop_time,omitempty"`
+	Duration      int64        `protobuf:"varint,7,opt,name=duration,proto3" json:"duration,omitempty"` // milliseconds from start(or create if never started) to stop
+	Codecs        []*CodecInfo `protobuf:"bytes,8,rep,name=codecs,proto3" json:"codecs,omitempty"`
+	Stats         *RtpStats    `protobuf:"bytes,9,opt,name=stats,proto3" json:"stats,omitempty"`
+	ErrorCount    int32        `protobuf:"varint,10,opt,name=error_count,json=errorCount,proto3" json:"error_count,omitempty"`
+	InterfaceName string       `protobuf:"bytes,11,opt,name=interface_name,json=interfaceName,proto3" json:"interface_name,omitempty"`
+	LocalIp       string       `protobuf:"bytes,12,opt,name=local_ip,json=localIp,proto3" json:"local_ip,omitempty"`
+	LocalRtpPort  uint32       `protobuf:"varint,13,opt,name=local_rtp_port,json=localRtpPort,proto3" json:"local_rtp_port,omitempty"`
+	PeerIp        string       `protobuf:"bytes,14,opt,name=peer_ip,json=peerIp,proto3" json:"peer_ip,omitempty"`
+	PeerRtpPort   uint32       `protobuf:"varint,15,opt,name=peer_rtp_port,json=peerRtpPort,proto3" json:"peer_rtp_port,omitempty"`
+}
+
+func (x *SessionReport) Reset() {
+	*x = SessionReport{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_msapi_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SessionReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionReport) ProtoMessage() {}
+
+func (x *SessionReport) ProtoReflect() protoreflect.Message {
+	mi := &file_msapi_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionReport.ProtoReflect.Descriptor instead.
+func (*SessionReport) Descriptor() ([]byte, []int) {
+	return file_msapi_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *SessionReport) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SessionReport) GetInstanceId() string {
+	if x != nil {
+		return x.InstanceId
+	}
+	return ""
+}
+
+func (x *SessionReport) GetStopReason() StopReason {
+	if x != nil {
+		return x.StopReason
+	}
+	return StopReason_NONE
+}
+
+func (x *SessionReport) GetCreateTime() int64 {
+	if x != nil {
+		return x.CreateTime
+	}
+	return 0
+}
+
+func (x *SessionReport) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *SessionReport) GetStopTime() int64 {
+	if x != nil {
+		return x.StopTime
+	}
+	return 0
+}
+
+func (x *SessionReport) GetDuration() int64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *SessionReport) GetCodecs() []*CodecInfo {
+	if x != nil {
+		return x.Codecs
+	}
+	return nil
+}
+
+func (x *SessionReport) GetStats() *RtpStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+func (x *SessionReport) GetErrorCount() int32 {
+	if x != nil {
+		return x.ErrorCount
+	}
+	return 0
+}
+
+func (x *SessionReport) GetInterfaceName() string {
+	if x != nil {
+		return x.InterfaceName
+	}
+	return ""
+}
+
+func (x *SessionReport) GetLocalIp() string {
+	if x != nil {
+		return x.LocalIp
+	}
+	return ""
+}
+
+func (x *SessionReport) GetLocalRtpPort() uint32 {
+	if x != nil {
+		return x.LocalRtpPort
+	}
+	return 0
+}
+
+func (x *SessionReport) GetPeerIp() string {
+	if x != nil {
+		return x.PeerIp
+	}
+	return ""
+}
+
+func (x *SessionReport) GetPeerRtpPort() uint32 {
+	if x != nil {
+		return x.PeerRtpPort
+	}
+	return 0
+}
+
+type SessionEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type       SessionEventType `protobuf:"varint,1,opt,name=type,proto3,enum=rpc.SessionEventType" json:"type,omitempty"`
+	SessionId  string           `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	InstanceId string           `protobuf:"bytes,3,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	StopReason StopReason       `protobuf:"varint,4,opt,name=stop_reason,json=stopReason,proto3,enum=rpc.StopReason" json:"stop_reason,omitempty"` // only for SESSION_STOPPED
+	Time       int64            `protobuf:"varint,5,opt,name=time,proto3" json:"time,omitempty"`                                                   // unix milliseconds
+	Report     *SessionReport   `protobuf:"bytes,6,opt,name=report,proto3" json:"report,omitempty"`                                                // only for SESSION_STOPPED
+}
+
+func (x *SessionEvent) Reset() {
+	*x = SessionEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_msapi_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SessionEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionEvent) ProtoMessage() {}
+
+func (x *SessionEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_msapi_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionEvent.ProtoReflect.Descriptor instead.
+func (*SessionEvent) Descriptor() ([]byte, []int) {
+	return file_msapi_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *SessionEvent) GetType() SessionEventType {
+	if x != nil {
+		return x.Type
+	}
+	return SessionEventType_SESSION_CREATED
+}
+
+func (x *SessionEvent) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SessionEvent) GetInstanceId() string {
+	if x != nil {
+		return x.InstanceId
+	}
+	return ""
+}
+
+func (x *SessionEvent) GetStopReason() StopReason {
+	if x != nil {
+		return x.StopReason
+	}
+	return StopReason_NONE
+}
+
+func (x *SessionEvent) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *SessionEvent) GetReport() *SessionReport {
+	if x != nil {
+		return x.Report
+	}
+	return nil
+}
+
+type DrainParam struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Timeout uint32 `protobuf:"varint,1,opt,name=timeout,proto3" json:"timeout,omitempty"` // seconds to wait for sessions ending before force-stopping them, server's default if zero
+}
+
+func (x *DrainParam) Reset() {
+	*x = DrainParam{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_msapi_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DrainParam) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrainParam) ProtoMessage() {}
+
+func (x *DrainParam) ProtoReflect() protoreflect.Message {
+	mi := &file_msapi_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrainParam.ProtoReflect.Descriptor instead.
+func (*DrainParam) Descriptor() ([]byte, []int) {
+	return file_msapi_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *DrainParam) GetTimeout() uint32 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+// CapacityReport is load of media server, so that signalling services can route sessions to the least loaded one
+type CapacityReport struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Interfaces      []*InterfaceCapacity `protobuf:"bytes,1,rep,name=interfaces,proto3" json:"interfaces,omitempty"`
+	Sessions        uint32               `protobuf:"varint,2,opt,name=sessions,proto3" json:"sessions,omitempty"` // prepared or started sessions
+	StartedSessions uint32               `protobuf:"varint,3,opt,name=started_sessions,json=startedSessions,proto3" json:"started_sessions,omitempty"`
+	GraphNodes      uint32               `protobuf:"varint,4,opt,name=graph_nodes,json=graphNodes,proto3" json:"graph_nodes,omitempty"` // nodes and links in event graph of all sessions
+	GraphLinks      uint32               `protobuf:"varint,5,opt,name=graph_links,json=graphLinks,proto3" json:"graph_links,omitempty"`
+	Goroutines      uint32               `protobuf:"varint,6,opt,name=goroutines,proto3" json:"goroutines,omitempty"`
+	CpuUsage        float64              `protobuf:"fixed64,7,opt,name=cpu_usage,json=cpuUsage,proto3" json:"cpu_usage,omitempty"` // 1.0 means all cpus are busy, negative if not supported
+	Draining        bool                 `protobuf:"varint,8,opt,name=draining,proto3" json:"draining,omitempty"`
+	DrainDeadline   int64                `protobuf:"varint,9,opt,name=drain_deadline,json=drainDeadline,proto3" json:"drain_deadline,omitempty"` // unix milliseconds, only if draining
+	Time            int64                `protobuf:"varint,10,opt,name=time,proto3" json:"time,omitempty"`                                       // unix milliseconds when the report is made
+}
+
+func (x *CapacityReport) Reset() {
+	*x = CapacityReport{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_msapi_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CapacityReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CapacityReport) ProtoMessage() {}
+
+func (x *CapacityReport) ProtoReflect() protoreflect.Message {
+	mi := &file_msapi_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CapacityReport.ProtoReflect.Descriptor instead.
+func (*CapacityReport) Descriptor() ([]byte, []int) {
+	return file_msapi_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *CapacityReport) GetInterfaces() []*InterfaceCapacity {
+	if x != nil {
+		return x.Interfaces
+	}
+	return nil
+}
+
+func (x *CapacityReport) GetSessions() uint32 {
+	if x != nil {
+		return x.Sessions
+	}
+	return 0
+}
+
+func (x *CapacityReport) GetStartedSessions() uint32 {
+	if x != nil {
+		return x.StartedSessions
+	}
+	return 0
+}
+
+func (x *CapacityReport) GetGraphNodes() uint32 {
+	if x != nil {
+		return x.GraphNodes
+	}
+	return 0
+}
+
+func (x *CapacityReport) GetGraphLinks() uint32 {
+	if x != nil {
+		return x.GraphLinks
+	}
+	return 0
+}
+
+func (x *CapacityReport) GetGoroutines() uint32 {
+	if x != nil {
+		return x.Goroutines
+	}
+	return 0
+}
+
+func (x *CapacityReport) GetCpuUsage() float64 {
+	if x != nil {
+		return x.CpuUsage
+	}
+	return 0
+}
+
+func (x *CapacityReport) GetDraining() bool {
+	if x != nil {
+		return x.Draining
+	}
+	return false
+}
+
+func (x *CapacityReport) GetDrainDeadline() int64 {
+	if x != nil {
+		return x.DrainDeadline
+	}
+	return 0
+}
+
+func (x *CapacityReport) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+type InterfaceCapacity struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name      string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	FreePorts uint32 `protobuf:"varint,2,opt,name=free_ports,json=freePorts,proto3" json:"free_ports,omitempty"` // port pairs ready for new sessions, quarantined ones are not counted
+	Capacity  uint32 `protobuf:"varint,3,opt,name=capacity,proto3" json:"capacity,omitempty"`                    // all port pairs except reserved ones
+}
+
+func (x *InterfaceCapacity) Reset() {
+	*x = InterfaceCapacity{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_msapi_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InterfaceCapacity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InterfaceCapacity) ProtoMessage() {}
+
+func (x *InterfaceCapacity) ProtoReflect() protoreflect.Message {
+	mi := &file_msapi_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InterfaceCapacity.ProtoReflect.Descriptor instead.
+func (*InterfaceCapacity) Descriptor() ([]byte, []int) {
+	return file_msapi_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *InterfaceCapacity) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *InterfaceCapacity) GetFreePorts() uint32 {
+	if x != nil {
+		return x.FreePorts
+	}
+	return 0
+}
+
+func (x *InterfaceCapacity) GetCapacity() uint32 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+// AdoptParam transfers sessions of an instance that is not registered in system channel to the adopter
+type AdoptParam struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FromInstanceId string   `protobuf:"bytes,1,opt,name=from_instance_id,json=fromInstanceId,proto3" json:"from_instance_id,omitempty"`
+	SessionId      []string `protobuf:"bytes,2,rep,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`    // only these sessions of from_instance_id are adopted if not empty
+	InstanceId     string   `protobuf:"bytes,3,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"` // the adopter, must be registered in system channel
+}
+
+func (x *AdoptParam) Reset() {
+	*x = AdoptParam{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_msapi_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdoptParam) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdoptParam) ProtoMessage() {}
+
+func (x *AdoptParam) ProtoReflect() protoreflect.Message {
+	mi := &file_msapi_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdoptParam.ProtoReflect.Descriptor instead.
+func (*AdoptParam) Descriptor() ([]byte, []int) {
+	return file_msapi_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *AdoptParam) GetFromInstanceId() string {
+	if x != nil {
+		return x.FromInstanceId
+	}
+	return ""
+}
+
+func (x *AdoptParam) GetSessionId() []string {
+	if x != nil {
+		return x.SessionId
+	}
+	return nil
+}
+
+func (x *AdoptParam) GetInstanceId() string {
+	if x != nil {
+		return x.InstanceId
+	}
+	return ""
+}
+
+type AdoptResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SessionId []string `protobuf:"bytes,1,rep,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"` // sessions actually adopted
+}
+
+func (x *AdoptResult) Reset() {
+	*x = AdoptResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_msapi_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdoptResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdoptResult) ProtoMessage() {}
+
+func (x *AdoptResult) ProtoReflect() protoreflect.Message {
+	mi := &file_msapi_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdoptResult.ProtoReflect.Descriptor instead.
+func (*AdoptResult) Descriptor() ([]byte, []int) {
+	return file_msapi_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *AdoptResult) GetSessionId() []string {
+	if x != nil {
+		return x.SessionId
+	}
+	return nil
+}
+
+type DrainStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Draining          bool   `protobuf:"varint,1,opt,name=draining,proto3" json:"draining,omitempty"`
+	Deadline          int64  `protobuf:"varint,2,opt,name=deadline,proto3" json:"deadline,omitempty"` // unix milliseconds
+	RemainingSessions uint32 `protobuf:"varint,3,opt,name=remaining_sessions,json=remainingSessions,proto3" json:"remaining_sessions,omitempty"`
+}
+
+func (x *DrainStatus) Reset() {
+	*x = DrainStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_msapi_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DrainStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrainStatus) ProtoMessage() {}
+
+func (x *DrainStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_msapi_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrainStatus.ProtoReflect.Descriptor instead.
+func (*DrainStatus) Descriptor() ([]byte, []int) {
+	return file_msapi_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *DrainStatus) GetDraining() bool {
+	if x != nil {
+		return x.Draining
+	}
+	return false
+}
+
+func (x *DrainStatus) GetDeadline() int64 {
+	if x != nil {
+		return x.Deadline
+	}
+	return 0
+}
+
+func (x *DrainStatus) GetRemainingSessions() uint32 {
+	if x != nil {
+		return x.RemainingSessions
+	}
+	return 0
+}
+
+// OfferParam creates session like CreateParam does, but peer address and codecs are negotiated from remote offer
+type OfferParam struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Sdp           string          `protobuf:"bytes,1,opt,name=sdp,proto3" json:"sdp,omitempty"`
+	GraphDesc     string          `protobuf:"bytes,2,opt,name=graph_desc,json=graphDesc,proto3" json:"graph_desc,omitempty"`
+	InstanceId    string          `protobuf:"bytes,3,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	InterfaceName string          `protobuf:"bytes,4,opt,name=interface_name,json=interfaceName,proto3" json:"interface_name,omitempty"`
+	Watchdog      *WatchdogPolicy `protobuf:"bytes,5,opt,name=watchdog,proto3" json:"watchdog,omitempty"`
+}
+
+func (x *OfferParam) Reset() {
+	*x = OfferParam{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_msapi_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OfferParam) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OfferParam) ProtoMessage() {}
+
+func (x *OfferParam) ProtoReflect() protoreflect.Message {
+	mi := &file_msapi_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OfferParam.ProtoReflect.Descriptor instead.
+func (*OfferParam) Descriptor() ([]byte, []int) {
+	return file_msapi_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *OfferParam) GetSdp() string {
+	if x != nil {
+		return x.Sdp
+	}
+	return ""
+}
+
+func (x *OfferParam) GetGraphDesc() string {
+	if x != nil {
+		return x.GraphDesc
+	}
+	return ""
+}
+
+func (x *OfferParam) GetInstanceId() string {
+	if x != nil {
+		return x.InstanceId
+	}
+	return ""
+}
+
+func (x *OfferParam) GetInterfaceName() string {
+	if x != nil {
+		return x.InterfaceName
+	}
+	return ""
+}
+
+func (x *OfferParam) GetWatchdog() *WatchdogPolicy {
+	if x != nil {
+		return x.Watchdog
+	}
+	return nil
+}
+
+// ReofferParam updates session with a new offer from remote
+type ReofferParam struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SessionId string `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Sdp       string `protobuf:"bytes,2,opt,name=sdp,proto3" json:"sdp,omitempty"`
+}
+
+func (x *ReofferParam) Reset() {
+	*x = ReofferParam{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_msapi_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReofferParam) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReofferParam) ProtoMessage() {}
+
+func (x *ReofferParam) ProtoReflect() protoreflect.Message {
+	mi := &file_msapi_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReofferParam.ProtoReflect.Descriptor instead.
+func (*ReofferParam) Descriptor() ([]byte, []int) {
+	return file_msapi_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ReofferParam) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *ReofferParam) GetSdp() string {
+	if x != nil {
+		return x.Sdp
+	}
+	return ""
+}
+
+type NodeProperty struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"` // go type of the node's field
+}
+
+func (x *NodeProperty) Reset() {
+	*x = NodeProperty{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_msapi_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NodeProperty) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeProperty) ProtoMessage() {}
+
+func (x *NodeProperty) ProtoReflect() protoreflect.Message {
+	mi := &file_msapi_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeProperty.ProtoReflect.Descriptor instead.
+func (*NodeProperty) Descriptor() ([]byte, []int) {
+	return file_msapi_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *NodeProperty) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *NodeProperty) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+type NodeCapability struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NodeType   string          `protobuf:"bytes,1,opt,name=node_type,json=nodeType,proto3" json:"node_type,omitempty"`
+	Accept     []string        `protobuf:"bytes,2,rep,name=accept,proto3" json:"accept,omitempty"` // message trait names
+	Offer      []string        `protobuf:"bytes,3,rep,name=offer,proto3" json:"offer,omitempty"`
+	Properties []*NodeProperty `protobuf:"bytes,4,rep,name=properties,proto3" json:"properties,omitempty"`
+}
+
+func (x *NodeCapability) Reset() {
+	*x = NodeCapability{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_msapi_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NodeCapability) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeCapability) ProtoMessage() {}
+
+func (x *NodeCapability) ProtoReflect() protoreflect.Message {
+	mi := &file_msapi_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeCapability.ProtoReflect.Descriptor instead.
+func (*NodeCapability) Descriptor() ([]byte, []int) {
+	return file_msapi_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *NodeCapability) GetNodeType() string {
+	if x != nil {
+		return x.NodeType
+	}
+	return ""
+}
+
+func (x *NodeCapability) GetAccept() []string {
+	if x != nil {
+		return x.Accept
+	}
+	return nil
+}
+
+func (x *NodeCapability) GetOffer() []string {
+	if x != nil {
+		return x.Offer
+	}
+	return nil
+}
+
+func (x *NodeCapability) GetProperties() []*NodeProperty {
+	if x != nil {
+		return x.Properties
+	}
+	return nil
+}
+
+type MessageCapability struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name          string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	TypeId        int32    `protobuf:"varint,2,opt,name=type_id,json=typeId,proto3" json:"type_id,omitempty"`
+	ConvertibleTo []string `protobuf:"bytes,3,rep,name=convertible_to,json=convertibleTo,proto3" json:"convertible_to,omitempty"` // message trait names this message can be converted to
+}
+
+func (x *MessageCapability) Reset() {
+	*x = MessageCapability{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_msapi_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MessageCapability) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageCapability) ProtoMessage() {}
+
+func (x *MessageCapability) ProtoReflect() protoreflect.Message {
+	mi := &file_msapi_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageCapability.ProtoReflect.Descriptor instead.
+func (*MessageCapability) Descriptor() ([]byte, []int) {
+	return file_msapi_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *MessageCapability) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MessageCapability) GetTypeId() int32 {
+	if x != nil {
+		return x.TypeId
+	}
+	return 0
+}
+
+func (x *MessageCapability) GetConvertibleTo() []string {
+	if x != nil {
+		return x.ConvertibleTo
+	}
+	return nil
+}
+
+type CommandCapability struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name  string           `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Trait CommandTraitType `protobuf:"varint,2,opt,name=trait,proto3,enum=rpc.CommandTraitType" json:"trait,omitempty"`
+}
+
+func (x *CommandCapability) Reset() {
+	*x = CommandCapability{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_msapi_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CommandCapability) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommandCapability) ProtoMessage() {}
+
+func (x *CommandCapability) ProtoReflect() protoreflect.Message {
+	mi := &file_msapi_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommandCapability.ProtoReflect.Descriptor instead.
+func (*CommandCapability) Descriptor() ([]byte, []int) {
+	return file_msapi_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *CommandCapability) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CommandCapability) GetTrait() CommandTraitType {
+	if x != nil {
+		return x.Trait
+	}
+	return CommandTraitType_SIMPLE
+}
+
+type Capabilities struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version  Version              `protobuf:"varint,1,opt,name=version,proto3,enum=rpc.Version" json:"version,omitempty"`
+	Nodes    []*NodeCapability    `protobuf:"bytes,2,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	Messages []*MessageCapability `protobuf:"bytes,3,rep,name=messages,proto3" json:"messages,omitempty"`
+	Codecs   []CodecType          `protobuf:"varint,4,rep,packed,name=codecs,proto3,enum=rpc.CodecType" json:"codecs,omitempty"`
+	Commands []*CommandCapability `protobuf:"bytes,5,rep,name=commands,proto3" json:"commands,omitempty"`
+}
+
+func (x *Capabilities) Reset() {
+	*x = Capabilities{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_msapi_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Capabilities) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Capabilities) ProtoMessage() {}
+
+func (x *Capabilities) ProtoReflect() protoreflect.Message {
+	mi := &file_msapi_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Capabilities.ProtoReflect.Descriptor instead.
+func (*Capabilities) Descriptor() ([]byte, []int) {
+	return file_msapi_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *Capabilities) GetVersion() Version {
+	if x != nil {
+		return x.Version
+	}
+	return Version_DUMMY
+}
+
+func (x *Capabilities) GetNodes() []*NodeCapability {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+func (x *Capabilities) GetMessages() []*MessageCapability {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *Capabilities) GetCodecs() []CodecType {
+	if x != nil {
+		return x.Codecs
+	}
+	return nil
+}
+
+func (x *Capabilities) GetCommands() []*CommandCapability {
+	if x != nil {
+		return x.Commands
+	}
+	return nil
+}
+
+type Answer struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Session *Session     `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	Codecs  []*CodecInfo `protobuf:"bytes,2,rep,name=codecs,proto3" json:"codecs,omitempty"` // negotiated codecs, audio/video codec comes first
+	Sdp     string       `protobuf:"bytes,3,opt,name=sdp,proto3" json:"sdp,omitempty"`
+}
+
+func (x *Answer) Reset() {
+	*x = Answer{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_msapi_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Answer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Answer) ProtoMessage() {}
+
+func (x *Answer) ProtoReflect() protoreflect.Message {
+	mi := &file_msapi_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Answer.ProtoReflect.Descriptor instead.
+func (*Answer) Descriptor() ([]byte, []int) {
+	return file_msapi_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *Answer) GetSession() *Session {
+	if x != nil {
+		return x.Session
+	}
+	return nil
+}
+
+func (x *Answer) GetCodecs() []*CodecInfo {
+	if x != nil {
+		return x.Codecs
+	}
+	return nil
+}
+
+func (x *Answer) GetSdp() string {
+	if x != nil {
+		return x.Sdp
+	}
+	return ""
+}
+
+type SystemEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Cmd        SystemCommand  `protobuf:"varint,1,opt,name=cmd,proto3,enum=rpc.SystemCommand" json:"cmd,omitempty"`
+	InstanceId string         `protobuf:"bytes,2,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	SessionId  string         `protobuf:"bytes,3,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Event      string         `protobuf:"bytes,4,opt,name=event,proto3" json:"event,omitempty"`
+	Report     *SessionReport `protobuf:"bytes,5,opt,name=report,proto3" json:"report,omitempty"`             // only for SESSION_REPORT
+	Node       string         `protobuf:"bytes,6,opt,name=node,proto3" json:"node,omitempty"`                 // USER_EVENT: the node sending it, or receiving it if sent by instance
+	AckId      uint64         `protobuf:"varint,7,opt,name=ack_id,json=ackId,proto3" json:"ack_id,omitempty"` // USER_EVENT requires USER_EVENT_ACK if not zero, may be delivered more than once
+	// sequence number of events sent to instance except KEEPALIVE and CAPACITY_REPORT, duplicated ones should be
+	// dropped by instance.
+	// REGISTER carries the last seq received by instance so that sequence continues if media server restarted.
+	Seq uint64 `protobuf:"varint,8,opt,name=seq,proto3" json:"seq,omitempty"`
+	// REGISTER: failover group of the instance, sessions of a lost instance are adopted by another one in its group
+	Group    string          `protobuf:"bytes,9,opt,name=group,proto3" json:"group,omitempty"`
+	Capacity *CapacityReport `protobuf:"bytes,10,opt,name=capacity,proto3" json:"capacity,omitempty"` // only for CAPACITY_REPORT
+}
+
+func (x *SystemEvent) Reset() {
+	*x = SystemEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_msapi_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SystemEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SystemEvent) ProtoMessage() {}
+
+func (x *SystemEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_msapi_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SystemEvent.ProtoReflect.Descriptor instead.
+func (*SystemEvent) Descriptor() ([]byte, []int) {
+	return file_msapi_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *SystemEvent) GetCmd() SystemCommand {
+	if x != nil {
+		return x.Cmd
+	}
+	return SystemCommand_USER_EVENT
 }
 
 func (x *SystemEvent) GetInstanceId() string {
@@ -1016,6 +3252,144 @@ func (x *SystemEvent) GetEvent() string {
 	return ""
 }
 
+func (x *SystemEvent) GetReport() *SessionReport {
+	if x != nil {
+		return x.Report
+	}
+	return nil
+}
+
+func (x *SystemEvent) GetNode() string {
+	if x != nil {
+		return x.Node
+	}
+	return ""
+}
+
+func (x *SystemEvent) GetAckId() uint64 {
+	if x != nil {
+		return x.AckId
+	}
+	return 0
+}
+
+func (x *SystemEvent) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *SystemEvent) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *SystemEvent) GetCapacity() *CapacityReport {
+	if x != nil {
+		return x.Capacity
+	}
+	return nil
+}
+
+// PeerFrame carries event graph frames between media servers, so that nodes can link to nodes of remote scopes
+type PeerFrame struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type    uint32 `protobuf:"varint,1,opt,name=type,proto3" json:"type,omitempty"` // frame type of event graph remote, i.e. scope advertisement, link up/down, data
+	LinkId  uint64 `protobuf:"varint,2,opt,name=link_id,json=linkId,proto3" json:"link_id,omitempty"`
+	Scope   string `protobuf:"bytes,3,opt,name=scope,proto3" json:"scope,omitempty"`
+	Name    string `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	Cmd     int32  `protobuf:"varint,5,opt,name=cmd,proto3" json:"cmd,omitempty"`                        // message type of data
+	ReplyId uint64 `protobuf:"varint,6,opt,name=reply_id,json=replyId,proto3" json:"reply_id,omitempty"` // data expecting reply, or the reply
+	Data    []byte `protobuf:"bytes,7,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *PeerFrame) Reset() {
+	*x = PeerFrame{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_msapi_proto_msgTypes[41]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PeerFrame) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeerFrame) ProtoMessage() {}
+
+func (x *PeerFrame) ProtoReflect() protoreflect.Message {
+	mi := &file_msapi_proto_msgTypes[41]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeerFrame.ProtoReflect.Descriptor instead.
+func (*PeerFrame) Descriptor() ([]byte, []int) {
+	return file_msapi_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *PeerFrame) GetType() uint32 {
+	if x != nil {
+		return x.Type
+	}
+	return 0
+}
+
+func (x *PeerFrame) GetLinkId() uint64 {
+	if x != nil {
+		return x.LinkId
+	}
+	return 0
+}
+
+func (x *PeerFrame) GetScope() string {
+	if x != nil {
+		return x.Scope
+	}
+	return ""
+}
+
+func (x *PeerFrame) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PeerFrame) GetCmd() int32 {
+	if x != nil {
+		return x.Cmd
+	}
+	return 0
+}
+
+func (x *PeerFrame) GetReplyId() uint64 {
+	if x != nil {
+		return x.ReplyId
+	}
+	return 0
+}
+
+func (x *PeerFrame) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
 var File_msapi_proto protoreflect.FileDescriptor
 
 var file_msapi_proto_rawDesc = []byte{
@@ -1032,7 +3406,21 @@ var file_msapi_proto_rawDesc = []byte{
 	0x64, 0x65, 0x63, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0b, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64,
 	0x54, 0x79, 0x70, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x5f, 0x70, 0x61,
 	0x72, 0x61, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6f, 0x64, 0x65, 0x63,
-	0x50, 0x61, 0x72, 0x61, 0x6d, 0x22, 0xab, 0x01, 0x0a, 0x0b, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x50, 0x61, 0x72, 0x61, 0x6d, 0x22, 0xdd, 0x01, 0x0a, 0x0e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x64,
+	0x6f, 0x67, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x29, 0x0a, 0x10, 0x69, 0x6e, 0x73, 0x74,
+	0x61, 0x6e, 0x63, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0f, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x54, 0x69, 0x6d, 0x65,
+	0x6f, 0x75, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x5f, 0x74,
+	0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x72, 0x65,
+	0x63, 0x65, 0x69, 0x76, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x21, 0x0a, 0x0c,
+	0x73, 0x65, 0x6e, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0b, 0x73, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12,
+	0x27, 0x0a, 0x0f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f,
+	0x6c, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x54,
+	0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x12, 0x2b, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x57,
+	0x61, 0x74, 0x63, 0x68, 0x64, 0x6f, 0x67, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x83, 0x02, 0x0a, 0x0b, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
 	0x50, 0x61, 0x72, 0x61, 0x6d, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x65, 0x65, 0x72, 0x5f, 0x69, 0x70,
 	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x65, 0x65, 0x72, 0x49, 0x70, 0x12, 0x1b,
 	0x0a, 0x09, 0x70, 0x65, 0x65, 0x72, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
@@ -1043,112 +3431,462 @@ var file_msapi_proto_rawDesc = []byte{
 	0x63, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x67, 0x72, 0x61, 0x70, 0x68, 0x44, 0x65,
 	0x73, 0x63, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x69,
 	0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63,
-	0x65, 0x49, 0x64, 0x22, 0x89, 0x01, 0x0a, 0x0b, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x61,
-	0x72, 0x61, 0x6d, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69,
-	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
-	0x49, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x65, 0x65, 0x72, 0x5f, 0x69, 0x70, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x65, 0x65, 0x72, 0x49, 0x70, 0x12, 0x1b, 0x0a, 0x09, 0x70,
-	0x65, 0x65, 0x72, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08,
-	0x70, 0x65, 0x65, 0x72, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x70, 0x61, 0x79, 0x6c,
-	0x6f, 0x61, 0x64, 0x5f, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05,
-	0x52, 0x0d, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x22,
-	0x2b, 0x0a, 0x0a, 0x53, 0x74, 0x61, 0x72, 0x74, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x12, 0x1d, 0x0a,
-	0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x22, 0x2a, 0x0a, 0x09,
-	0x53, 0x74, 0x6f, 0x70, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73,
-	0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73,
-	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x22, 0x20, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74,
-	0x75, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0xa6, 0x01, 0x0a, 0x07, 0x53,
-	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x65, 0x49, 0x64, 0x12, 0x25, 0x0a, 0x0e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65,
+	0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x69, 0x6e, 0x74,
+	0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x2f, 0x0a, 0x08, 0x77, 0x61,
+	0x74, 0x63, 0x68, 0x64, 0x6f, 0x67, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x72,
+	0x70, 0x63, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x64, 0x6f, 0x67, 0x50, 0x6f, 0x6c, 0x69, 0x63,
+	0x79, 0x52, 0x08, 0x77, 0x61, 0x74, 0x63, 0x68, 0x64, 0x6f, 0x67, 0x22, 0x89, 0x01, 0x0a, 0x0b,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x12, 0x1d, 0x0a, 0x0a, 0x73,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x65,
+	0x65, 0x72, 0x5f, 0x69, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x65, 0x65,
+	0x72, 0x49, 0x70, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x65, 0x65, 0x72, 0x5f, 0x70, 0x6f, 0x72, 0x74,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x70, 0x65, 0x65, 0x72, 0x50, 0x6f, 0x72, 0x74,
+	0x12, 0x25, 0x0a, 0x0e, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x6e, 0x75, 0x6d, 0x62,
+	0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61,
+	0x64, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x22, 0x2b, 0x0a, 0x0a, 0x53, 0x74, 0x61, 0x72, 0x74,
+	0x50, 0x61, 0x72, 0x61, 0x6d, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x49, 0x64, 0x22, 0x2a, 0x0a, 0x09, 0x53, 0x74, 0x6f, 0x70, 0x50, 0x61, 0x72, 0x61,
+	0x6d, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64,
+	0x22, 0x20, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x22, 0xcd, 0x01, 0x0a, 0x07, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1d,
+	0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x19, 0x0a,
+	0x08, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x5f, 0x69, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x49, 0x70, 0x12, 0x24, 0x0a, 0x0e, 0x6c, 0x6f, 0x63, 0x61,
+	0x6c, 0x5f, 0x72, 0x74, 0x70, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x0c, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x52, 0x74, 0x70, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x17,
+	0x0a, 0x07, 0x70, 0x65, 0x65, 0x72, 0x5f, 0x69, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x70, 0x65, 0x65, 0x72, 0x49, 0x70, 0x12, 0x22, 0x0a, 0x0d, 0x70, 0x65, 0x65, 0x72, 0x5f,
+	0x72, 0x74, 0x70, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b,
+	0x70, 0x65, 0x65, 0x72, 0x52, 0x74, 0x70, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x69,
+	0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0d, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x4e, 0x61,
+	0x6d, 0x65, 0x22, 0x52, 0x0a, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a,
+	0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x63,
+	0x6d, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x63, 0x6d, 0x64, 0x12, 0x17, 0x0a,
+	0x07, 0x63, 0x6d, 0x64, 0x5f, 0x61, 0x72, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x63, 0x6d, 0x64, 0x41, 0x72, 0x67, 0x22, 0x43, 0x0a, 0x0c, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f,
 	0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73,
-	0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x5f, 0x69,
-	0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x49, 0x70,
+	0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x22, 0x42, 0x0a, 0x0b, 0x41,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x76, 0x65,
+	0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x22,
+	0x6c, 0x0a, 0x08, 0x50, 0x75, 0x73, 0x68, 0x44, 0x61, 0x74, 0x61, 0x12, 0x1d, 0x0a, 0x0a, 0x73,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x6d,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x63, 0x6d, 0x64, 0x12, 0x1b, 0x0a, 0x09,
+	0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x6e, 0x6f, 0x64, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74,
+	0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x76, 0x0a,
+	0x09, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x6e,
+	0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0a, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x6d, 0x69, 0x6e, 0x5f, 0x61, 0x67, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x6d, 0x69, 0x6e, 0x41, 0x67, 0x65, 0x12, 0x17, 0x0a, 0x07,
+	0x6d, 0x61, 0x78, 0x5f, 0x61, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06, 0x6d,
+	0x61, 0x78, 0x41, 0x67, 0x65, 0x22, 0xab, 0x02, 0x0a, 0x0b, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
+	0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x69, 0x6e, 0x73, 0x74, 0x61,
+	0x6e, 0x63, 0x65, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1f, 0x0a,
+	0x0b, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x25,
+	0x0a, 0x0e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63,
+	0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x5f, 0x69,
+	0x70, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x49, 0x70,
 	0x12, 0x24, 0x0a, 0x0e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x5f, 0x72, 0x74, 0x70, 0x5f, 0x70, 0x6f,
-	0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x52,
+	0x72, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x52,
 	0x74, 0x70, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x65, 0x65, 0x72, 0x5f, 0x69,
-	0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x65, 0x65, 0x72, 0x49, 0x70, 0x12,
+	0x70, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x65, 0x65, 0x72, 0x49, 0x70, 0x12,
 	0x22, 0x0a, 0x0d, 0x70, 0x65, 0x65, 0x72, 0x5f, 0x72, 0x74, 0x70, 0x5f, 0x70, 0x6f, 0x72, 0x74,
-	0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x70, 0x65, 0x65, 0x72, 0x52, 0x74, 0x70, 0x50,
-	0x6f, 0x72, 0x74, 0x22, 0x52, 0x0a, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a,
-	0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x10, 0x0a, 0x03,
-	0x63, 0x6d, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x63, 0x6d, 0x64, 0x12, 0x17,
-	0x0a, 0x07, 0x63, 0x6d, 0x64, 0x5f, 0x61, 0x72, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x06, 0x63, 0x6d, 0x64, 0x41, 0x72, 0x67, 0x22, 0x43, 0x0a, 0x0c, 0x41, 0x63, 0x74, 0x69, 0x6f,
-	0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69,
-	0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x73,
-	0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x22, 0x42, 0x0a, 0x0b,
-	0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x70, 0x65, 0x65, 0x72, 0x52, 0x74, 0x70, 0x50,
+	0x6f, 0x72, 0x74, 0x22, 0x3b, 0x0a, 0x0b, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x4c, 0x69,
+	0x73, 0x74, 0x12, 0x2c, 0x0a, 0x08, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x08, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73,
+	0x22, 0x2e, 0x0a, 0x0d, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x50, 0x61, 0x72, 0x61,
+	0x6d, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64,
+	0x22, 0xdd, 0x01, 0x0a, 0x0c, 0x57, 0x61, 0x74, 0x63, 0x68, 0x64, 0x6f, 0x67, 0x49, 0x6e, 0x66,
+	0x6f, 0x12, 0x2e, 0x0a, 0x13, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x61, 0x6c,
+	0x69, 0x76, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x11,
+	0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x41, 0x6c, 0x69, 0x76, 0x65, 0x54, 0x69, 0x6d,
+	0x65, 0x12, 0x26, 0x0a, 0x0f, 0x73, 0x65, 0x6e, 0x64, 0x5f, 0x61, 0x6c, 0x69, 0x76, 0x65, 0x5f,
+	0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x73, 0x65, 0x6e, 0x64,
+	0x41, 0x6c, 0x69, 0x76, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x2c, 0x0a, 0x12, 0x72, 0x65, 0x63,
+	0x65, 0x69, 0x76, 0x65, 0x5f, 0x61, 0x6c, 0x69, 0x76, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x10, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x41, 0x6c,
+	0x69, 0x76, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x26, 0x0a, 0x0f, 0x72, 0x74, 0x63, 0x70, 0x5f,
+	0x61, 0x6c, 0x69, 0x76, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0d, 0x72, 0x74, 0x63, 0x70, 0x41, 0x6c, 0x69, 0x76, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x12,
+	0x1f, 0x0a, 0x0b, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x75, 0x6e, 0x74,
+	0x22, 0xc7, 0x01, 0x0a, 0x08, 0x52, 0x74, 0x70, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x29, 0x0a,
+	0x10, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x5f, 0x70, 0x61, 0x63, 0x6b, 0x65, 0x74,
+	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65,
+	0x64, 0x50, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x72, 0x65, 0x63, 0x65,
+	0x69, 0x76, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x0d, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12,
+	0x27, 0x0a, 0x0f, 0x64, 0x72, 0x6f, 0x70, 0x70, 0x65, 0x64, 0x5f, 0x70, 0x61, 0x63, 0x6b, 0x65,
+	0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x64, 0x72, 0x6f, 0x70, 0x70, 0x65,
+	0x64, 0x50, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x65, 0x6e, 0x74,
+	0x5f, 0x70, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b,
+	0x73, 0x65, 0x6e, 0x74, 0x50, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x73,
+	0x65, 0x6e, 0x74, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x09, 0x73, 0x65, 0x6e, 0x74, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0x49, 0x0a, 0x09, 0x47, 0x72,
+	0x61, 0x70, 0x68, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74,
+	0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12,
+	0x14, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x73, 0x63, 0x6f, 0x70, 0x65, 0x22, 0x88, 0x01, 0x0a, 0x09, 0x47, 0x72, 0x61, 0x70, 0x68, 0x4c,
+	0x69, 0x6e, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x02, 0x74, 0x6f, 0x12, 0x21, 0x0a, 0x0c, 0x6d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x76,
+	0x65, 0x72, 0x66, 0x6c, 0x6f, 0x77, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6f, 0x76,
+	0x65, 0x72, 0x66, 0x6c, 0x6f, 0x77, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x72, 0x6f, 0x70, 0x70, 0x65,
+	0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x64, 0x72, 0x6f, 0x70, 0x70, 0x65, 0x64,
+	0x22, 0x82, 0x02, 0x0a, 0x12, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x44, 0x65, 0x73, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x24, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x12, 0x26, 0x0a,
+	0x06, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e,
+	0x72, 0x70, 0x63, 0x2e, 0x43, 0x6f, 0x64, 0x65, 0x63, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x06, 0x63,
+	0x6f, 0x64, 0x65, 0x63, 0x73, 0x12, 0x2d, 0x0a, 0x08, 0x77, 0x61, 0x74, 0x63, 0x68, 0x64, 0x6f,
+	0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x57, 0x61,
+	0x74, 0x63, 0x68, 0x64, 0x6f, 0x67, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x08, 0x77, 0x61, 0x74, 0x63,
+	0x68, 0x64, 0x6f, 0x67, 0x12, 0x23, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x73, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x74, 0x70, 0x53, 0x74, 0x61,
+	0x74, 0x73, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x73, 0x12, 0x24, 0x0a, 0x05, 0x6e, 0x6f, 0x64,
+	0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x47,
+	0x72, 0x61, 0x70, 0x68, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x05, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x12,
+	0x24, 0x0a, 0x05, 0x6c, 0x69, 0x6e, 0x6b, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e,
+	0x2e, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x72, 0x61, 0x70, 0x68, 0x4c, 0x69, 0x6e, 0x6b, 0x52, 0x05,
+	0x6c, 0x69, 0x6e, 0x6b, 0x73, 0x22, 0x2d, 0x0a, 0x0a, 0x57, 0x61, 0x74, 0x63, 0x68, 0x50, 0x61,
+	0x72, 0x61, 0x6d, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e,
+	0x63, 0x65, 0x49, 0x64, 0x22, 0x8d, 0x04, 0x0a, 0x0d, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63,
+	0x65, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x69, 0x6e, 0x73, 0x74,
+	0x61, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x12, 0x30, 0x0a, 0x0b, 0x73, 0x74, 0x6f, 0x70, 0x5f, 0x72,
+	0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0f, 0x2e, 0x72, 0x70,
+	0x63, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x52, 0x0a, 0x73, 0x74,
+	0x6f, 0x70, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x63,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x74, 0x6f, 0x70,
+	0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x73, 0x74, 0x6f,
+	0x70, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x26, 0x0a, 0x06, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x0e, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x6f, 0x64, 0x65, 0x63, 0x49, 0x6e, 0x66,
+	0x6f, 0x52, 0x06, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x73, 0x12, 0x23, 0x0a, 0x05, 0x73, 0x74, 0x61,
+	0x74, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x52,
+	0x74, 0x70, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x73, 0x12, 0x1f,
+	0x0a, 0x0b, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x0a, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12,
+	0x25, 0x0a, 0x0e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x5f, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61,
+	0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x5f,
+	0x69, 0x70, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x49,
+	0x70, 0x12, 0x24, 0x0a, 0x0e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x5f, 0x72, 0x74, 0x70, 0x5f, 0x70,
+	0x6f, 0x72, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x6c, 0x6f, 0x63, 0x61, 0x6c,
+	0x52, 0x74, 0x70, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x65, 0x65, 0x72, 0x5f,
+	0x69, 0x70, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x65, 0x65, 0x72, 0x49, 0x70,
+	0x12, 0x22, 0x0a, 0x0d, 0x70, 0x65, 0x65, 0x72, 0x5f, 0x72, 0x74, 0x70, 0x5f, 0x70, 0x6f, 0x72,
+	0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x70, 0x65, 0x65, 0x72, 0x52, 0x74, 0x70,
+	0x50, 0x6f, 0x72, 0x74, 0x22, 0xeb, 0x01, 0x0a, 0x0c, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x29, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65,
+	0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12,
+	0x1f, 0x0a, 0x0b, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x49, 0x64,
+	0x12, 0x30, 0x0a, 0x0b, 0x73, 0x74, 0x6f, 0x70, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0f, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x74, 0x6f, 0x70,
+	0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x52, 0x0a, 0x73, 0x74, 0x6f, 0x70, 0x52, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x2a, 0x0a, 0x06, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x06, 0x72, 0x65, 0x70, 0x6f,
+	0x72, 0x74, 0x22, 0x26, 0x0a, 0x0a, 0x44, 0x72, 0x61, 0x69, 0x6e, 0x50, 0x61, 0x72, 0x61, 0x6d,
+	0x12, 0x18, 0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x22, 0xe5, 0x02, 0x0a, 0x0e, 0x43,
+	0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x36, 0x0a,
+	0x0a, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x16, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63,
+	0x65, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x52, 0x0a, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x66, 0x61, 0x63, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x73, 0x12, 0x29, 0x0a, 0x10, 0x73, 0x74, 0x61, 0x72, 0x74, 0x65, 0x64, 0x5f, 0x73, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0f, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x65, 0x64, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1f, 0x0a, 0x0b,
+	0x67, 0x72, 0x61, 0x70, 0x68, 0x5f, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x0a, 0x67, 0x72, 0x61, 0x70, 0x68, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x1f, 0x0a,
+	0x0b, 0x67, 0x72, 0x61, 0x70, 0x68, 0x5f, 0x6c, 0x69, 0x6e, 0x6b, 0x73, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x0a, 0x67, 0x72, 0x61, 0x70, 0x68, 0x4c, 0x69, 0x6e, 0x6b, 0x73, 0x12, 0x1e,
+	0x0a, 0x0a, 0x67, 0x6f, 0x72, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x65, 0x73, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x0a, 0x67, 0x6f, 0x72, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x65, 0x73, 0x12, 0x1b,
+	0x0a, 0x09, 0x63, 0x70, 0x75, 0x5f, 0x75, 0x73, 0x61, 0x67, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x08, 0x63, 0x70, 0x75, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x64,
+	0x72, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x64,
+	0x72, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x25, 0x0a, 0x0e, 0x64, 0x72, 0x61, 0x69, 0x6e,
+	0x5f, 0x64, 0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0d, 0x64, 0x72, 0x61, 0x69, 0x6e, 0x44, 0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x74, 0x69,
+	0x6d, 0x65, 0x22, 0x62, 0x0a, 0x11, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x43,
+	0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x66,
+	0x72, 0x65, 0x65, 0x5f, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x09, 0x66, 0x72, 0x65, 0x65, 0x50, 0x6f, 0x72, 0x74, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61,
+	0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x63, 0x61,
+	0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x22, 0x76, 0x0a, 0x0a, 0x41, 0x64, 0x6f, 0x70, 0x74, 0x50,
+	0x61, 0x72, 0x61, 0x6d, 0x12, 0x28, 0x0a, 0x10, 0x66, 0x72, 0x6f, 0x6d, 0x5f, 0x69, 0x6e, 0x73,
+	0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e,
+	0x66, 0x72, 0x6f, 0x6d, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x12, 0x1d,
+	0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x1f, 0x0a,
+	0x0b, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0a, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x22, 0x2c,
+	0x0a, 0x0b, 0x41, 0x64, 0x6f, 0x70, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x1d, 0x0a,
+	0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x22, 0x74, 0x0a, 0x0b,
+	0x44, 0x72, 0x61, 0x69, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x64,
+	0x72, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x64,
+	0x72, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x65, 0x61, 0x64, 0x6c,
+	0x69, 0x6e, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x64, 0x65, 0x61, 0x64, 0x6c,
+	0x69, 0x6e, 0x65, 0x12, 0x2d, 0x0a, 0x12, 0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67,
+	0x5f, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x11, 0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x73, 0x22, 0xb6, 0x01, 0x0a, 0x0a, 0x4f, 0x66, 0x66, 0x65, 0x72, 0x50, 0x61, 0x72, 0x61,
+	0x6d, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x64, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x73, 0x64, 0x70, 0x12, 0x1d, 0x0a, 0x0a, 0x67, 0x72, 0x61, 0x70, 0x68, 0x5f, 0x64, 0x65, 0x73,
+	0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x67, 0x72, 0x61, 0x70, 0x68, 0x44, 0x65,
+	0x73, 0x63, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x69,
+	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63,
+	0x65, 0x49, 0x64, 0x12, 0x25, 0x0a, 0x0e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65,
+	0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x69, 0x6e, 0x74,
+	0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x2f, 0x0a, 0x08, 0x77, 0x61,
+	0x74, 0x63, 0x68, 0x64, 0x6f, 0x67, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x72,
+	0x70, 0x63, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x64, 0x6f, 0x67, 0x50, 0x6f, 0x6c, 0x69, 0x63,
+	0x79, 0x52, 0x08, 0x77, 0x61, 0x74, 0x63, 0x68, 0x64, 0x6f, 0x67, 0x22, 0x3f, 0x0a, 0x0c, 0x52,
+	0x65, 0x6f, 0x66, 0x66, 0x65, 0x72, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x12, 0x1d, 0x0a, 0x0a, 0x73,
 	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x64,
+	0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x73, 0x64, 0x70, 0x22, 0x36, 0x0a, 0x0c,
+	0x4e, 0x6f, 0x64, 0x65, 0x50, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x79, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x74, 0x79, 0x70, 0x65, 0x22, 0x8e, 0x01, 0x0a, 0x0e, 0x4e, 0x6f, 0x64, 0x65, 0x43, 0x61, 0x70,
+	0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x6e, 0x6f, 0x64, 0x65, 0x5f,
+	0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6e, 0x6f, 0x64, 0x65,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x12, 0x14, 0x0a, 0x05,
+	0x6f, 0x66, 0x66, 0x65, 0x72, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x6f, 0x66, 0x66,
+	0x65, 0x72, 0x12, 0x31, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x69, 0x65, 0x73,
+	0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4e, 0x6f, 0x64,
+	0x65, 0x50, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x79, 0x52, 0x0a, 0x70, 0x72, 0x6f, 0x70, 0x65,
+	0x72, 0x74, 0x69, 0x65, 0x73, 0x22, 0x67, 0x0a, 0x11, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x17,
+	0x0a, 0x07, 0x74, 0x79, 0x70, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x06, 0x74, 0x79, 0x70, 0x65, 0x49, 0x64, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x6e, 0x76, 0x65,
+	0x72, 0x74, 0x69, 0x62, 0x6c, 0x65, 0x5f, 0x74, 0x6f, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x0d, 0x63, 0x6f, 0x6e, 0x76, 0x65, 0x72, 0x74, 0x69, 0x62, 0x6c, 0x65, 0x54, 0x6f, 0x22, 0x54,
+	0x0a, 0x11, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c,
+	0x69, 0x74, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2b, 0x0a, 0x05, 0x74, 0x72, 0x61, 0x69, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x6f, 0x6d,
+	0x6d, 0x61, 0x6e, 0x64, 0x54, 0x72, 0x61, 0x69, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x05, 0x74,
+	0x72, 0x61, 0x69, 0x74, 0x22, 0xf1, 0x01, 0x0a, 0x0c, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c,
+	0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x26, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0c, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x56, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x29, 0x0a,
+	0x05, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x72,
+	0x70, 0x63, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74,
+	0x79, 0x52, 0x05, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x32, 0x0a, 0x08, 0x6d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x72, 0x70, 0x63,
+	0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69,
+	0x74, 0x79, 0x52, 0x08, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x12, 0x26, 0x0a, 0x06,
+	0x63, 0x6f, 0x64, 0x65, 0x63, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x72,
+	0x70, 0x63, 0x2e, 0x43, 0x6f, 0x64, 0x65, 0x63, 0x54, 0x79, 0x70, 0x65, 0x52, 0x06, 0x63, 0x6f,
+	0x64, 0x65, 0x63, 0x73, 0x12, 0x32, 0x0a, 0x08, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73,
+	0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x6f, 0x6d,
+	0x6d, 0x61, 0x6e, 0x64, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x08,
+	0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x73, 0x22, 0x6a, 0x0a, 0x06, 0x41, 0x6e, 0x73, 0x77,
+	0x65, 0x72, 0x12, 0x26, 0x0a, 0x07, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x52, 0x07, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x26, 0x0a, 0x06, 0x63, 0x6f,
+	0x64, 0x65, 0x63, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x72, 0x70, 0x63,
+	0x2e, 0x43, 0x6f, 0x64, 0x65, 0x63, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x06, 0x63, 0x6f, 0x64, 0x65,
+	0x63, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x64, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x73, 0x64, 0x70, 0x22, 0xb9, 0x02, 0x0a, 0x0b, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x12, 0x24, 0x0a, 0x03, 0x63, 0x6d, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x12, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x43, 0x6f,
+	0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52, 0x03, 0x63, 0x6d, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x6e,
+	0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0a, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x73,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
 	0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x76,
-	0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74,
-	0x22, 0x6c, 0x0a, 0x08, 0x50, 0x75, 0x73, 0x68, 0x44, 0x61, 0x74, 0x61, 0x12, 0x1d, 0x0a, 0x0a,
-	0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x63,
-	0x6d, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x63, 0x6d, 0x64, 0x12, 0x1b, 0x0a,
-	0x09, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x08, 0x6e, 0x6f, 0x64, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61,
-	0x74, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x89,
-	0x01, 0x0a, 0x0b, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x24,
-	0x0a, 0x03, 0x63, 0x6d, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x72, 0x70,
-	0x63, 0x2e, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x52,
-	0x03, 0x63, 0x6d, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
-	0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x69, 0x6e, 0x73, 0x74, 0x61,
-	0x6e, 0x63, 0x65, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
-	0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69,
-	0x6f, 0x6e, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x04, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x2a, 0x21, 0x0a, 0x07, 0x56, 0x65,
-	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x09, 0x0a, 0x05, 0x44, 0x55, 0x4d, 0x4d, 0x59, 0x10, 0x00,
-	0x12, 0x0b, 0x0a, 0x07, 0x44, 0x45, 0x46, 0x41, 0x55, 0x4c, 0x54, 0x10, 0x02, 0x2a, 0x7c, 0x0a,
-	0x09, 0x43, 0x6f, 0x64, 0x65, 0x63, 0x54, 0x79, 0x70, 0x65, 0x12, 0x07, 0x0a, 0x03, 0x52, 0x41,
-	0x57, 0x10, 0x00, 0x12, 0x16, 0x0a, 0x12, 0x54, 0x45, 0x4c, 0x45, 0x50, 0x48, 0x4f, 0x4e, 0x45,
-	0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x38, 0x4b, 0x10, 0x01, 0x12, 0x17, 0x0a, 0x13, 0x54,
-	0x45, 0x4c, 0x45, 0x50, 0x48, 0x4f, 0x4e, 0x45, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x31,
-	0x36, 0x4b, 0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08, 0x50, 0x43, 0x4d, 0x5f, 0x41, 0x4c, 0x41, 0x57,
-	0x10, 0x03, 0x12, 0x09, 0x0a, 0x05, 0x41, 0x4d, 0x52, 0x4e, 0x42, 0x10, 0x04, 0x12, 0x09, 0x0a,
-	0x05, 0x41, 0x4d, 0x52, 0x57, 0x42, 0x10, 0x05, 0x12, 0x08, 0x0a, 0x04, 0x48, 0x32, 0x36, 0x34,
-	0x10, 0x06, 0x12, 0x07, 0x0a, 0x03, 0x45, 0x56, 0x53, 0x10, 0x07, 0x2a, 0x4e, 0x0a, 0x0d, 0x53,
-	0x79, 0x73, 0x74, 0x65, 0x6d, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x0e, 0x0a, 0x0a,
-	0x55, 0x53, 0x45, 0x52, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08,
-	0x52, 0x45, 0x47, 0x49, 0x53, 0x54, 0x45, 0x52, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x4b, 0x45,
-	0x45, 0x50, 0x41, 0x4c, 0x49, 0x56, 0x45, 0x10, 0x02, 0x12, 0x10, 0x0a, 0x0c, 0x53, 0x45, 0x53,
-	0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x49, 0x4e, 0x46, 0x4f, 0x10, 0x03, 0x32, 0xe9, 0x03, 0x0a, 0x08,
-	0x4d, 0x65, 0x64, 0x69, 0x61, 0x41, 0x70, 0x69, 0x12, 0x2e, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x56,
-	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x0a, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x45, 0x6d, 0x70,
-	0x74, 0x79, 0x1a, 0x12, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
-	0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x22, 0x00, 0x12, 0x32, 0x0a, 0x0e, 0x50, 0x72, 0x65, 0x70,
-	0x61, 0x72, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x10, 0x2e, 0x72, 0x70, 0x63,
-	0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x1a, 0x0c, 0x2e, 0x72,
-	0x70, 0x63, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x00, 0x12, 0x30, 0x0a, 0x0d,
-	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x10, 0x2e,
-	0x72, 0x70, 0x63, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x1a,
-	0x0b, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x00, 0x12, 0x2e,
-	0x0a, 0x0c, 0x53, 0x74, 0x61, 0x72, 0x74, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x0f,
-	0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x1a,
-	0x0b, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x00, 0x12, 0x2c,
-	0x0a, 0x0b, 0x53, 0x74, 0x6f, 0x70, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x0e, 0x2e,
-	0x72, 0x70, 0x63, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x1a, 0x0b, 0x2e,
-	0x72, 0x70, 0x63, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x00, 0x12, 0x31, 0x0a, 0x0d,
-	0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0b, 0x2e,
-	0x72, 0x70, 0x63, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x1a, 0x11, 0x2e, 0x72, 0x70, 0x63,
-	0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x00, 0x12,
-	0x3c, 0x0a, 0x17, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e,
-	0x57, 0x69, 0x74, 0x68, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x12, 0x0b, 0x2e, 0x72, 0x70, 0x63,
-	0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x1a, 0x10, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x63,
-	0x74, 0x69, 0x6f, 0x6e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x22, 0x00, 0x30, 0x01, 0x12, 0x3d, 0x0a,
-	0x15, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x65, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x57, 0x69,
-	0x74, 0x68, 0x50, 0x75, 0x73, 0x68, 0x12, 0x0d, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x75, 0x73,
-	0x68, 0x44, 0x61, 0x74, 0x61, 0x1a, 0x11, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x63, 0x74, 0x69,
-	0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x00, 0x28, 0x01, 0x12, 0x39, 0x0a, 0x0d,
-	0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x10, 0x2e,
-	0x72, 0x70, 0x63, 0x2e, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x1a,
-	0x10, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x45, 0x76, 0x65, 0x6e,
-	0x74, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x42, 0x26, 0x5a, 0x24, 0x67, 0x69, 0x74, 0x68, 0x75,
-	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x70, 0x70, 0x63, 0x72, 0x61, 0x73, 0x68, 0x2f, 0x6d,
-	0x65, 0x64, 0x69, 0x61, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2f, 0x72, 0x70, 0x63, 0x62,
-	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x65, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74,
+	0x12, 0x2a, 0x0a, 0x06, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x12, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x70, 0x6f, 0x72, 0x74, 0x52, 0x06, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x6f, 0x64, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x6f, 0x64, 0x65,
+	0x12, 0x15, 0x0a, 0x06, 0x61, 0x63, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x05, 0x61, 0x63, 0x6b, 0x49, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x65, 0x71, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x73, 0x65, 0x71, 0x12, 0x14, 0x0a, 0x05, 0x67, 0x72, 0x6f,
+	0x75, 0x70, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x12,
+	0x2f, 0x0a, 0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x13, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79,
+	0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79,
+	0x22, 0xa3, 0x01, 0x0a, 0x09, 0x50, 0x65, 0x65, 0x72, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x6c, 0x69, 0x6e, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x06, 0x6c, 0x69, 0x6e, 0x6b, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x73,
+	0x63, 0x6f, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x63, 0x6f, 0x70,
+	0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x6d, 0x64, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x03, 0x63, 0x6d, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x72, 0x65, 0x70, 0x6c, 0x79,
+	0x5f, 0x69, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x72, 0x65, 0x70, 0x6c, 0x79,
+	0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x2a, 0x21, 0x0a, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x09, 0x0a, 0x05, 0x44, 0x55, 0x4d, 0x4d, 0x59, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07,
+	0x44, 0x45, 0x46, 0x41, 0x55, 0x4c, 0x54, 0x10, 0x10, 0x2a, 0x7c, 0x0a, 0x09, 0x43, 0x6f, 0x64,
+	0x65, 0x63, 0x54, 0x79, 0x70, 0x65, 0x12, 0x07, 0x0a, 0x03, 0x52, 0x41, 0x57, 0x10, 0x00, 0x12,
+	0x16, 0x0a, 0x12, 0x54, 0x45, 0x4c, 0x45, 0x50, 0x48, 0x4f, 0x4e, 0x45, 0x5f, 0x45, 0x56, 0x45,
+	0x4e, 0x54, 0x5f, 0x38, 0x4b, 0x10, 0x01, 0x12, 0x17, 0x0a, 0x13, 0x54, 0x45, 0x4c, 0x45, 0x50,
+	0x48, 0x4f, 0x4e, 0x45, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x31, 0x36, 0x4b, 0x10, 0x02,
+	0x12, 0x0c, 0x0a, 0x08, 0x50, 0x43, 0x4d, 0x5f, 0x41, 0x4c, 0x41, 0x57, 0x10, 0x03, 0x12, 0x09,
+	0x0a, 0x05, 0x41, 0x4d, 0x52, 0x4e, 0x42, 0x10, 0x04, 0x12, 0x09, 0x0a, 0x05, 0x41, 0x4d, 0x52,
+	0x57, 0x42, 0x10, 0x05, 0x12, 0x08, 0x0a, 0x04, 0x48, 0x32, 0x36, 0x34, 0x10, 0x06, 0x12, 0x07,
+	0x0a, 0x03, 0x45, 0x56, 0x53, 0x10, 0x07, 0x2a, 0x4e, 0x0a, 0x0e, 0x57, 0x61, 0x74, 0x63, 0x68,
+	0x64, 0x6f, 0x67, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x10, 0x57, 0x41, 0x54,
+	0x43, 0x48, 0x44, 0x4f, 0x47, 0x5f, 0x44, 0x45, 0x46, 0x41, 0x55, 0x4c, 0x54, 0x10, 0x00, 0x12,
+	0x11, 0x0a, 0x0d, 0x57, 0x41, 0x54, 0x43, 0x48, 0x44, 0x4f, 0x47, 0x5f, 0x53, 0x54, 0x4f, 0x50,
+	0x10, 0x01, 0x12, 0x13, 0x0a, 0x0f, 0x57, 0x41, 0x54, 0x43, 0x48, 0x44, 0x4f, 0x47, 0x5f, 0x4e,
+	0x4f, 0x54, 0x49, 0x46, 0x59, 0x10, 0x02, 0x2a, 0x87, 0x01, 0x0a, 0x0a, 0x53, 0x74, 0x6f, 0x70,
+	0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x08, 0x0a, 0x04, 0x4e, 0x4f, 0x4e, 0x45, 0x10, 0x00,
+	0x12, 0x11, 0x0a, 0x0d, 0x45, 0x58, 0x50, 0x4c, 0x49, 0x43, 0x49, 0x54, 0x5f, 0x53, 0x54, 0x4f,
+	0x50, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x50, 0x45, 0x45, 0x52, 0x5f, 0x42, 0x59, 0x45, 0x10,
+	0x02, 0x12, 0x14, 0x0a, 0x10, 0x57, 0x41, 0x54, 0x43, 0x48, 0x44, 0x4f, 0x47, 0x5f, 0x54, 0x49,
+	0x4d, 0x45, 0x4f, 0x55, 0x54, 0x10, 0x03, 0x12, 0x13, 0x0a, 0x0f, 0x45, 0x52, 0x52, 0x4f, 0x52,
+	0x5f, 0x54, 0x48, 0x52, 0x45, 0x53, 0x48, 0x4f, 0x4c, 0x44, 0x10, 0x04, 0x12, 0x11, 0x0a, 0x0d,
+	0x53, 0x54, 0x41, 0x52, 0x54, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x55, 0x52, 0x45, 0x10, 0x05, 0x12,
+	0x10, 0x0a, 0x0c, 0x53, 0x45, 0x52, 0x56, 0x45, 0x52, 0x5f, 0x44, 0x52, 0x41, 0x49, 0x4e, 0x10,
+	0x06, 0x2a, 0x7b, 0x0a, 0x10, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x13, 0x0a, 0x0f, 0x53, 0x45, 0x53, 0x53, 0x49, 0x4f, 0x4e,
+	0x5f, 0x43, 0x52, 0x45, 0x41, 0x54, 0x45, 0x44, 0x10, 0x00, 0x12, 0x13, 0x0a, 0x0f, 0x53, 0x45,
+	0x53, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x55, 0x50, 0x44, 0x41, 0x54, 0x45, 0x44, 0x10, 0x01, 0x12,
+	0x13, 0x0a, 0x0f, 0x53, 0x45, 0x53, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x52, 0x54,
+	0x45, 0x44, 0x10, 0x02, 0x12, 0x13, 0x0a, 0x0f, 0x53, 0x45, 0x53, 0x53, 0x49, 0x4f, 0x4e, 0x5f,
+	0x53, 0x54, 0x4f, 0x50, 0x50, 0x45, 0x44, 0x10, 0x03, 0x12, 0x13, 0x0a, 0x0f, 0x53, 0x45, 0x53,
+	0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x41, 0x44, 0x4f, 0x50, 0x54, 0x45, 0x44, 0x10, 0x04, 0x2a, 0x40,
+	0x0a, 0x10, 0x43, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x54, 0x72, 0x61, 0x69, 0x74, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x49, 0x4d, 0x50, 0x4c, 0x45, 0x10, 0x00, 0x12, 0x0f,
+	0x0a, 0x0b, 0x50, 0x55, 0x4c, 0x4c, 0x5f, 0x53, 0x54, 0x52, 0x45, 0x41, 0x4d, 0x10, 0x01, 0x12,
+	0x0f, 0x0a, 0x0b, 0x50, 0x55, 0x53, 0x48, 0x5f, 0x53, 0x54, 0x52, 0x45, 0x41, 0x4d, 0x10, 0x02,
+	0x2a, 0xe3, 0x01, 0x0a, 0x0d, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x43, 0x6f, 0x6d, 0x6d, 0x61,
+	0x6e, 0x64, 0x12, 0x0e, 0x0a, 0x0a, 0x55, 0x53, 0x45, 0x52, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54,
+	0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08, 0x52, 0x45, 0x47, 0x49, 0x53, 0x54, 0x45, 0x52, 0x10, 0x01,
+	0x12, 0x0d, 0x0a, 0x09, 0x4b, 0x45, 0x45, 0x50, 0x41, 0x4c, 0x49, 0x56, 0x45, 0x10, 0x02, 0x12,
+	0x10, 0x0a, 0x0c, 0x53, 0x45, 0x53, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x49, 0x4e, 0x46, 0x4f, 0x10,
+	0x03, 0x12, 0x12, 0x0a, 0x0e, 0x53, 0x45, 0x53, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x52, 0x45, 0x50,
+	0x4f, 0x52, 0x54, 0x10, 0x04, 0x12, 0x12, 0x0a, 0x0e, 0x57, 0x41, 0x54, 0x43, 0x48, 0x44, 0x4f,
+	0x47, 0x5f, 0x41, 0x4c, 0x45, 0x52, 0x54, 0x10, 0x05, 0x12, 0x09, 0x0a, 0x05, 0x44, 0x52, 0x41,
+	0x49, 0x4e, 0x10, 0x06, 0x12, 0x12, 0x0a, 0x0e, 0x55, 0x53, 0x45, 0x52, 0x5f, 0x45, 0x56, 0x45,
+	0x4e, 0x54, 0x5f, 0x41, 0x43, 0x4b, 0x10, 0x07, 0x12, 0x0b, 0x0a, 0x07, 0x53, 0x45, 0x51, 0x5f,
+	0x41, 0x43, 0x4b, 0x10, 0x08, 0x12, 0x16, 0x0a, 0x12, 0x4f, 0x57, 0x4e, 0x45, 0x52, 0x53, 0x48,
+	0x49, 0x50, 0x5f, 0x54, 0x52, 0x41, 0x4e, 0x53, 0x46, 0x45, 0x52, 0x10, 0x09, 0x12, 0x13, 0x0a,
+	0x0f, 0x43, 0x41, 0x50, 0x41, 0x43, 0x49, 0x54, 0x59, 0x5f, 0x52, 0x45, 0x50, 0x4f, 0x52, 0x54,
+	0x10, 0x0a, 0x12, 0x12, 0x0a, 0x0e, 0x43, 0x41, 0x50, 0x41, 0x43, 0x49, 0x54, 0x59, 0x5f, 0x51,
+	0x55, 0x45, 0x52, 0x59, 0x10, 0x0b, 0x32, 0x8b, 0x08, 0x0a, 0x08, 0x4d, 0x65, 0x64, 0x69, 0x61,
+	0x41, 0x70, 0x69, 0x12, 0x2e, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x0a, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x12, 0x2e,
+	0x72, 0x70, 0x63, 0x2e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x4e, 0x75, 0x6d, 0x62, 0x65,
+	0x72, 0x22, 0x00, 0x12, 0x32, 0x0a, 0x0e, 0x50, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x53, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x10, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x1a, 0x0c, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x00, 0x12, 0x30, 0x0a, 0x0d, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x10, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x1a, 0x0b, 0x2e, 0x72, 0x70, 0x63,
+	0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x00, 0x12, 0x2e, 0x0a, 0x0c, 0x53, 0x74, 0x61,
+	0x72, 0x74, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x0f, 0x2e, 0x72, 0x70, 0x63, 0x2e,
+	0x53, 0x74, 0x61, 0x72, 0x74, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x1a, 0x0b, 0x2e, 0x72, 0x70, 0x63,
+	0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x00, 0x12, 0x2c, 0x0a, 0x0b, 0x53, 0x74, 0x6f,
+	0x70, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x0e, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53,
+	0x74, 0x6f, 0x70, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x1a, 0x0b, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x00, 0x12, 0x31, 0x0a, 0x0d, 0x45, 0x78, 0x65, 0x63, 0x75,
+	0x74, 0x65, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0b, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x41,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x1a, 0x11, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x00, 0x12, 0x3c, 0x0a, 0x17, 0x45, 0x78,
+	0x65, 0x63, 0x75, 0x74, 0x65, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x57, 0x69, 0x74, 0x68, 0x4e,
+	0x6f, 0x74, 0x69, 0x66, 0x79, 0x12, 0x0b, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x1a, 0x10, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x22, 0x00, 0x30, 0x01, 0x12, 0x3d, 0x0a, 0x15, 0x45, 0x78, 0x65, 0x63,
+	0x75, 0x74, 0x65, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x57, 0x69, 0x74, 0x68, 0x50, 0x75, 0x73,
+	0x68, 0x12, 0x0d, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x75, 0x73, 0x68, 0x44, 0x61, 0x74, 0x61,
+	0x1a, 0x11, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x22, 0x00, 0x28, 0x01, 0x12, 0x39, 0x0a, 0x0d, 0x53, 0x79, 0x73, 0x74, 0x65,
+	0x6d, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x10, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53,
+	0x79, 0x73, 0x74, 0x65, 0x6d, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x1a, 0x10, 0x2e, 0x72, 0x70, 0x63,
+	0x2e, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x22, 0x00, 0x28, 0x01,
+	0x30, 0x01, 0x12, 0x32, 0x0a, 0x0c, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x73, 0x12, 0x0e, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x61, 0x72,
+	0x61, 0x6d, 0x1a, 0x10, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x4c, 0x69, 0x73, 0x74, 0x22, 0x00, 0x12, 0x40, 0x0a, 0x0f, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69,
+	0x62, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x2e, 0x72, 0x70, 0x63, 0x2e,
+	0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x1a, 0x17, 0x2e,
+	0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x44, 0x65, 0x73, 0x63, 0x72,
+	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x00, 0x12, 0x37, 0x0a, 0x0d, 0x57, 0x61, 0x74, 0x63,
+	0x68, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x0f, 0x2e, 0x72, 0x70, 0x63, 0x2e,
+	0x57, 0x61, 0x74, 0x63, 0x68, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x1a, 0x11, 0x2e, 0x72, 0x70, 0x63,
+	0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x22, 0x00, 0x30,
+	0x01, 0x12, 0x2c, 0x0a, 0x05, 0x44, 0x72, 0x61, 0x69, 0x6e, 0x12, 0x0f, 0x2e, 0x72, 0x70, 0x63,
+	0x2e, 0x44, 0x72, 0x61, 0x69, 0x6e, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x1a, 0x10, 0x2e, 0x72, 0x70,
+	0x63, 0x2e, 0x44, 0x72, 0x61, 0x69, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x00, 0x12,
+	0x39, 0x0a, 0x17, 0x50, 0x72, 0x65, 0x70, 0x61, 0x72, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x57, 0x69, 0x74, 0x68, 0x4f, 0x66, 0x66, 0x65, 0x72, 0x12, 0x0f, 0x2e, 0x72, 0x70, 0x63,
+	0x2e, 0x4f, 0x66, 0x66, 0x65, 0x72, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x1a, 0x0b, 0x2e, 0x72, 0x70,
+	0x63, 0x2e, 0x41, 0x6e, 0x73, 0x77, 0x65, 0x72, 0x22, 0x00, 0x12, 0x3a, 0x0a, 0x16, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x57, 0x69, 0x74, 0x68, 0x4f,
+	0x66, 0x66, 0x65, 0x72, 0x12, 0x11, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x6f, 0x66, 0x66,
+	0x65, 0x72, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x1a, 0x0b, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x6e,
+	0x73, 0x77, 0x65, 0x72, 0x22, 0x00, 0x12, 0x32, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x43, 0x61, 0x70,
+	0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x0a, 0x2e, 0x72, 0x70, 0x63, 0x2e,
+	0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x11, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x61, 0x70, 0x61,
+	0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x22, 0x00, 0x12, 0x34, 0x0a, 0x0d, 0x41, 0x64,
+	0x6f, 0x70, 0x74, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x0f, 0x2e, 0x72, 0x70,
+	0x63, 0x2e, 0x41, 0x64, 0x6f, 0x70, 0x74, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x1a, 0x10, 0x2e, 0x72,
+	0x70, 0x63, 0x2e, 0x41, 0x64, 0x6f, 0x70, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x00,
+	0x12, 0x30, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x12,
+	0x0a, 0x2e, 0x72, 0x70, 0x63, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x1a, 0x13, 0x2e, 0x72, 0x70,
+	0x63, 0x2e, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74,
+	0x22, 0x00, 0x12, 0x30, 0x0a, 0x08, 0x50, 0x65, 0x65, 0x72, 0x4c, 0x69, 0x6e, 0x6b, 0x12, 0x0e,
+	0x2e, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x65, 0x65, 0x72, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x1a, 0x0e,
+	0x2e, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x65, 0x65, 0x72, 0x46, 0x72, 0x61, 0x6d, 0x65, 0x22, 0x00,
+	0x28, 0x01, 0x30, 0x01, 0x42, 0x26, 0x5a, 0x24, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x61, 0x70, 0x70, 0x63, 0x72, 0x61, 0x73, 0x68, 0x2f, 0x6d, 0x65, 0x64, 0x69,
+	0x61, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2f, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1163,55 +3901,135 @@ func file_msapi_proto_rawDescGZIP() []byte {
 	return file_msapi_proto_rawDescData
 }
 
-var file_msapi_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-var file_msapi_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_msapi_proto_enumTypes = make([]protoimpl.EnumInfo, 7)
+var file_msapi_proto_msgTypes = make([]protoimpl.MessageInfo, 42)
 var file_msapi_proto_goTypes = []interface{}{
-	(Version)(0),          // 0: rpc.Version
-	(CodecType)(0),        // 1: rpc.CodecType
-	(SystemCommand)(0),    // 2: rpc.SystemCommand
-	(*VersionNumber)(nil), // 3: rpc.VersionNumber
-	(*Empty)(nil),         // 4: rpc.Empty
-	(*CodecInfo)(nil),     // 5: rpc.CodecInfo
-	(*CreateParam)(nil),   // 6: rpc.CreateParam
-	(*UpdateParam)(nil),   // 7: rpc.UpdateParam
-	(*StartParam)(nil),    // 8: rpc.StartParam
-	(*StopParam)(nil),     // 9: rpc.StopParam
-	(*Status)(nil),        // 10: rpc.Status
-	(*Session)(nil),       // 11: rpc.Session
-	(*Action)(nil),        // 12: rpc.Action
-	(*ActionResult)(nil),  // 13: rpc.ActionResult
-	(*ActionEvent)(nil),   // 14: rpc.ActionEvent
-	(*PushData)(nil),      // 15: rpc.PushData
-	(*SystemEvent)(nil),   // 16: rpc.SystemEvent
+	(Version)(0),               // 0: rpc.Version
+	(CodecType)(0),             // 1: rpc.CodecType
+	(WatchdogAction)(0),        // 2: rpc.WatchdogAction
+	(StopReason)(0),            // 3: rpc.StopReason
+	(SessionEventType)(0),      // 4: rpc.SessionEventType
+	(CommandTraitType)(0),      // 5: rpc.CommandTraitType
+	(SystemCommand)(0),         // 6: rpc.SystemCommand
+	(*VersionNumber)(nil),      // 7: rpc.VersionNumber
+	(*Empty)(nil),              // 8: rpc.Empty
+	(*CodecInfo)(nil),          // 9: rpc.CodecInfo
+	(*WatchdogPolicy)(nil),     // 10: rpc.WatchdogPolicy
+	(*CreateParam)(nil),        // 11: rpc.CreateParam
+	(*UpdateParam)(nil),        // 12: rpc.UpdateParam
+	(*StartParam)(nil),         // 13: rpc.StartParam
+	(*StopParam)(nil),          // 14: rpc.StopParam
+	(*Status)(nil),             // 15: rpc.Status
+	(*Session)(nil),            // 16: rpc.Session
+	(*Action)(nil),             // 17: rpc.Action
+	(*ActionResult)(nil),       // 18: rpc.ActionResult
+	(*ActionEvent)(nil),        // 19: rpc.ActionEvent
+	(*PushData)(nil),           // 20: rpc.PushData
+	(*ListParam)(nil),          // 21: rpc.ListParam
+	(*SessionInfo)(nil),        // 22: rpc.SessionInfo
+	(*SessionList)(nil),        // 23: rpc.SessionList
+	(*DescribeParam)(nil),      // 24: rpc.DescribeParam
+	(*WatchdogInfo)(nil),       // 25: rpc.WatchdogInfo
+	(*RtpStats)(nil),           // 26: rpc.RtpStats
+	(*GraphNode)(nil),          // 27: rpc.GraphNode
+	(*GraphLink)(nil),          // 28: rpc.GraphLink
+	(*SessionDescription)(nil), // 29: rpc.SessionDescription
+	(*WatchParam)(nil),         // 30: rpc.WatchParam
+	(*SessionReport)(nil),      // 31: rpc.SessionReport
+	(*SessionEvent)(nil),       // 32: rpc.SessionEvent
+	(*DrainParam)(nil),         // 33: rpc.DrainParam
+	(*CapacityReport)(nil),     // 34: rpc.CapacityReport
+	(*InterfaceCapacity)(nil),  // 35: rpc.InterfaceCapacity
+	(*AdoptParam)(nil),         // 36: rpc.AdoptParam
+	(*AdoptResult)(nil),        // 37: rpc.AdoptResult
+	(*DrainStatus)(nil),        // 38: rpc.DrainStatus
+	(*OfferParam)(nil),         // 39: rpc.OfferParam
+	(*ReofferParam)(nil),       // 40: rpc.ReofferParam
+	(*NodeProperty)(nil),       // 41: rpc.NodeProperty
+	(*NodeCapability)(nil),     // 42: rpc.NodeCapability
+	(*MessageCapability)(nil),  // 43: rpc.MessageCapability
+	(*CommandCapability)(nil),  // 44: rpc.CommandCapability
+	(*Capabilities)(nil),       // 45: rpc.Capabilities
+	(*Answer)(nil),             // 46: rpc.Answer
+	(*SystemEvent)(nil),        // 47: rpc.SystemEvent
+	(*PeerFrame)(nil),          // 48: rpc.PeerFrame
 }
 var file_msapi_proto_depIdxs = []int32{
 	0,  // 0: rpc.VersionNumber.ver:type_name -> rpc.Version
 	1,  // 1: rpc.CodecInfo.payload_type:type_name -> rpc.CodecType
-	5,  // 2: rpc.CreateParam.codecs:type_name -> rpc.CodecInfo
-	2,  // 3: rpc.SystemEvent.cmd:type_name -> rpc.SystemCommand
-	4,  // 4: rpc.MediaApi.GetVersion:input_type -> rpc.Empty
-	6,  // 5: rpc.MediaApi.PrepareSession:input_type -> rpc.CreateParam
-	7,  // 6: rpc.MediaApi.UpdateSession:input_type -> rpc.UpdateParam
-	8,  // 7: rpc.MediaApi.StartSession:input_type -> rpc.StartParam
-	9,  // 8: rpc.MediaApi.StopSession:input_type -> rpc.StopParam
-	12, // 9: rpc.MediaApi.ExecuteAction:input_type -> rpc.Action
-	12, // 10: rpc.MediaApi.ExecuteActionWithNotify:input_type -> rpc.Action
-	15, // 11: rpc.MediaApi.ExecuteActionWithPush:input_type -> rpc.PushData
-	16, // 12: rpc.MediaApi.SystemChannel:input_type -> rpc.SystemEvent
-	3,  // 13: rpc.MediaApi.GetVersion:output_type -> rpc.VersionNumber
-	11, // 14: rpc.MediaApi.PrepareSession:output_type -> rpc.Session
-	10, // 15: rpc.MediaApi.UpdateSession:output_type -> rpc.Status
-	10, // 16: rpc.MediaApi.StartSession:output_type -> rpc.Status
-	10, // 17: rpc.MediaApi.StopSession:output_type -> rpc.Status
-	13, // 18: rpc.MediaApi.ExecuteAction:output_type -> rpc.ActionResult
-	14, // 19: rpc.MediaApi.ExecuteActionWithNotify:output_type -> rpc.ActionEvent
-	13, // 20: rpc.MediaApi.ExecuteActionWithPush:output_type -> rpc.ActionResult
-	16, // 21: rpc.MediaApi.SystemChannel:output_type -> rpc.SystemEvent
-	13, // [13:22] is the sub-list for method output_type
-	4,  // [4:13] is the sub-list for method input_type
-	4,  // [4:4] is the sub-list for extension type_name
-	4,  // [4:4] is the sub-list for extension extendee
-	0,  // [0:4] is the sub-list for field type_name
+	2,  // 2: rpc.WatchdogPolicy.action:type_name -> rpc.WatchdogAction
+	9,  // 3: rpc.CreateParam.codecs:type_name -> rpc.CodecInfo
+	10, // 4: rpc.CreateParam.watchdog:type_name -> rpc.WatchdogPolicy
+	22, // 5: rpc.SessionList.sessions:type_name -> rpc.SessionInfo
+	22, // 6: rpc.SessionDescription.info:type_name -> rpc.SessionInfo
+	9,  // 7: rpc.SessionDescription.codecs:type_name -> rpc.CodecInfo
+	25, // 8: rpc.SessionDescription.watchdog:type_name -> rpc.WatchdogInfo
+	26, // 9: rpc.SessionDescription.stats:type_name -> rpc.RtpStats
+	27, // 10: rpc.SessionDescription.nodes:type_name -> rpc.GraphNode
+	28, // 11: rpc.SessionDescription.links:type_name -> rpc.GraphLink
+	3,  // 12: rpc.SessionReport.stop_reason:type_name -> rpc.StopReason
+	9,  // 13: rpc.SessionReport.codecs:type_name -> rpc.CodecInfo
+	26, // 14: rpc.SessionReport.stats:type_name -> rpc.RtpStats
+	4,  // 15: rpc.SessionEvent.type:type_name -> rpc.SessionEventType
+	3,  // 16: rpc.SessionEvent.stop_reason:type_name -> rpc.StopReason
+	31, // 17: rpc.SessionEvent.report:type_name -> rpc.SessionReport
+	35, // 18: rpc.CapacityReport.interfaces:type_name -> rpc.InterfaceCapacity
+	10, // 19: rpc.OfferParam.watchdog:type_name -> rpc.WatchdogPolicy
+	41, // 20: rpc.NodeCapability.properties:type_name -> rpc.NodeProperty
+	5,  // 21: rpc.CommandCapability.trait:type_name -> rpc.CommandTraitType
+	0,  // 22: rpc.Capabilities.version:type_name -> rpc.Version
+	42, // 23: rpc.Capabilities.nodes:type_name -> rpc.NodeCapability
+	43, // 24: rpc.Capabilities.messages:type_name -> rpc.MessageCapability
+	1,  // 25: rpc.Capabilities.codecs:type_name -> rpc.CodecType
+	44, // 26: rpc.Capabilities.commands:type_name -> rpc.CommandCapability
+	16, // 27: rpc.Answer.session:type_name -> rpc.Session
+	9,  // 28: rpc.Answer.codecs:type_name -> rpc.CodecInfo
+	6,  // 29: rpc.SystemEvent.cmd:type_name -> rpc.SystemCommand
+	31, // 30: rpc.SystemEvent.report:type_name -> rpc.SessionReport
+	34, // 31: rpc.SystemEvent.capacity:type_name -> rpc.CapacityReport
+	8,  // 32: rpc.MediaApi.GetVersion:input_type -> rpc.Empty
+	11, // 33: rpc.MediaApi.PrepareSession:input_type -> rpc.CreateParam
+	12, // 34: rpc.MediaApi.UpdateSession:input_type -> rpc.UpdateParam
+	13, // 35: rpc.MediaApi.StartSession:input_type -> rpc.StartParam
+	14, // 36: rpc.MediaApi.StopSession:input_type -> rpc.StopParam
+	17, // 37: rpc.MediaApi.ExecuteAction:input_type -> rpc.Action
+	17, // 38: rpc.MediaApi.ExecuteActionWithNotify:input_type -> rpc.Action
+	20, // 39: rpc.MediaApi.ExecuteActionWithPush:input_type -> rpc.PushData
+	47, // 40: rpc.MediaApi.SystemChannel:input_type -> rpc.SystemEvent
+	21, // 41: rpc.MediaApi.ListSessions:input_type -> rpc.ListParam
+	24, // 42: rpc.MediaApi.DescribeSession:input_type -> rpc.DescribeParam
+	30, // 43: rpc.MediaApi.WatchSessions:input_type -> rpc.WatchParam
+	33, // 44: rpc.MediaApi.Drain:input_type -> rpc.DrainParam
+	39, // 45: rpc.MediaApi.PrepareSessionWithOffer:input_type -> rpc.OfferParam
+	40, // 46: rpc.MediaApi.UpdateSessionWithOffer:input_type -> rpc.ReofferParam
+	8,  // 47: rpc.MediaApi.GetCapabilities:input_type -> rpc.Empty
+	36, // 48: rpc.MediaApi.AdoptSessions:input_type -> rpc.AdoptParam
+	8,  // 49: rpc.MediaApi.GetCapacity:input_type -> rpc.Empty
+	48, // 50: rpc.MediaApi.PeerLink:input_type -> rpc.PeerFrame
+	7,  // 51: rpc.MediaApi.GetVersion:output_type -> rpc.VersionNumber
+	16, // 52: rpc.MediaApi.PrepareSession:output_type -> rpc.Session
+	15, // 53: rpc.MediaApi.UpdateSession:output_type -> rpc.Status
+	15, // 54: rpc.MediaApi.StartSession:output_type -> rpc.Status
+	15, // 55: rpc.MediaApi.StopSession:output_type -> rpc.Status
+	18, // 56: rpc.MediaApi.ExecuteAction:output_type -> rpc.ActionResult
+	19, // 57: rpc.MediaApi.ExecuteActionWithNotify:output_type -> rpc.ActionEvent
+	18, // 58: rpc.MediaApi.ExecuteActionWithPush:output_type -> rpc.ActionResult
+	47, // 59: rpc.MediaApi.SystemChannel:output_type -> rpc.SystemEvent
+	23, // 60: rpc.MediaApi.ListSessions:output_type -> rpc.SessionList
+	29, // 61: rpc.MediaApi.DescribeSession:output_type -> rpc.SessionDescription
+	32, // 62: rpc.MediaApi.WatchSessions:output_type -> rpc.SessionEvent
+	38, // 63: rpc.MediaApi.Drain:output_type -> rpc.DrainStatus
+	46, // 64: rpc.MediaApi.PrepareSessionWithOffer:output_type -> rpc.Answer
+	46, // 65: rpc.MediaApi.UpdateSessionWithOffer:output_type -> rpc.Answer
+	45, // 66: rpc.MediaApi.GetCapabilities:output_type -> rpc.Capabilities
+	37, // 67: rpc.MediaApi.AdoptSessions:output_type -> rpc.AdoptResult
+	34, // 68: rpc.MediaApi.GetCapacity:output_type -> rpc.CapacityReport
+	48, // 69: rpc.MediaApi.PeerLink:output_type -> rpc.PeerFrame
+	51, // [51:70] is the sub-list for method output_type
+	32, // [32:51] is the sub-list for method input_type
+	32, // [32:32] is the sub-list for extension type_name
+	32, // [32:32] is the sub-list for extension extendee
+	0,  // [0:32] is the sub-list for field type_name
 }
 
 func init() { file_msapi_proto_init() }
@@ -1232,8 +4050,92 @@ func file_msapi_proto_init() {
 				return nil
 			}
 		}
-		file_msapi_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Empty); i {
+		file_msapi_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Empty); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_msapi_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CodecInfo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_msapi_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchdogPolicy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_msapi_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateParam); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_msapi_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateParam); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_msapi_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StartParam); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_msapi_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StopParam); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_msapi_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Status); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1244,8 +4146,8 @@ func file_msapi_proto_init() {
 				return nil
 			}
 		}
-		file_msapi_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CodecInfo); i {
+		file_msapi_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Session); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1256,8 +4158,8 @@ func file_msapi_proto_init() {
 				return nil
 			}
 		}
-		file_msapi_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CreateParam); i {
+		file_msapi_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Action); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1268,8 +4170,8 @@ func file_msapi_proto_init() {
 				return nil
 			}
 		}
-		file_msapi_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UpdateParam); i {
+		file_msapi_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ActionResult); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1280,8 +4182,8 @@ func file_msapi_proto_init() {
 				return nil
 			}
 		}
-		file_msapi_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StartParam); i {
+		file_msapi_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ActionEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1292,8 +4194,8 @@ func file_msapi_proto_init() {
 				return nil
 			}
 		}
-		file_msapi_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StopParam); i {
+		file_msapi_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PushData); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1304,8 +4206,8 @@ func file_msapi_proto_init() {
 				return nil
 			}
 		}
-		file_msapi_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Status); i {
+		file_msapi_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListParam); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1316,8 +4218,8 @@ func file_msapi_proto_init() {
 				return nil
 			}
 		}
-		file_msapi_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Session); i {
+		file_msapi_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SessionInfo); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1328,8 +4230,8 @@ func file_msapi_proto_init() {
 				return nil
 			}
 		}
-		file_msapi_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Action); i {
+		file_msapi_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SessionList); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1340,8 +4242,8 @@ func file_msapi_proto_init() {
 				return nil
 			}
 		}
-		file_msapi_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ActionResult); i {
+		file_msapi_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DescribeParam); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1352,8 +4254,8 @@ func file_msapi_proto_init() {
 				return nil
 			}
 		}
-		file_msapi_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ActionEvent); i {
+		file_msapi_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchdogInfo); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1364,8 +4266,8 @@ func file_msapi_proto_init() {
 				return nil
 			}
 		}
-		file_msapi_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PushData); i {
+		file_msapi_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RtpStats); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1376,7 +4278,247 @@ func file_msapi_proto_init() {
 				return nil
 			}
 		}
-		file_msapi_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+		file_msapi_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GraphNode); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_msapi_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GraphLink); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_msapi_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SessionDescription); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_msapi_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchParam); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_msapi_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SessionReport); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_msapi_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SessionEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_msapi_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DrainParam); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_msapi_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CapacityReport); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_msapi_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InterfaceCapacity); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_msapi_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdoptParam); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_msapi_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdoptResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_msapi_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DrainStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_msapi_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OfferParam); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_msapi_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReofferParam); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_msapi_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NodeProperty); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_msapi_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NodeCapability); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_msapi_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MessageCapability); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_msapi_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CommandCapability); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_msapi_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Capabilities); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_msapi_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Answer); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_msapi_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SystemEvent); i {
 			case 0:
 				return &v.state
@@ -1388,14 +4530,26 @@ func file_msapi_proto_init() {
 				return nil
 			}
 		}
+		file_msapi_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PeerFrame); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_msapi_proto_rawDesc,
-			NumEnums:      3,
-			NumMessages:   14,
+			NumEnums:      7,
+			NumMessages:   42,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
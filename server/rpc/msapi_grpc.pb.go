@@ -27,6 +27,16 @@ type MediaApiClient interface {
 	ExecuteActionWithNotify(ctx context.Context, in *Action, opts ...grpc.CallOption) (MediaApi_ExecuteActionWithNotifyClient, error)
 	ExecuteActionWithPush(ctx context.Context, opts ...grpc.CallOption) (MediaApi_ExecuteActionWithPushClient, error)
 	SystemChannel(ctx context.Context, opts ...grpc.CallOption) (MediaApi_SystemChannelClient, error)
+	ListSessions(ctx context.Context, in *ListParam, opts ...grpc.CallOption) (*SessionList, error)
+	DescribeSession(ctx context.Context, in *DescribeParam, opts ...grpc.CallOption) (*SessionDescription, error)
+	WatchSessions(ctx context.Context, in *WatchParam, opts ...grpc.CallOption) (MediaApi_WatchSessionsClient, error)
+	Drain(ctx context.Context, in *DrainParam, opts ...grpc.CallOption) (*DrainStatus, error)
+	PrepareSessionWithOffer(ctx context.Context, in *OfferParam, opts ...grpc.CallOption) (*Answer, error)
+	UpdateSessionWithOffer(ctx context.Context, in *ReofferParam, opts ...grpc.CallOption) (*Answer, error)
+	GetCapabilities(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Capabilities, error)
+	AdoptSessions(ctx context.Context, in *AdoptParam, opts ...grpc.CallOption) (*AdoptResult, error)
+	GetCapacity(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*CapacityReport, error)
+	PeerLink(ctx context.Context, opts ...grpc.CallOption) (MediaApi_PeerLinkClient, error)
 }
 
 type mediaApiClient struct {
@@ -188,6 +198,141 @@ func (x *mediaApiSystemChannelClient) Recv() (*SystemEvent, error) {
 	return m, nil
 }
 
+func (c *mediaApiClient) ListSessions(ctx context.Context, in *ListParam, opts ...grpc.CallOption) (*SessionList, error) {
+	out := new(SessionList)
+	err := c.cc.Invoke(ctx, "/rpc.MediaApi/ListSessions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaApiClient) DescribeSession(ctx context.Context, in *DescribeParam, opts ...grpc.CallOption) (*SessionDescription, error) {
+	out := new(SessionDescription)
+	err := c.cc.Invoke(ctx, "/rpc.MediaApi/DescribeSession", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaApiClient) WatchSessions(ctx context.Context, in *WatchParam, opts ...grpc.CallOption) (MediaApi_WatchSessionsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &MediaApi_ServiceDesc.Streams[3], "/rpc.MediaApi/WatchSessions", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &mediaApiWatchSessionsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MediaApi_WatchSessionsClient interface {
+	Recv() (*SessionEvent, error)
+	grpc.ClientStream
+}
+
+type mediaApiWatchSessionsClient struct {
+	grpc.ClientStream
+}
+
+func (x *mediaApiWatchSessionsClient) Recv() (*SessionEvent, error) {
+	m := new(SessionEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *mediaApiClient) Drain(ctx context.Context, in *DrainParam, opts ...grpc.CallOption) (*DrainStatus, error) {
+	out := new(DrainStatus)
+	err := c.cc.Invoke(ctx, "/rpc.MediaApi/Drain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaApiClient) PrepareSessionWithOffer(ctx context.Context, in *OfferParam, opts ...grpc.CallOption) (*Answer, error) {
+	out := new(Answer)
+	err := c.cc.Invoke(ctx, "/rpc.MediaApi/PrepareSessionWithOffer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaApiClient) UpdateSessionWithOffer(ctx context.Context, in *ReofferParam, opts ...grpc.CallOption) (*Answer, error) {
+	out := new(Answer)
+	err := c.cc.Invoke(ctx, "/rpc.MediaApi/UpdateSessionWithOffer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaApiClient) GetCapabilities(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Capabilities, error) {
+	out := new(Capabilities)
+	err := c.cc.Invoke(ctx, "/rpc.MediaApi/GetCapabilities", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaApiClient) AdoptSessions(ctx context.Context, in *AdoptParam, opts ...grpc.CallOption) (*AdoptResult, error) {
+	out := new(AdoptResult)
+	err := c.cc.Invoke(ctx, "/rpc.MediaApi/AdoptSessions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaApiClient) GetCapacity(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*CapacityReport, error) {
+	out := new(CapacityReport)
+	err := c.cc.Invoke(ctx, "/rpc.MediaApi/GetCapacity", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mediaApiClient) PeerLink(ctx context.Context, opts ...grpc.CallOption) (MediaApi_PeerLinkClient, error) {
+	stream, err := c.cc.NewStream(ctx, &MediaApi_ServiceDesc.Streams[4], "/rpc.MediaApi/PeerLink", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &mediaApiPeerLinkClient{stream}
+	return x, nil
+}
+
+type MediaApi_PeerLinkClient interface {
+	Send(*PeerFrame) error
+	Recv() (*PeerFrame, error)
+	grpc.ClientStream
+}
+
+type mediaApiPeerLinkClient struct {
+	grpc.ClientStream
+}
+
+func (x *mediaApiPeerLinkClient) Send(m *PeerFrame) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *mediaApiPeerLinkClient) Recv() (*PeerFrame, error) {
+	m := new(PeerFrame)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // MediaApiServer is the server API for MediaApi service.
 // All implementations must embed UnimplementedMediaApiServer
 // for forward compatibility
@@ -201,6 +346,16 @@ type MediaApiServer interface {
 	ExecuteActionWithNotify(*Action, MediaApi_ExecuteActionWithNotifyServer) error
 	ExecuteActionWithPush(MediaApi_ExecuteActionWithPushServer) error
 	SystemChannel(MediaApi_SystemChannelServer) error
+	ListSessions(context.Context, *ListParam) (*SessionList, error)
+	DescribeSession(context.Context, *DescribeParam) (*SessionDescription, error)
+	WatchSessions(*WatchParam, MediaApi_WatchSessionsServer) error
+	Drain(context.Context, *DrainParam) (*DrainStatus, error)
+	PrepareSessionWithOffer(context.Context, *OfferParam) (*Answer, error)
+	UpdateSessionWithOffer(context.Context, *ReofferParam) (*Answer, error)
+	GetCapabilities(context.Context, *Empty) (*Capabilities, error)
+	AdoptSessions(context.Context, *AdoptParam) (*AdoptResult, error)
+	GetCapacity(context.Context, *Empty) (*CapacityReport, error)
+	PeerLink(MediaApi_PeerLinkServer) error
 	mustEmbedUnimplementedMediaApiServer()
 }
 
@@ -235,6 +390,36 @@ func (UnimplementedMediaApiServer) ExecuteActionWithPush(MediaApi_ExecuteActionW
 func (UnimplementedMediaApiServer) SystemChannel(MediaApi_SystemChannelServer) error {
 	return status.Errorf(codes.Unimplemented, "method SystemChannel not implemented")
 }
+func (UnimplementedMediaApiServer) ListSessions(context.Context, *ListParam) (*SessionList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessions not implemented")
+}
+func (UnimplementedMediaApiServer) DescribeSession(context.Context, *DescribeParam) (*SessionDescription, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DescribeSession not implemented")
+}
+func (UnimplementedMediaApiServer) WatchSessions(*WatchParam, MediaApi_WatchSessionsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchSessions not implemented")
+}
+func (UnimplementedMediaApiServer) Drain(context.Context, *DrainParam) (*DrainStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Drain not implemented")
+}
+func (UnimplementedMediaApiServer) PrepareSessionWithOffer(context.Context, *OfferParam) (*Answer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PrepareSessionWithOffer not implemented")
+}
+func (UnimplementedMediaApiServer) UpdateSessionWithOffer(context.Context, *ReofferParam) (*Answer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateSessionWithOffer not implemented")
+}
+func (UnimplementedMediaApiServer) GetCapabilities(context.Context, *Empty) (*Capabilities, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCapabilities not implemented")
+}
+func (UnimplementedMediaApiServer) AdoptSessions(context.Context, *AdoptParam) (*AdoptResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdoptSessions not implemented")
+}
+func (UnimplementedMediaApiServer) GetCapacity(context.Context, *Empty) (*CapacityReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCapacity not implemented")
+}
+func (UnimplementedMediaApiServer) PeerLink(MediaApi_PeerLinkServer) error {
+	return status.Errorf(codes.Unimplemented, "method PeerLink not implemented")
+}
 func (UnimplementedMediaApiServer) mustEmbedUnimplementedMediaApiServer() {}
 
 // UnsafeMediaApiServer may be embedded to opt out of forward compatibility for this service.
@@ -429,6 +614,197 @@ func (x *mediaApiSystemChannelServer) Recv() (*SystemEvent, error) {
 	return m, nil
 }
 
+func _MediaApi_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListParam)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaApiServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.MediaApi/ListSessions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaApiServer).ListSessions(ctx, req.(*ListParam))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaApi_DescribeSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DescribeParam)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaApiServer).DescribeSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.MediaApi/DescribeSession",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaApiServer).DescribeSession(ctx, req.(*DescribeParam))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaApi_WatchSessions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchParam)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MediaApiServer).WatchSessions(m, &mediaApiWatchSessionsServer{stream})
+}
+
+type MediaApi_WatchSessionsServer interface {
+	Send(*SessionEvent) error
+	grpc.ServerStream
+}
+
+type mediaApiWatchSessionsServer struct {
+	grpc.ServerStream
+}
+
+func (x *mediaApiWatchSessionsServer) Send(m *SessionEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _MediaApi_Drain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DrainParam)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaApiServer).Drain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.MediaApi/Drain",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaApiServer).Drain(ctx, req.(*DrainParam))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaApi_PrepareSessionWithOffer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OfferParam)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaApiServer).PrepareSessionWithOffer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.MediaApi/PrepareSessionWithOffer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaApiServer).PrepareSessionWithOffer(ctx, req.(*OfferParam))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaApi_UpdateSessionWithOffer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReofferParam)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaApiServer).UpdateSessionWithOffer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.MediaApi/UpdateSessionWithOffer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaApiServer).UpdateSessionWithOffer(ctx, req.(*ReofferParam))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaApi_GetCapabilities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaApiServer).GetCapabilities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.MediaApi/GetCapabilities",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaApiServer).GetCapabilities(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaApi_AdoptSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdoptParam)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaApiServer).AdoptSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.MediaApi/AdoptSessions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaApiServer).AdoptSessions(ctx, req.(*AdoptParam))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaApi_GetCapacity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MediaApiServer).GetCapacity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.MediaApi/GetCapacity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MediaApiServer).GetCapacity(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MediaApi_PeerLink_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(MediaApiServer).PeerLink(&mediaApiPeerLinkServer{stream})
+}
+
+type MediaApi_PeerLinkServer interface {
+	Send(*PeerFrame) error
+	Recv() (*PeerFrame, error)
+	grpc.ServerStream
+}
+
+type mediaApiPeerLinkServer struct {
+	grpc.ServerStream
+}
+
+func (x *mediaApiPeerLinkServer) Send(m *PeerFrame) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *mediaApiPeerLinkServer) Recv() (*PeerFrame, error) {
+	m := new(PeerFrame)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // MediaApi_ServiceDesc is the grpc.ServiceDesc for MediaApi service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -460,6 +836,38 @@ var MediaApi_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ExecuteAction",
 			Handler:    _MediaApi_ExecuteAction_Handler,
 		},
+		{
+			MethodName: "ListSessions",
+			Handler:    _MediaApi_ListSessions_Handler,
+		},
+		{
+			MethodName: "DescribeSession",
+			Handler:    _MediaApi_DescribeSession_Handler,
+		},
+		{
+			MethodName: "Drain",
+			Handler:    _MediaApi_Drain_Handler,
+		},
+		{
+			MethodName: "PrepareSessionWithOffer",
+			Handler:    _MediaApi_PrepareSessionWithOffer_Handler,
+		},
+		{
+			MethodName: "UpdateSessionWithOffer",
+			Handler:    _MediaApi_UpdateSessionWithOffer_Handler,
+		},
+		{
+			MethodName: "GetCapabilities",
+			Handler:    _MediaApi_GetCapabilities_Handler,
+		},
+		{
+			MethodName: "AdoptSessions",
+			Handler:    _MediaApi_AdoptSessions_Handler,
+		},
+		{
+			MethodName: "GetCapacity",
+			Handler:    _MediaApi_GetCapacity_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -478,6 +886,17 @@ var MediaApi_ServiceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "WatchSessions",
+			Handler:       _MediaApi_WatchSessions_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "PeerLink",
+			Handler:       _MediaApi_PeerLink_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "msapi.proto",
 }
@@ -0,0 +1,140 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"github.com/appcrash/media/server/prom"
+	"net"
+	"time"
+)
+
+// DefaultRtpInterface is the name of interface built from Config.RtpIp/StartPort/EndPort
+const DefaultRtpInterface = "default"
+
+// ErrPortExhausted is wrapped by the error returned when an rtp interface has no free port pair
+var ErrPortExhausted = errors.New("rtp port exhausted")
+
+// RtpInterface is a named local address with its own rtp port range, sessions select one of them by
+// CreateParam.InterfaceName, i.e. public, private or ipv6 interfaces of a host
+type RtpInterface struct {
+	Name               string
+	Ip                 string
+	StartPort, EndPort uint16
+	Reserved           []PortRange // ports never allocated to sessions
+}
+
+type rtpInterface struct {
+	name     string
+	ip       *net.IPAddr
+	portPool *PortPool
+}
+
+func newRtpInterface(ri *RtpInterface, quarantine time.Duration, validateBind bool) (*rtpInterface, error) {
+	ip, err := net.ResolveIPAddr("ip", ri.Ip)
+	if err != nil {
+		return nil, fmt.Errorf("rtp interface %v: invalid ip %v", ri.Name, ri.Ip)
+	}
+	if ri.StartPort >= ri.EndPort {
+		return nil, fmt.Errorf("rtp interface %v: invalid port range [%v,%v)", ri.Name, ri.StartPort, ri.EndPort)
+	}
+	itf := &rtpInterface{
+		name:     ri.Name,
+		ip:       ip,
+		portPool: NewPortPool(),
+	}
+	for _, r := range ri.Reserved {
+		itf.portPool.Reserve(r)
+	}
+	itf.portPool.Init(ri.StartPort, ri.EndPort)
+	itf.portPool.SetQuarantine(quarantine)
+	if validateBind {
+		itf.portPool.SetValidator(itf.validateBind)
+	}
+	prom.RtpInterfacePortCapacity.WithLabelValues(itf.name).Set(float64(itf.portPool.Capacity()))
+	prom.RtpInterfacePortUsed.WithLabelValues(itf.name).Set(0)
+	prom.RtpInterfacePortQuarantined.Set(itf.name, func() float64 {
+		return float64(itf.portPool.Quarantined())
+	})
+	return itf, nil
+}
+
+// validateBind checks both rtp and rtcp ports can be bound on this interface
+func (itf *rtpInterface) validateBind(port uint16) error {
+	var conns []*net.UDPConn
+	defer func() {
+		for _, c := range conns {
+			c.Close()
+		}
+	}()
+	for _, p := range []uint16{port, port + 1} {
+		c, err := net.ListenUDP("udp", &net.UDPAddr{IP: itf.ip.IP, Port: int(p), Zone: itf.ip.Zone})
+		if err != nil {
+			prom.RtpInterfacePortBindFailed.WithLabelValues(itf.name).Inc()
+			return err
+		}
+		conns = append(conns, c)
+	}
+	return nil
+}
+
+// rtpInterfacesOf builds interfaces declared in config, the first one is the default
+func rtpInterfacesOf(c *Config) (itfs []*rtpInterface, err error) {
+	var declared []RtpInterface
+	if c.RtpIp != "" {
+		declared = append(declared, RtpInterface{
+			Name:      DefaultRtpInterface,
+			Ip:        c.RtpIp,
+			StartPort: c.StartPort,
+			EndPort:   c.EndPort,
+			Reserved:  c.ReservedPorts,
+		})
+	}
+	quarantine := c.PortQuarantine
+	if quarantine < 0 {
+		quarantine = 0
+	}
+	declared = append(declared, c.RtpInterfaces...)
+	if len(declared) == 0 {
+		return nil, errors.New("no rtp interface configured")
+	}
+	for i := range declared {
+		ri := &declared[i]
+		if ri.Name == "" {
+			return nil, fmt.Errorf("rtp interface with ip %v has no name", ri.Ip)
+		}
+		var itf *rtpInterface
+		if itf, err = newRtpInterface(ri, quarantine, c.ValidatePortBind); err != nil {
+			return
+		}
+		for _, other := range itfs {
+			if other.name == itf.name {
+				return nil, fmt.Errorf("duplicated rtp interface name: %v", itf.name)
+			}
+			if other.ip.IP.Equal(itf.ip.IP) && other.portPool.overlap(itf.portPool) {
+				return nil, fmt.Errorf("rtp interface %v and %v have overlapped port range on %v",
+					other.name, itf.name, itf.ip)
+			}
+		}
+		itfs = append(itfs, itf)
+	}
+	return
+}
+
+func (itf *rtpInterface) getPort() (port uint16, err error) {
+	if port = itf.portPool.Get(); port == 0 {
+		prom.RtpInterfacePortExhausted.WithLabelValues(itf.name).Inc()
+		return 0, fmt.Errorf("rtp interface %v: %w", itf.name, ErrPortExhausted)
+	}
+	prom.UsedPortPair.Inc()
+	prom.RtpInterfacePortUsed.WithLabelValues(itf.name).Inc()
+	return
+}
+
+func (itf *rtpInterface) putPort(port uint16) {
+	if port == 0 {
+		return
+	}
+	itf.portPool.Put(port)
+	prom.UsedPortPair.Dec()
+	prom.RtpInterfacePortUsed.WithLabelValues(itf.name).Dec()
+}
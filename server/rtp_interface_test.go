@@ -0,0 +1,90 @@
+package server_test
+
+import (
+	"context"
+	"github.com/appcrash/media/server"
+	"github.com/appcrash/media/server/rpc"
+	"testing"
+	"time"
+)
+
+func TestRtpInterfaceConfig(t *testing.T) {
+	invalid := [][]server.RtpInterface{
+		{{Name: "a", Ip: "127.0.0.1", StartPort: 30000, EndPort: 30010}, {Name: "a", Ip: "127.0.0.1", StartPort: 30100, EndPort: 30110}},
+		{{Name: "a", Ip: "127.0.0.1", StartPort: 30000, EndPort: 30010}, {Name: "b", Ip: "127.0.0.1", StartPort: 30008, EndPort: 30020}},
+		{{Name: "a", Ip: "127.0.0.1", StartPort: 30010, EndPort: 30000}},
+		{{Ip: "127.0.0.1", StartPort: 30000, EndPort: 30010}},
+		{},
+	}
+	for i, itfs := range invalid {
+		if _, _, err := server.NewServer(&server.Config{
+			RtpInterfaces: itfs,
+			GrpcIp:        grpcIp,
+			GrpcPort:      freePort(t),
+		}); err == nil {
+			t.Fatalf("config %v should be invalid", i)
+		}
+	}
+}
+
+func TestRtpInterfaceSelection(t *testing.T) {
+	client := startTestServer(t, &server.Config{
+		RtpIp: "127.0.0.1",
+		RtpInterfaces: []server.RtpInterface{
+			{Name: "private", Ip: "127.0.0.2", StartPort: 30000, EndPort: 30004},
+		},
+		PortQuarantine: 200 * time.Millisecond,
+	}).client
+
+	ctx := context.Background()
+	prepare := func(itf string) (*rpc.Session, error) {
+		return client.PrepareSession(ctx, &rpc.CreateParam{
+			PeerIp:   "127.0.0.1",
+			PeerPort: 40000,
+			Codecs: []*rpc.CodecInfo{{
+				PayloadNumber: 8,
+				PayloadType:   rpc.CodecType_PCM_ALAW,
+			}},
+			GraphDesc:     "[echo]",
+			InstanceId:    "interface",
+			InterfaceName: itf,
+		})
+	}
+
+	s, err := prepare("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.LocalIp != "127.0.0.1" || s.InterfaceName != server.DefaultRtpInterface {
+		t.Fatalf("session should bind to default interface: %v", s)
+	}
+	client.StopSession(ctx, &rpc.StopParam{SessionId: s.SessionId})
+
+	// private interface has two port pairs
+	var ids []string
+	for i := 0; i < 2; i++ {
+		if s, err = prepare("private"); err != nil {
+			t.Fatal(err)
+		}
+		if s.LocalIp != "127.0.0.2" || s.InterfaceName != "private" || s.LocalRtpPort < 30000 || s.LocalRtpPort >= 30004 {
+			t.Fatalf("session should bind to private interface: %v", s)
+		}
+		ids = append(ids, s.SessionId)
+	}
+	if _, err = prepare("private"); err == nil {
+		t.Fatal("private interface should run out of port")
+	}
+	if _, err = prepare("public"); err == nil {
+		t.Fatal("unknown interface should be rejected")
+	}
+	for _, id := range ids {
+		client.StopSession(ctx, &rpc.StopParam{SessionId: id})
+	}
+	if _, err = prepare("private"); err == nil {
+		t.Fatal("port should be quarantined after session stopped")
+	}
+	time.Sleep(300 * time.Millisecond)
+	if _, err = prepare("private"); err != nil {
+		t.Fatalf("port should be reclaimed after quarantine: %v", err)
+	}
+}
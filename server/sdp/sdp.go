@@ -0,0 +1,318 @@
+// Package sdp parses and builds session descriptions(RFC 4566) for offer/answer of rtp sessions. it only covers
+// lines needed by media server, unknown lines are ignored when parsing.
+package sdp
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+const (
+	DirectionSendRecv = "sendrecv"
+	DirectionSendOnly = "sendonly"
+	DirectionRecvOnly = "recvonly"
+	DirectionInactive = "inactive"
+)
+
+type Origin struct {
+	Username           string
+	SessionId, Version uint64
+	Address            string
+}
+
+type Attribute struct {
+	Key, Value string // Value is empty for property attribute like "a=sendrecv"
+}
+
+type Description struct {
+	Origin     Origin
+	Name       string
+	Connection string // address of session level "c=" line, empty if absent
+	Attributes []Attribute
+	Media      []*Media
+}
+
+type Media struct {
+	Type       string // audio, video ...
+	Port       int
+	Proto      string // RTP/AVP ...
+	Formats    []string
+	Connection string // address of media level "c=" line, empty if absent
+	Attributes []Attribute
+}
+
+// RtpMap is value of "a=rtpmap:"
+type RtpMap struct {
+	PayloadNumber int
+	Encoding      string
+	ClockRate     int
+	Channels      int // zero if not given
+}
+
+// FmtpParam is one of ';' separated parameters of "a=fmtp:", Value is empty if no '=' in it
+type FmtpParam struct {
+	Key, Value string
+}
+
+// staticRtpMaps are payload numbers that can be used without rtpmap(RFC 3551)
+var staticRtpMaps = map[int]RtpMap{
+	0:  {0, "PCMU", 8000, 0},
+	8:  {8, "PCMA", 8000, 0},
+	9:  {9, "G722", 8000, 0},
+	18: {18, "G729", 8000, 0},
+}
+
+func isDirection(key string) bool {
+	switch key {
+	case DirectionSendRecv, DirectionSendOnly, DirectionRecvOnly, DirectionInactive:
+		return true
+	}
+	return false
+}
+
+func parseConnection(value string) (string, error) {
+	fields := strings.Fields(value)
+	if len(fields) != 3 || fields[0] != "IN" {
+		return "", fmt.Errorf("invalid connection: %v", value)
+	}
+	// strip ttl or number of addresses of multicast address
+	return strings.SplitN(fields[2], "/", 2)[0], nil
+}
+
+func parseAttribute(value string) Attribute {
+	kv := strings.SplitN(value, ":", 2)
+	if len(kv) == 1 {
+		return Attribute{Key: kv[0]}
+	}
+	return Attribute{Key: kv[0], Value: kv[1]}
+}
+
+// Parse decodes session description, at least one media is required
+func Parse(s string) (d *Description, err error) {
+	var media *Media
+	d = &Description{}
+	for i, line := range strings.Split(s, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" {
+			continue
+		}
+		if len(line) < 2 || line[1] != '=' {
+			return nil, fmt.Errorf("invalid line %v: %v", i+1, line)
+		}
+		value := line[2:]
+		switch line[0] {
+		case 'v':
+			if value != "0" {
+				return nil, fmt.Errorf("unsupported version: %v", value)
+			}
+		case 'o':
+			fields := strings.Fields(value)
+			if len(fields) != 6 {
+				return nil, fmt.Errorf("invalid origin: %v", value)
+			}
+			d.Origin.Username, d.Origin.Address = fields[0], fields[5]
+			if d.Origin.SessionId, err = strconv.ParseUint(fields[1], 10, 64); err != nil {
+				return nil, fmt.Errorf("invalid origin session id: %v", fields[1])
+			}
+			if d.Origin.Version, err = strconv.ParseUint(fields[2], 10, 64); err != nil {
+				return nil, fmt.Errorf("invalid origin version: %v", fields[2])
+			}
+		case 's':
+			d.Name = value
+		case 'c':
+			var addr string
+			if addr, err = parseConnection(value); err != nil {
+				return nil, err
+			}
+			if media != nil {
+				media.Connection = addr
+			} else {
+				d.Connection = addr
+			}
+		case 'm':
+			fields := strings.Fields(value)
+			if len(fields) < 4 {
+				return nil, fmt.Errorf("invalid media: %v", value)
+			}
+			media = &Media{Type: fields[0], Proto: fields[2], Formats: fields[3:]}
+			// strip number of ports
+			if media.Port, err = strconv.Atoi(strings.SplitN(fields[1], "/", 2)[0]); err != nil ||
+				media.Port < 0 || media.Port > 0xffff {
+				return nil, fmt.Errorf("invalid media port: %v", fields[1])
+			}
+			d.Media = append(d.Media, media)
+		case 'a':
+			if media != nil {
+				media.Attributes = append(media.Attributes, parseAttribute(value))
+			} else {
+				d.Attributes = append(d.Attributes, parseAttribute(value))
+			}
+		}
+	}
+	if len(d.Media) == 0 {
+		return nil, errors.New("no media in session description")
+	}
+	return d, nil
+}
+
+// Marshal encodes session description with CRLF line endings
+func (d *Description) Marshal() string {
+	var b strings.Builder
+	line := func(format string, args ...interface{}) {
+		b.WriteString(fmt.Sprintf(format, args...))
+		b.WriteString("\r\n")
+	}
+	attributes := func(attrs []Attribute) {
+		for _, a := range attrs {
+			if a.Value == "" {
+				line("a=%v", a.Key)
+			} else {
+				line("a=%v:%v", a.Key, a.Value)
+			}
+		}
+	}
+	username := d.Origin.Username
+	if username == "" {
+		username = "-"
+	}
+	name := d.Name
+	if name == "" {
+		name = "-"
+	}
+	line("v=0")
+	line("o=%v %v %v IN %v %v", username, d.Origin.SessionId, d.Origin.Version, addrType(d.Origin.Address),
+		d.Origin.Address)
+	line("s=%v", name)
+	if d.Connection != "" {
+		line("c=IN %v %v", addrType(d.Connection), d.Connection)
+	}
+	line("t=0 0")
+	attributes(d.Attributes)
+	for _, m := range d.Media {
+		line("m=%v %v %v %v", m.Type, m.Port, m.Proto, strings.Join(m.Formats, " "))
+		if m.Connection != "" {
+			line("c=IN %v %v", addrType(m.Connection), m.Connection)
+		}
+		attributes(m.Attributes)
+	}
+	return b.String()
+}
+
+func addrType(addr string) string {
+	if ip := net.ParseIP(addr); ip != nil && ip.To4() == nil {
+		return "IP6"
+	}
+	return "IP4"
+}
+
+// ConnectionOf returns address of the media, session level one is used if media doesn't have it
+func (d *Description) ConnectionOf(m *Media) string {
+	if m.Connection != "" {
+		return m.Connection
+	}
+	return d.Connection
+}
+
+// DirectionOf returns direction attribute of the media, session level one is used if media doesn't have it
+func (d *Description) DirectionOf(m *Media) string {
+	for _, attrs := range [][]Attribute{m.Attributes, d.Attributes} {
+		for _, a := range attrs {
+			if isDirection(a.Key) {
+				return a.Key
+			}
+		}
+	}
+	return DirectionSendRecv
+}
+
+// AnswerDirection is the direction answerer uses for the offered one(RFC 3264)
+func AnswerDirection(offered string) string {
+	switch offered {
+	case DirectionSendOnly:
+		return DirectionRecvOnly
+	case DirectionRecvOnly:
+		return DirectionSendOnly
+	case DirectionInactive:
+		return DirectionInactive
+	}
+	return DirectionSendRecv
+}
+
+// attribute returns value of the first attribute with key and value prefix, i.e. "rtpmap" and "8 "
+func (m *Media) attribute(key, prefix string) (string, bool) {
+	for _, a := range m.Attributes {
+		if a.Key == key && strings.HasPrefix(a.Value, prefix) {
+			return strings.TrimPrefix(a.Value, prefix), true
+		}
+	}
+	return "", false
+}
+
+// RtpMap returns rtpmap of the payload number, static ones are used if media doesn't have it
+func (m *Media) RtpMap(pt int) (rm RtpMap, ok bool) {
+	value, found := m.attribute("rtpmap", strconv.Itoa(pt)+" ")
+	if !found {
+		rm, ok = staticRtpMaps[pt]
+		return
+	}
+	parts := strings.Split(strings.TrimSpace(value), "/")
+	if len(parts) < 2 {
+		return
+	}
+	rm.PayloadNumber, rm.Encoding = pt, parts[0]
+	if rm.ClockRate, ok = atoi(parts[1]); ok && len(parts) > 2 {
+		rm.Channels, ok = atoi(parts[2])
+	}
+	return
+}
+
+// Fmtp returns format parameters of the payload number, empty if not exist
+func (m *Media) Fmtp(pt int) string {
+	value, _ := m.attribute("fmtp", strconv.Itoa(pt)+" ")
+	return strings.TrimSpace(value)
+}
+
+func (rm RtpMap) String() string {
+	s := fmt.Sprintf("%v %v/%v", rm.PayloadNumber, rm.Encoding, rm.ClockRate)
+	if rm.Channels > 0 {
+		s += "/" + strconv.Itoa(rm.Channels)
+	}
+	return s
+}
+
+func atoi(s string) (int, bool) {
+	n, err := strconv.Atoi(s)
+	return n, err == nil
+}
+
+// ParseFmtp splits format parameters like "mode-set=0,2; octet-align=1", keys are lower-cased
+func ParseFmtp(s string) (params []FmtpParam) {
+	for _, p := range strings.Split(s, ";") {
+		if p = strings.TrimSpace(p); p == "" {
+			continue
+		}
+		kv := strings.SplitN(p, "=", 2)
+		param := FmtpParam{Key: strings.ToLower(strings.TrimSpace(kv[0]))}
+		if len(kv) == 2 {
+			param.Value = strings.TrimSpace(kv[1])
+		}
+		params = append(params, param)
+	}
+	return
+}
+
+// FormatFmtp joins format parameters by "; "
+func FormatFmtp(params []FmtpParam) string {
+	var ps []string
+	for _, p := range params {
+		if p.Value == "" {
+			ps = append(ps, p.Key)
+		} else {
+			ps = append(ps, p.Key+"="+p.Value)
+		}
+	}
+	return strings.Join(ps, "; ")
+}
@@ -0,0 +1,104 @@
+package sdp_test
+
+import (
+	"github.com/appcrash/media/server/sdp"
+	"reflect"
+	"testing"
+)
+
+const testOffer = "v=0\r\n" +
+	"o=alice 2890844526 2890844527 IN IP4 10.0.0.1\r\n" +
+	"s=call\r\n" +
+	"c=IN IP4 10.0.0.1\r\n" +
+	"t=0 0\r\n" +
+	"a=sendonly\r\n" +
+	"m=audio 49170 RTP/AVP 96 8 101\r\n" +
+	"a=rtpmap:96 AMR-WB/16000/1\r\n" +
+	"a=fmtp:96 mode-set=0,1,2; octet-align=1\r\n" +
+	"a=rtpmap:101 telephone-event/16000\r\n" +
+	"a=fmtp:101 0-16\r\n" +
+	"m=video 51372 RTP/AVP 99\r\n" +
+	"c=IN IP6 ::1\r\n" +
+	"a=rtpmap:99 H264/90000\r\n" +
+	"a=recvonly\r\n"
+
+func TestParse(t *testing.T) {
+	d, err := sdp.Parse(testOffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Origin.SessionId != 2890844526 || d.Origin.Version != 2890844527 || d.Connection != "10.0.0.1" {
+		t.Fatalf("invalid session level fields: %+v", d)
+	}
+	if len(d.Media) != 2 {
+		t.Fatalf("expect 2 media but got %v", len(d.Media))
+	}
+	audio, video := d.Media[0], d.Media[1]
+	if audio.Port != 49170 || !reflect.DeepEqual(audio.Formats, []string{"96", "8", "101"}) {
+		t.Fatalf("invalid audio media: %+v", audio)
+	}
+	if rm, ok := audio.RtpMap(96); !ok || rm != (sdp.RtpMap{96, "AMR-WB", 16000, 1}) {
+		t.Fatalf("invalid rtpmap: %+v", rm)
+	}
+	if rm, ok := audio.RtpMap(8); !ok || rm.Encoding != "PCMA" || rm.ClockRate != 8000 {
+		t.Fatalf("static payload number should have rtpmap: %+v", rm)
+	}
+	if _, ok := audio.RtpMap(102); ok {
+		t.Fatal("unknown payload number should not have rtpmap")
+	}
+	if audio.Fmtp(101) != "0-16" || audio.Fmtp(8) != "" {
+		t.Fatal("invalid fmtp")
+	}
+	if d.ConnectionOf(audio) != "10.0.0.1" || d.ConnectionOf(video) != "::1" {
+		t.Fatal("invalid connection of media")
+	}
+	if d.DirectionOf(audio) != sdp.DirectionSendOnly || d.DirectionOf(video) != sdp.DirectionRecvOnly {
+		t.Fatal("invalid direction of media")
+	}
+
+	d2, err := sdp.Parse(d.Marshal())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(d, d2) {
+		t.Fatalf("marshalled description changed after parsing: %+v", d2)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"v=1\r\nm=audio 1000 RTP/AVP 0\r\n",
+		"v=0\r\nm=audio port RTP/AVP 0\r\n",
+		"v=0\r\nc=IN IP4\r\nm=audio 1000 RTP/AVP 0\r\n",
+		"v=0\r\ninvalid\r\nm=audio 1000 RTP/AVP 0\r\n",
+	} {
+		if _, err := sdp.Parse(s); err == nil {
+			t.Fatalf("sdp should be invalid: %q", s)
+		}
+	}
+}
+
+func TestFmtp(t *testing.T) {
+	params := sdp.ParseFmtp(" Mode-Set=0,2;octet-align=1; ;robust-sorting")
+	expected := []sdp.FmtpParam{{"mode-set", "0,2"}, {"octet-align", "1"}, {"robust-sorting", ""}}
+	if !reflect.DeepEqual(params, expected) {
+		t.Fatalf("invalid fmtp params: %+v", params)
+	}
+	if s := sdp.FormatFmtp(params); s != "mode-set=0,2; octet-align=1; robust-sorting" {
+		t.Fatalf("invalid formatted fmtp: %v", s)
+	}
+}
+
+func TestAnswerDirection(t *testing.T) {
+	for offered, answered := range map[string]string{
+		sdp.DirectionSendRecv: sdp.DirectionSendRecv,
+		sdp.DirectionSendOnly: sdp.DirectionRecvOnly,
+		sdp.DirectionRecvOnly: sdp.DirectionSendOnly,
+		sdp.DirectionInactive: sdp.DirectionInactive,
+	} {
+		if d := sdp.AnswerDirection(offered); d != answered {
+			t.Fatalf("offered %v should be answered with %v, but got %v", offered, answered, d)
+		}
+	}
+}
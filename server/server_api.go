@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"fmt"
 	"github.com/appcrash/media/server/channel"
 	"github.com/appcrash/media/server/comp"
@@ -10,6 +11,8 @@ import (
 	"google.golang.org/grpc"
 	"math/rand"
 	"net"
+	"os"
+	"os/signal"
 	"sync"
 	"time"
 )
@@ -28,36 +31,115 @@ func init() {
 
 type MediaServer struct {
 	rpc.UnimplementedMediaApiServer
-	rtpServerIpString string
-	rtpServerIpAddr   *net.IPAddr
-	portPool          *PortPool
-	sessionListener   []SessionListener
+	rtpInterfaces    map[string]*rtpInterface
+	defaultInterface *rtpInterface
+	sessionListener  []SessionListener
+	lifecycle        *lifecycleHub
+	cdr              *cdrWriter // nil if cdr is disabled
+	watchdogPolicy   WatchdogPolicy
+	auditPeriod      time.Duration
+	drainState       *drainState
+	drainTimeout     time.Duration
+	rtpOverflow      time.Duration // how long received rtp packet waits for consumer
+	admission        *admission
+	failover         *failover
+	gateway          *gateway // nil if http gateway is disabled
+	peers            *peerLinks
 
-	graph *event.Graph
+	graph   *event.Graph
+	reactor *reactor           // nil unless io model is reactor
+	auditor *watchdogScheduler // audits all watchdogs in one goroutine if not nil
 
 	sessionMutex sync.Mutex
 	sessionMap   map[SessionIdType]*MediaSession
 
 	simpleExecutorMap map[string]CommandExecute
 	streamExecutorMap map[string]CommandExecute
+	commandTraits     []CommandTrait // all registered commands in order
 }
 
 type Config struct {
-	RtpIp               string
-	StartPort, EndPort  uint16
+	// RtpIp with StartPort/EndPort declares the interface named DefaultRtpInterface, more interfaces can be declared
+	// in RtpInterfaces. the first declared interface is used if session doesn't select one.
+	RtpIp              string
+	StartPort, EndPort uint16
+	ReservedPorts      []PortRange // reserved ports of the default interface
+	RtpInterfaces      []RtpInterface
+	// PortQuarantine is how long a released port waits before reuse, zero or negative disables it.
+	// ValidatePortBind checks rtp/rtcp ports are bindable before handing them out.
+	PortQuarantine      time.Duration
+	ValidatePortBind    bool
 	ExecutorList        []CommandExecute
 	SessionListenerList []SessionListener
 
 	GrpcIp           string
 	GrpcPort         uint16
 	GrpcRegisterMore RegisterMore
+	// TLS enables tls of grpc if not nil. Authenticator identifies caller of every rpc if not nil, then sessions can
+	// only be operated by the instance created them or an admin identity
+	TLS           *TLSConfig
+	Authenticator Authenticator
+	// HttpPort enables http/json gateway of session operations if not zero, see gateway.go for the routes. it shares
+	// TLS, Authenticator and admission with grpc.
+	HttpIp   string
+	HttpPort uint16
+
+	// IOModel defaults to IOModelGoroutine, ReactorWorkers is only used by IOModelReactor, the number of cpu is
+	// used if not positive
+	IOModel        IOModel
+	ReactorWorkers int
+	// RtpTransport defaults to RtpTransportUDP, RtpTransportBatch falls back to it if kernel doesn't support
+	// recvmmsg/sendmmsg or io model is not reactor. RtpGSO enables udp segmentation offload for batched sends.
+	RtpTransport RtpTransport
+	RtpGSO       bool
+	// RtpOverflowTimeout is how long receive loop waits for rtp packet consumer that doesn't catch up before dropping
+	// the packet, it is dropped at once if zero. waiting without limit is not supported as socket must be read in
+	// time, neither is dropping the oldest packets as consumer only exposes a send-only channel. in reactor io model
+	// the wait also holds up other sessions of the worker, so keep it short.
+	RtpOverflowTimeout time.Duration
+
+	// WatchdogPolicy overrides DefaultWatchdogPolicy for all sessions, CreateParam can override it again for a
+	// session. WatchdogAuditPeriod defaults to SessionAuditPeriod if not positive.
+	WatchdogPolicy      WatchdogPolicy
+	WatchdogAuditPeriod time.Duration
+
+	// CdrFile is the path that final report of every stopped session is appended to as a csv line, disabled if empty
+	CdrFile string
+
+	// DrainSignals make server enter drain mode like Drain rpc does, i.e. syscall.SIGTERM. DrainTimeout defaults to
+	// DefaultDrainTimeout if not positive.
+	DrainSignals []os.Signal
+	DrainTimeout time.Duration
+
+	// Admission limits sessions that can be created, nothing is limited by default
+	Admission AdmissionPolicy
+
+	// FailoverGrace is how long an instance registered with a failover group can be lost before its sessions are
+	// adopted by another instance of the group, DefaultFailoverGrace if zero and negative disables it. sessions can
+	// always be adopted by AdoptSessions rpc, by admin or instances of the group if authentication is enabled.
+	FailoverGrace time.Duration
+
+	// CapacityReportPeriod makes server broadcast CAPACITY_REPORT to all instances periodically if positive,
+	// instances can always query it by CAPACITY_QUERY or GetCapacity rpc
+	CapacityReportPeriod time.Duration
+
+	// PeerAddresses are grpc addresses of peer media servers, nodes can stream to nodes of sessions hosted by them as
+	// if they were local, i.e. [mixer@room1] where room1 is a session of a peer. a link is bidirectional whichever
+	// side dials. PeerDialOptions default to insecure transport if empty, peers dialing in must be admin identities.
+	PeerAddresses   []string
+	PeerDialOptions []grpc.DialOption
+
+	// GraphShards is the number of event graph shards, i.e. loops setting up nodes and links of sessions in parallel,
+	// a single shard is used if zero and one per available cpu if negative
+	GraphShards int
 }
 
 type RegisterMore func(s grpc.ServiceRegistrar)
 type StartServerFunc func()
 type StopServerFunc func()
 
-// SessionListener methods are called concurrently, so it must be goroutine safe
+// SessionListener methods are called concurrently, so it must be goroutine safe. in OnSessionStopped, the reason and
+// final report are available by GetStopReason and GetReport of the session.
 type SessionListener interface {
 	OnSessionCreated(s *MediaSession)
 	OnSessionUpdated(s *MediaSession)
@@ -75,48 +157,156 @@ func (b *BaseSessionListener) OnSessionStopped(s *MediaSession) {}
 
 func NewServer(c *Config) (start StartServerFunc, stop StopServerFunc, err error) {
 	var lis net.Listener
-	var ip *net.IPAddr
+	var itfs []*rtpInterface
+	var r *reactor
+	var cdr *cdrWriter
+	var opts []grpc.ServerOption
 
+	if itfs, err = rtpInterfacesOf(c); err != nil {
+		logger.Errorf("invalid rtp interface config: %v", err)
+		return
+	}
+	if c.IOModel == IOModelReactor {
+		if r, err = newReactor(c.ReactorWorkers, c.RtpTransport, c.RtpGSO); err != nil {
+			logger.Errorf("failed to start reactor: %v", err)
+			return
+		}
+	}
+	if c.IOModel != IOModelReactor && c.RtpTransport == RtpTransportBatch {
+		logger.Warnf("batch transport requires reactor io model, fall back to rtp.TransportUDP")
+	}
+	defer func() {
+		if err != nil && r != nil {
+			r.close()
+		}
+		if err != nil && cdr != nil {
+			cdr.close()
+		}
+	}()
+	if c.TLS != nil {
+		var opt grpc.ServerOption
+		if opt, err = c.TLS.serverOption(); err != nil {
+			logger.Errorf("invalid tls config: %v", err)
+			return
+		}
+		opts = append(opts, opt)
+	}
+	if c.Authenticator != nil {
+		opts = append(opts, authInterceptors(c.Authenticator)...)
+	}
+	if c.CdrFile != "" {
+		if cdr, err = newCdrWriter(c.CdrFile); err != nil {
+			logger.Errorf("failed to open cdr file: %v", err)
+			return
+		}
+	}
 	if lis, err = net.Listen("tcp", fmt.Sprintf("%s:%d", c.GrpcIp, c.GrpcPort)); err != nil {
 		logger.Errorf("failed to listen to port(%v) for grpc", c.GrpcPort)
 		return
 	}
-	rtpIp, rtpStartPort, rtpEndPort := c.RtpIp, c.StartPort, c.EndPort
 	server := MediaServer{
-		rtpServerIpString: rtpIp,
-		portPool:          NewPortPool(),
-		sessionListener:   c.SessionListenerList,
-		sessionMap:        make(map[SessionIdType]*MediaSession),
+		sessionListener: c.SessionListenerList,
+		lifecycle:       newLifecycleHub(),
+		cdr:             cdr,
+		watchdogPolicy:  DefaultWatchdogPolicy.override(c.WatchdogPolicy),
+		auditPeriod:     SessionAuditPeriod,
+		drainState:      newDrainState(),
+		drainTimeout:    DefaultDrainTimeout,
+		rtpOverflow:     c.RtpOverflowTimeout,
+		sessionMap:      make(map[SessionIdType]*MediaSession),
 
 		// read-only maps once executors registered
 		simpleExecutorMap: make(map[string]CommandExecute),
 		streamExecutorMap: make(map[string]CommandExecute),
-
-		graph: event.NewEventGraph(),
 	}
-	if ip, err = net.ResolveIPAddr("ip", rtpIp); err != nil {
-		return
+	if c.DrainTimeout > 0 {
+		server.drainTimeout = c.DrainTimeout
+	}
+	if c.WatchdogAuditPeriod > 0 {
+		server.auditPeriod = c.WatchdogAuditPeriod
+	}
+	if c.HttpPort != 0 {
+		if server.gateway, err = newGateway(&server, c); err != nil {
+			logger.Errorf("failed to start http gateway on port(%v): %v", c.HttpPort, err)
+			lis.Close()
+			return
+		}
+	}
+	// nothing fails from now on, so states running goroutines are made here
+	server.admission = newAdmission(c.Admission)
+	server.failover = newFailover(c.FailoverGrace)
+	if c.GraphShards == 0 {
+		server.graph = event.NewEventGraph()
+	} else {
+		server.graph = event.NewShardedEventGraph(c.GraphShards)
+	}
+	if r != nil {
+		server.reactor = r
+		server.auditor = newWatchdogScheduler(server.auditPeriod)
 	}
-	server.init(ip, rtpStartPort, rtpEndPort)
+	server.peers = newPeerLinks(server.graph)
+	server.init(itfs)
 	server.registerCommandExecutor(&BuiltinCommandHandler{}) // built-in script executor
 	for _, e := range c.ExecutorList {
 		server.registerCommandExecutor(e)
 	}
 
-	var opts []grpc.ServerOption
 	grpcServer := grpc.NewServer(opts...)
 	rpc.RegisterMediaApiServer(grpcServer, &server)
 	if c.GrpcRegisterMore != nil {
 		c.GrpcRegisterMore(grpcServer)
 	}
 
+	var signalC chan os.Signal
+	if len(c.DrainSignals) > 0 {
+		signalC = make(chan os.Signal, 1)
+		signal.Notify(signalC, c.DrainSignals...)
+		go func() {
+			if sig, ok := <-signalC; ok {
+				logger.Infof("received signal %v, drain media server", sig)
+				server.drain(0)
+			}
+		}()
+	}
+
+	capacityCtx, capacityCancel := context.WithCancel(context.Background())
+	if c.CapacityReportPeriod > 0 {
+		go server.capacityLoop(capacityCtx, c.CapacityReportPeriod)
+	}
+
+	for _, address := range c.PeerAddresses {
+		go server.peers.dial(address, c.PeerDialOptions)
+	}
+
 	start = func() {
 		logger.Infof("starting media server")
+		if server.gateway != nil {
+			go server.gateway.serve()
+		}
 		grpcServer.Serve(lis)
 	}
 	stop = func() {
 		logger.Infof("try to gracefully stop media server")
+		if signalC != nil {
+			signal.Stop(signalC)
+			close(signalC)
+		}
+		server.drainState.cancel()
+		server.admission.close()
+		server.failover.close()
+		capacityCancel()
+		server.peers.close()
+		if server.gateway != nil {
+			server.gateway.stop()
+		}
 		grpcServer.GracefulStop()
+		if server.reactor != nil {
+			server.reactor.close()
+			server.auditor.stop()
+		}
+		if server.cdr != nil {
+			server.cdr.close()
+		}
 		logger.Infof("media server has stopped")
 	}
 	return
@@ -9,9 +9,12 @@ import (
 	"net"
 )
 
-func (srv *MediaServer) init(ip *net.IPAddr, portStart, portEnd uint16) {
-	srv.rtpServerIpAddr = ip
-	srv.portPool.Init(portStart, portEnd)
+func (srv *MediaServer) init(itfs []*rtpInterface) {
+	srv.rtpInterfaces = make(map[string]*rtpInterface)
+	for _, itf := range itfs {
+		srv.rtpInterfaces[itf.name] = itf
+	}
+	srv.defaultInterface = itfs[0]
 	channel.GetSystemChannel().AddListener(srv)
 }
 
@@ -29,14 +32,25 @@ func (srv *MediaServer) removeFromSessionMap(session *MediaSession) {
 	prom.CreatedSession.Dec()
 }
 
-func (srv *MediaServer) getNextAvailableRtpPort() uint16 {
-	return srv.portPool.Get()
+// getSessions returns a snapshot of all sessions in map
+func (srv *MediaServer) getSessions() (sessions []*MediaSession) {
+	srv.sessionMutex.Lock()
+	defer srv.sessionMutex.Unlock()
+	for _, session := range srv.sessionMap {
+		sessions = append(sessions, session)
+	}
+	return
 }
 
-func (srv *MediaServer) reclaimRtpPort(port uint16) {
-	if port != 0 {
-		srv.portPool.Put(port)
+// getRtpInterface finds interface by name, empty name means the default one
+func (srv *MediaServer) getRtpInterface(name string) (*rtpInterface, error) {
+	if name == "" {
+		return srv.defaultInterface, nil
+	}
+	if itf, ok := srv.rtpInterfaces[name]; ok {
+		return itf, nil
 	}
+	return nil, fmt.Errorf("unknown rtp interface: %v", name)
 }
 
 func (srv *MediaServer) invokeSessionListener(session *MediaSession, status int) {
@@ -58,6 +72,7 @@ func (srv *MediaServer) invokeSessionListener(session *MediaSession, status int)
 			listener.OnSessionUpdated(session)
 		}
 	}
+	srv.lifecycle.publish(session, status)
 }
 
 func (srv *MediaServer) createSession(param *rpc.CreateParam) (session *MediaSession, err error) {
@@ -68,9 +83,14 @@ func (srv *MediaServer) createSession(param *rpc.CreateParam) (session *MediaSes
 	}()
 
 	logger.Infof("create session param: %v", param)
+	if err = srv.admission.admit(param.GetInstanceId()); err != nil {
+		return
+	}
 	if session, err = newSession(srv, param); err != nil {
+		srv.admission.release(param.GetInstanceId())
 		return
 	}
+	session.admitted = true
 
 	// connect source/sink into event graph of this session
 	// then listen on udp messages
@@ -84,6 +104,12 @@ func (srv *MediaServer) createSession(param *rpc.CreateParam) (session *MediaSes
 }
 
 func (srv *MediaServer) updateSession(param *rpc.UpdateParam) (err error) {
+	return srv.updateSessionWith(param, nil)
+}
+
+// updateSessionWith updates session like updateSession, apply is invoked if not nil to change more fields before
+// listeners are notified
+func (srv *MediaServer) updateSessionWith(param *rpc.UpdateParam, apply func(session *MediaSession)) (err error) {
 	var sessionId SessionIdType
 	if sessionId, err = SessionIdFromString(param.GetSessionId()); err != nil {
 		err = errors.New("invalid session id")
@@ -123,6 +149,9 @@ func (srv *MediaServer) updateSession(param *rpc.UpdateParam) (err error) {
 			}
 
 		}
+		if apply != nil {
+			apply(session)
+		}
 
 		srv.invokeSessionListener(session, sessionStatusUpdated)
 	} else {
@@ -148,6 +177,8 @@ func (srv *MediaServer) startSession(param *rpc.StartParam) (err error) {
 	}
 	if err == nil {
 		srv.invokeSessionListener(session, sessionStatusStarted)
+	} else if exist && session.GetStatus() == sessionStatusStopped {
+		session.onStopped()
 	}
 	return
 }
@@ -163,8 +194,8 @@ func (srv *MediaServer) stopSession(param *rpc.StopParam) (err error) {
 	session, exist := srv.sessionMap[sessionId]
 	srv.sessionMutex.Unlock()
 	if exist {
+		// listeners are notified by session itself, as it can also be stopped by peer or watchdog
 		session.Stop()
-		srv.invokeSessionListener(session, sessionStatusStopped)
 	} else {
 		err = errors.New("session not exist")
 	}
@@ -195,6 +226,7 @@ func (srv *MediaServer) registerCommandExecutor(e CommandExecute) (err error) {
 		} else {
 			logger.Infof("register execute with command %v\n", trait.CmdName)
 			cm[trait.CmdName] = e
+			srv.commandTraits = append(srv.commandTraits, trait)
 		}
 	}
 	return
@@ -214,12 +246,16 @@ func (srv *MediaServer) getExecutorFor(cmd string) (needNotify bool, ce CommandE
 	return
 }
 
-// OnChannelEvent just forwards system event to session
+// OnChannelEvent answers capacity query and forwards other system events to session
 func (srv *MediaServer) OnChannelEvent(e *rpc.SystemEvent) {
 	var exist bool
 	var session *MediaSession
 	var sessionId SessionIdType
 	var err error
+	if e.Cmd == rpc.SystemCommand_CAPACITY_QUERY {
+		srv.notifyCapacity(e.InstanceId)
+		return
+	}
 	if sessionId, err = SessionIdFromString(e.SessionId); err != nil {
 		logger.Errorf("OnChannelEvent got invalid session id")
 		return
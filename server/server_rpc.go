@@ -19,9 +19,18 @@ func (srv *MediaServer) GetVersion(_ context.Context, _ *rpc.Empty) (*rpc.Versio
 	return &rpc.VersionNumber{Ver: rpc.Version_DEFAULT}, nil
 }
 
-func (srv *MediaServer) PrepareSession(_ context.Context, param *rpc.CreateParam) (*rpc.Session, error) {
+func (srv *MediaServer) PrepareSession(ctx context.Context, param *rpc.CreateParam) (*rpc.Session, error) {
 	var session *MediaSession
 	var err error
+	if id := identityFrom(ctx); id != nil && !id.Admin && param.GetInstanceId() == "" {
+		param.InstanceId = id.InstanceId
+	}
+	if err = authorizeInstance(ctx, param.GetInstanceId()); err != nil {
+		return nil, err
+	}
+	if srv.drainState.isDraining() {
+		return nil, errServerDraining
+	}
 	if session, err = srv.createSession(param); err != nil {
 		logger.Errorf("fail to prepare session with error:%v", err)
 		return nil, err
@@ -34,22 +43,32 @@ func (srv *MediaServer) PrepareSession(_ context.Context, param *rpc.CreateParam
 	rpcSession.PeerRtpPort = param.GetPeerPort()
 	rpcSession.LocalRtpPort = uint32(session.localPort)
 	rpcSession.LocalIp = session.localIp.String()
+	rpcSession.InterfaceName = session.rtpItf.name
 
 	return &rpcSession, nil
 }
 
-func (srv *MediaServer) UpdateSession(_ context.Context, param *rpc.UpdateParam) (*rpc.Status, error) {
+func (srv *MediaServer) UpdateSession(ctx context.Context, param *rpc.UpdateParam) (*rpc.Status, error) {
+	if err := srv.authorizeSession(ctx, param.GetSessionId()); err != nil {
+		return nil, err
+	}
 	// only remote (ip, port) can be updated
 	err := srv.updateSession(param)
 	return &rpc.Status{Status: "ok"}, err
 }
 
-func (srv *MediaServer) StartSession(_ context.Context, param *rpc.StartParam) (*rpc.Status, error) {
+func (srv *MediaServer) StartSession(ctx context.Context, param *rpc.StartParam) (*rpc.Status, error) {
+	if err := srv.authorizeSession(ctx, param.GetSessionId()); err != nil {
+		return nil, err
+	}
 	err := srv.startSession(param)
 	return &rpc.Status{Status: "ok"}, err
 }
 
-func (srv *MediaServer) StopSession(_ context.Context, param *rpc.StopParam) (*rpc.Status, error) {
+func (srv *MediaServer) StopSession(ctx context.Context, param *rpc.StopParam) (*rpc.Status, error) {
+	if err := srv.authorizeSession(ctx, param.GetSessionId()); err != nil {
+		return nil, err
+	}
 	if err := srv.stopSession(param); err != nil {
 		return nil, err
 	} else {
@@ -57,9 +76,12 @@ func (srv *MediaServer) StopSession(_ context.Context, param *rpc.StopParam) (*r
 	}
 }
 
-func (srv *MediaServer) ExecuteAction(_ context.Context, action *rpc.Action) (*rpc.ActionResult, error) {
+func (srv *MediaServer) ExecuteAction(ctx context.Context, action *rpc.Action) (*rpc.ActionResult, error) {
 	var sessionId SessionIdType
 	var err error
+	if err = srv.authorizeSession(ctx, action.SessionId); err != nil {
+		return nil, err
+	}
 	if sessionId, err = SessionIdFromString(action.SessionId); err != nil {
 		err = errors.New("invalid session id")
 		return nil, err
@@ -96,6 +118,9 @@ func (srv *MediaServer) ExecuteAction(_ context.Context, action *rpc.Action) (*r
 func (srv *MediaServer) ExecuteActionWithNotify(action *rpc.Action, stream rpc.MediaApi_ExecuteActionWithNotifyServer) error {
 	var sessionId SessionIdType
 	var err error
+	if err = srv.authorizeSession(stream.Context(), action.SessionId); err != nil {
+		return err
+	}
 	if sessionId, err = SessionIdFromString(action.SessionId); err != nil {
 		err = errors.New("invalid session id")
 		return err
@@ -140,6 +165,8 @@ func (srv *MediaServer) ExecuteActionWithNotify(action *rpc.Action, stream rpc.M
 						logger.Errorf("send action event of stream(%v) with event %v error", session, event.String())
 						break outLoop
 					}
+				case <-stream.Context().Done():
+					break outLoop
 				}
 			}
 		}
@@ -153,6 +180,7 @@ func (srv *MediaServer) ExecuteActionWithNotify(action *rpc.Action, stream rpc.M
 func (srv *MediaServer) ExecuteActionWithPush(stream rpc.MediaApi_ExecuteActionWithPushServer) error {
 	var sessionId SessionIdType
 	var dataIn chan *rpc.PushData
+	var cmd string
 
 	// receive the first data to retrieve the session id
 	if data, err := stream.Recv(); err != nil {
@@ -162,6 +190,9 @@ func (srv *MediaServer) ExecuteActionWithPush(stream rpc.MediaApi_ExecuteActionW
 		if sidStr == "" {
 			return errors.New("push action with empty session id")
 		}
+		if err = srv.authorizeSession(stream.Context(), sidStr); err != nil {
+			return err
+		}
 		if sessionId, err = SessionIdFromString(sidStr); err != nil {
 			return errors.New("push action with invalid sessin id")
 		}
@@ -171,7 +202,8 @@ func (srv *MediaServer) ExecuteActionWithPush(stream rpc.MediaApi_ExecuteActionW
 		if !ok {
 			return fmt.Errorf("push action with session(%v) that is not exist", sessionId)
 		}
-		exec := srv.streamExecutorMap[data.Cmd]
+		cmd = data.Cmd
+		exec := srv.streamExecutorMap[cmd]
 		if exec == nil {
 			logger.Errorf("no push executor cmd: %v registered", data.Cmd)
 			return fmt.Errorf("push cmd %v not found", data.Cmd)
@@ -183,7 +215,7 @@ func (srv *MediaServer) ExecuteActionWithPush(stream rpc.MediaApi_ExecuteActionW
 	}
 
 	defer func() {
-		prom.SessionAction.With(prometheus.Labels{"type": "push_stream"}).Inc()
+		prom.SessionAction.With(prometheus.Labels{"cmd": cmd, "type": "push_stream"}).Inc()
 		// let push loop stop
 		if dataIn != nil {
 			close(dataIn)
@@ -208,12 +240,99 @@ func (srv *MediaServer) ExecuteActionWithPush(stream rpc.MediaApi_ExecuteActionW
 	}
 }
 
+func (srv *MediaServer) ListSessions(ctx context.Context, param *rpc.ListParam) (*rpc.SessionList, error) {
+	if id := identityFrom(ctx); id != nil && !id.Admin && param.GetInstanceId() == "" {
+		param.InstanceId = id.InstanceId
+	}
+	if err := authorizeInstance(ctx, param.GetInstanceId()); err != nil {
+		return nil, err
+	}
+	list := &rpc.SessionList{}
+	now := time.Now()
+	for _, session := range srv.getSessions() {
+		if session.matchListParam(param, now) {
+			list.Sessions = append(list.Sessions, session.info())
+		}
+	}
+	return list, nil
+}
+
+func (srv *MediaServer) DescribeSession(ctx context.Context, param *rpc.DescribeParam) (*rpc.SessionDescription, error) {
+	if err := srv.authorizeSession(ctx, param.GetSessionId()); err != nil {
+		return nil, err
+	}
+	sessionId, err := SessionIdFromString(param.GetSessionId())
+	if err != nil {
+		return nil, errors.New("invalid session id")
+	}
+	srv.sessionMutex.Lock()
+	session, ok := srv.sessionMap[sessionId]
+	srv.sessionMutex.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("session(%v) not exist", sessionId)
+	}
+	return session.describe(), nil
+}
+
+// Drain makes server reject new sessions, then force-stops remaining sessions after timeout
+func (srv *MediaServer) Drain(ctx context.Context, param *rpc.DrainParam) (*rpc.DrainStatus, error) {
+	if err := authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return srv.drain(time.Duration(param.GetTimeout()) * time.Second), nil
+}
+
+// AdoptSessions transfers sessions of a lost instance to the adopter, which receives OWNERSHIP_TRANSFER of each one.
+// sessions can only be adopted by admin or instances of the same failover group as the lost one
+func (srv *MediaServer) AdoptSessions(ctx context.Context, param *rpc.AdoptParam) (*rpc.AdoptResult, error) {
+	id := identityFrom(ctx)
+	if id != nil && !id.Admin && param.GetInstanceId() == "" {
+		param.InstanceId = id.InstanceId
+	}
+	if err := authorizeInstance(ctx, param.GetInstanceId()); err != nil {
+		return nil, err
+	}
+	adopted, err := srv.adopt(param, id != nil && !id.Admin)
+	if err != nil {
+		return nil, err
+	}
+	return &rpc.AdoptResult{SessionId: adopted}, nil
+}
+
+// WatchSessions streams lifecycle events of sessions created by the instance until client cancels
+func (srv *MediaServer) WatchSessions(param *rpc.WatchParam, stream rpc.MediaApi_WatchSessionsServer) error {
+	instanceId := param.GetInstanceId()
+	if instanceId == "" {
+		return errors.New("watch sessions with empty instance id")
+	}
+	if err := authorizeInstance(stream.Context(), instanceId); err != nil {
+		return err
+	}
+	w := srv.lifecycle.subscribe(instanceId)
+	defer srv.lifecycle.unsubscribe(w)
+	logger.Infof("instance:%v starts watching sessions", instanceId)
+	for {
+		select {
+		case evt := <-w.c:
+			if err := stream.Send(evt); err != nil {
+				logger.Errorf("instance:%v watch sessions, send event error: %v", instanceId, err)
+				return err
+			}
+		case <-stream.Context().Done():
+			logger.Infof("instance:%v stops watching sessions", instanceId)
+			return nil
+		}
+	}
+}
+
 // SystemChannel is long-keepalive connection to ease bidirectional system-level message exchange
 func (srv *MediaServer) SystemChannel(stream rpc.MediaApi_SystemChannelServer) error {
 	wg := &sync.WaitGroup{}
 	var instanceId string
+	var lastSeq uint64
+	var group string
 	var errorLogged bool
-	var fromC, toC chan *rpc.SystemEvent
+	var toC chan *rpc.SystemEvent
 
 	// only work after REGISTER is seen
 	for {
@@ -223,12 +342,15 @@ func (srv *MediaServer) SystemChannel(stream rpc.MediaApi_SystemChannelServer) e
 			return err
 		}
 		if in.Cmd == rpc.SystemCommand_REGISTER {
-			instanceId = in.InstanceId
+			instanceId, lastSeq, group = in.InstanceId, in.Seq, in.Group
 			if instanceId == "" {
 				err = fmt.Errorf("system channel got null instance id when registering")
 				logger.Error(err)
 				return err
 			}
+			if err = authorizeInstance(stream.Context(), instanceId); err != nil {
+				return err
+			}
 			break
 		} else {
 			if !errorLogged {
@@ -241,38 +363,30 @@ func (srv *MediaServer) SystemChannel(stream rpc.MediaApi_SystemChannelServer) e
 	logger.Infof("instance (%v) enters system channel rpc", instanceId)
 	// the client has registered itself
 	sc := channel.GetSystemChannel()
-	if is, err := sc.RegisterInstance(instanceId); err != nil {
+	is, err := sc.ResumeInstance(instanceId, lastSeq)
+	if err != nil {
 		return err
-	} else {
-		fromC, toC = is.FromInstanceC, is.ToInstanceC
 	}
+	toC = is.ToInstanceC
+	srv.failover.join(instanceId, group)
 	logger.Infof("instance:%v has registered system channel", instanceId)
-	wg.Add(2)
+	wg.Add(1)
 
-	// the receive&send goroutines are independent, one exit wouldn't end the other one
-	// only network exception or instance state close would end them
+	// the receive loop ends when rpc ends or instance state closed, while send loop ends rpc when client goes away or
+	// instance state closed(i.e. keep-alive timeout or re-registered by another rpc)
 	now := time.Now().Format("2006-01-02 15:04:05")
 	go func() {
-		defer func() {
-			wg.Done()
-			logger.Infof("instance:%v at %v system channel rpc, exit recv loop", instanceId, now)
-		}()
-
+		defer logger.Infof("instance:%v at %v system channel rpc, exit recv loop", instanceId, now)
 		for {
 			in, err := stream.Recv()
 			if err != nil {
 				break
 			}
-			_, ok := <-fromC
-			if !ok {
-				// Channel is closed
+			in.InstanceId = instanceId // events are always from the registered instance
+			if !sc.FromInstance(is, in) {
+				// instance state is closed
 				break
 			}
-			select {
-			case fromC <- in:
-			default:
-			}
-
 		}
 	}()
 
@@ -290,8 +404,12 @@ func (srv *MediaServer) SystemChannel(stream rpc.MediaApi_SystemChannelServer) e
 				}
 				if err := stream.Send(msg); err != nil {
 					logger.Errorf("instance:%v system channel rpc, send message error: %v", instanceId, err)
+					sc.UnregisterInstance(is)
 					return
 				}
+			case <-stream.Context().Done():
+				sc.UnregisterInstance(is)
+				return
 			}
 		}
 	}()
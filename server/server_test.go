@@ -15,6 +15,8 @@ import (
 	"net"
 	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -185,6 +187,65 @@ func startServer() {
 	}
 }
 
+// nextRtpPort is where rtp ports of the next test server start, away from the ones used by startServer
+var nextRtpPort uint32 = 34000
+
+// freePort asks the os for a tcp port nobody listens on
+func freePort(t *testing.T) uint16 {
+	lis, err := net.Listen("tcp", fmt.Sprintf("%v:0", grpcIp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close()
+	return uint16(lis.Addr().(*net.TCPAddr).Port)
+}
+
+// rtpPorts allocates n ports no other test server uses
+func rtpPorts(n uint16) (start, end uint16) {
+	end = uint16(atomic.AddUint32(&nextRtpPort, uint32(n)))
+	return end - n, end
+}
+
+type testServer struct {
+	port   uint16
+	conn   *grpc.ClientConn
+	client rpc.MediaApiClient
+	stop   func() // stop the server before test ends, only the first call takes effect
+}
+
+// startTestServer starts a server listening on a free grpc port with its own rtp ports unless config sets them,
+// then dials it. both are closed when test ends.
+func startTestServer(t *testing.T, config *server.Config, opts ...grpc.DialOption) *testServer {
+	t.Helper()
+	if config.GrpcIp == "" {
+		config.GrpcIp = grpcIp
+	}
+	if config.GrpcPort == 0 {
+		config.GrpcPort = freePort(t)
+	}
+	if config.RtpIp != "" && config.StartPort == 0 {
+		config.StartPort, config.EndPort = rtpPorts(100)
+	}
+	start, stop, err := server.NewServer(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go start()
+	ts := &testServer{port: config.GrpcPort}
+	var once sync.Once
+	ts.stop = func() { once.Do(stop) }
+	t.Cleanup(ts.stop)
+	if len(opts) == 0 {
+		opts = []grpc.DialOption{grpc.WithInsecure()}
+	}
+	if ts.conn, err = grpc.Dial(fmt.Sprintf("%v:%v", config.GrpcIp, config.GrpcPort), opts...); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ts.conn.Close() })
+	ts.client = rpc.NewMediaApiClient(ts.conn)
+	return ts
+}
+
 func initComposer() {
 	comp.InitBuiltIn()
 	comp.RegisterNodeTrait(comp.NT[echo]("echo", func() comp.SessionAware {
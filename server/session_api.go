@@ -18,16 +18,24 @@ const (
 	sessionStatusUpdated
 	sessionStatusStarted
 	sessionStatusStopped
+	sessionStatusAdopted // only for lifecycle event, not a status of session
 )
 
 type MediaSession struct {
+	stats                 rtpStats // keep it first for 64-bit alignment of atomic operations
 	server                *MediaServer
 	sessionId             SessionIdType
 	localIp, remoteIp     *net.IPAddr
 	localPort, remotePort uint16
+	rtpItf                *rtpInterface // where local ip and port come from
 	rtpSession            *rtp.Session
-	rtpSessionLocalId     uint32 //rtpSession id which update rtp params
-	instanceId            string // which instance created this session
+	rtpSessionLocalId     uint32         //rtpSession id which update rtp params
+	rtpStream             *reactorStream // used instead of rtpSession in reactor io model
+	reactorWorker         *reactorWorker
+	ownerMutex            sync.RWMutex
+	instanceId            string // which instance owns this session, the creator unless adopted by another one
+	createTime, startTime time.Time
+	admitted              bool // holds a slot of admission until finalized
 
 	avPayloadNumber uint8
 	avPayloadCodec  rpc.CodecType
@@ -39,9 +47,14 @@ type MediaSession struct {
 
 	mutex sync.Mutex
 
-	status     int
-	cancelFunc context.CancelFunc
-	doneC      chan string // notify this channel when loop is done
+	status       int
+	stopReason   rpc.StopReason
+	report       *rpc.SessionReport // made once session stopped
+	sdpVersion   uint64             // version of the last sdp answer
+	sdpDirection string             // direction of the last sdp answer, empty if session is not made by offer
+	cancelFunc   context.CancelFunc
+	doneC        chan string // notify this channel when loop is done
+	nbLoop       int         // number of loops that notify doneC
 
 	pullC        <-chan *utils.RtpPacketList
 	handleC      chan<- *utils.RtpPacketList
@@ -54,6 +67,18 @@ func (s *MediaSession) GetSessionId() SessionIdType {
 	return s.sessionId
 }
 
+// GetInstanceId returns the instance owning this session
+func (s *MediaSession) GetInstanceId() string {
+	s.ownerMutex.RLock()
+	defer s.ownerMutex.RUnlock()
+	return s.instanceId
+}
+
+// GetInterfaceName returns name of the rtp interface this session binds to
+func (s *MediaSession) GetInterfaceName() string {
+	return s.rtpItf.name
+}
+
 func (s *MediaSession) GetStatus() int {
 	return s.status
 }
@@ -100,9 +125,15 @@ func (s *MediaSession) Start() (err error) {
 			logger.Errorf("session(%v) start failed with error(%v), finalize it", s.sessionId, err)
 			s.finalize()
 			s.status = sessionStatusStopped
+			s.stopReason = rpc.StopReason_START_FAILURE
 		}
 	}()
 
+	if s.rtpStream != nil {
+		err = s.startReactorStream()
+		return
+	}
+
 	port := int(s.remotePort)
 	if _, err = s.rtpSession.AddRemote(&rtp.Address{
 		IPAddr:   s.remoteIp.IP,
@@ -119,21 +150,84 @@ func (s *MediaSession) Start() (err error) {
 
 	ctx, cancel := context.WithCancel(context.Background())
 	s.cancelFunc = cancel
+	s.nbLoop = 3
 	go s.receiveRtcpLoop(ctx)
 	go s.receiveRtpLoop(ctx)
 	go s.sendRtpLoop(ctx)
 	s.status = sessionStatusStarted
+	s.startTime = time.Now()
+	return
+}
+
+// startReactorStream hands over rtp/rtcp io to a reactor worker, which acts as the only loop of this session
+func (s *MediaSession) startReactorStream() (err error) {
+	if err = s.rtpStream.conn.setRemote(s.remoteIp.IP, int(s.remotePort)); err != nil {
+		return
+	}
+	prom.StartedSession.Inc()
+	r := s.server.reactor
+	s.rtpStream.attach()
+	worker := r.register(s.rtpStream)
+	s.reactorWorker = worker
+	stream := s.rtpStream
+	s.cancelFunc = func() {
+		r.unregister(worker, stream)
+		select {
+		case <-stream.doneC:
+		case <-time.After(5 * time.Second):
+			logger.Errorf("session(%v) is not removed from reactor in time", s.sessionId)
+		}
+	}
+	// worker closes doneC of the stream instead of notifying doneC of session
+	s.nbLoop = 0
+	s.status = sessionStatusStarted
+	s.startTime = time.Now()
 	return
 }
 
 func (s *MediaSession) Stop() {
+	s.stop(rpc.StopReason_EXPLICIT_STOP)
+}
+
+// GetStopReason returns why the session is stopped, rpc.StopReason_NONE if it is not stopped yet
+func (s *MediaSession) GetStopReason() rpc.StopReason {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.stopReason
+}
+
+// GetReport returns the final report, nil if session is not stopped yet
+func (s *MediaSession) GetReport() *rpc.SessionReport {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.report
+}
+
+// stop ends the session with reason, listeners are notified by the one call that actually stops it
+func (s *MediaSession) stop(reason rpc.StopReason) {
+	if s.terminate(reason) {
+		s.onStopped()
+	}
+}
+
+// onStopped makes the final report, then delivers it to listeners, instance and cdr file
+func (s *MediaSession) onStopped() {
+	report := s.makeReport()
+	logger.Infof("session(%v) stopped, reason: %v, duration: %vms", s.sessionId, report.StopReason, report.Duration)
+	s.server.invokeSessionListener(s, sessionStatusStopped)
+	s.server.deliverReport(report)
+}
+
+// terminate returns false if session is already stopped
+func (s *MediaSession) terminate(reason rpc.StopReason) bool {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 	var nbDone int
 	if s.status == sessionStatusStopped {
-		//logger.Errorf("try to stop already terminated session(%v)", s.sessionId)
-		return
+		//logger.Errorf("try to terminate already terminated session(%v)", s.sessionId)
+		return false
 	}
+	s.stopReason = reason
 	if s.status == sessionStatusCreated {
 		// created but not started
 		goto cleanup
@@ -143,7 +237,7 @@ func (s *MediaSession) Stop() {
 		s.cancelFunc()
 		// for debug purpose, check all loops are finished normally
 	done:
-		for nbDone < 3 {
+		for nbDone < s.nbLoop {
 			select {
 			case <-s.doneC:
 				nbDone++
@@ -151,7 +245,7 @@ func (s *MediaSession) Stop() {
 				break done
 			}
 		}
-		if nbDone != 3 {
+		if nbDone != s.nbLoop {
 			logger.Errorf("session(%v) loops don't stop normally, finished number:%v", s.sessionId, nbDone)
 		}
 		if s.doneC != nil {
@@ -162,4 +256,6 @@ func (s *MediaSession) Stop() {
 
 cleanup:
 	s.finalize()
+	s.status = sessionStatusStopped
+	return true
 }
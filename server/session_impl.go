@@ -10,6 +10,7 @@ import (
 	"net"
 	"strconv"
 	"sync/atomic"
+	"time"
 )
 
 type SessionIdType uint32
@@ -47,16 +48,19 @@ func profileOfCodec(c rpc.CodecType) (profile string) {
 func newSession(srv *MediaServer, mediaParam *rpc.CreateParam) (s *MediaSession, err error) {
 	var localPort, remotePort uint16
 	var remoteIp *net.IPAddr
+	var itf *rtpInterface
 
+	if itf, err = srv.getRtpInterface(mediaParam.GetInterfaceName()); err != nil {
+		return
+	}
 	defer func() {
 		// if create session failed, avoid port leaking
 		if err != nil && localPort > 0 {
-			srv.reclaimRtpPort(localPort)
+			itf.putPort(localPort)
 		}
 	}()
 
-	if localPort = srv.getNextAvailableRtpPort(); localPort == 0 {
-		err = errors.New("server runs out of port resource")
+	if localPort, err = itf.getPort(); err != nil {
 		return
 	}
 	instanceId := mediaParam.InstanceId
@@ -79,14 +83,19 @@ func newSession(srv *MediaServer, mediaParam *rpc.CreateParam) (s *MediaSession,
 		logger.Errorf("parse graph error: %v", err)
 		return nil, errors.New("composer parse graph description failed")
 	}
+	if err = srv.admission.checkGraph(composer); err != nil {
+		return
+	}
 	s = &MediaSession{
 		server:     srv,
 		sessionId:  sid,
-		localIp:    srv.rtpServerIpAddr,
+		localIp:    itf.ip,
 		localPort:  localPort,
+		rtpItf:     itf,
 		remoteIp:   remoteIp,
 		remotePort: remotePort,
 		instanceId: instanceId,
+		createTime: time.Now(),
 
 		// use buffered version to avoid deadlock
 		doneC:  make(chan string, 3),
@@ -122,7 +131,7 @@ func newSession(srv *MediaServer, mediaParam *rpc.CreateParam) (s *MediaSession,
 	}
 
 	// everything is checked, setup the watchdog
-	s.watchdog = newWatchDog(s)
+	s.watchdog = newWatchDog(s, srv.watchdogPolicy.override(watchdogPolicyFromRpc(mediaParam.GetWatchdog())))
 	return
 }
 
@@ -166,6 +175,9 @@ func (s *MediaSession) activate() (err error) {
 	if err = s.setupGraph(); err != nil {
 		return
 	}
+	if s.server.reactor != nil {
+		return s.activateReactorStream()
+	}
 	if tpLocal, err = rtp.NewTransportUDP(s.localIp, localPort, ""); err != nil {
 		return
 	}
@@ -189,6 +201,20 @@ func (s *MediaSession) activate() (err error) {
 	return nil
 }
 
+// activateReactorStream binds sockets for reactor io model, they are not polled until session started
+func (s *MediaSession) activateReactorStream() (err error) {
+	var conn *reactorConn
+	if profileOfCodec(s.avPayloadCodec) == "" {
+		return errors.New("unsupported rtp payload profile")
+	}
+	if conn, err = s.server.reactor.openConn(s.localIp.IP, int(s.localPort)); err != nil {
+		return
+	}
+	s.rtpStream = newReactorStream(s, conn)
+	s.watchdog.start()
+	return
+}
+
 //author:sean. purpose:update rtp params.but does not use
 func (s *MediaSession) UpdateRtpParams() (err error) {
 	if s.rtpSession == nil {
@@ -215,10 +241,18 @@ func (s *MediaSession) finalize() {
 		s.rtpSession.CloseSession()
 		s.status = sessionStatusStopped
 	}
+	if s.rtpStream != nil {
+		s.rtpStream.conn.close()
+		s.status = sessionStatusStopped
+	}
 	if s.localPort != 0 {
-		s.server.reclaimRtpPort(s.localPort)
+		s.rtpItf.putPort(s.localPort)
 		s.localPort = 0
 	}
+	if s.admitted {
+		s.server.admission.release(s.GetInstanceId())
+		s.admitted = false
+	}
 	prom.StartedSession.Dec()
 	s.server.removeFromSessionMap(s)
 }
@@ -226,9 +260,33 @@ func (s *MediaSession) finalize() {
 func (s *MediaSession) onSystemEvent(se *rpc.SystemEvent) {
 	switch se.Cmd {
 	case rpc.SystemCommand_USER_EVENT:
-		// TODO: use user event
+		s.onUserEvent(se)
 	case rpc.SystemCommand_SESSION_INFO:
 		s.watchdog.reportSessionInfo(se)
 	}
 
 }
+
+// onUserEvent routes event of instance into graph. if node name is given, the event is parsed as cast command and
+// sent to the node, otherwise it is delivered to event loops of all nodes implementing comp.UserEventReceiver
+func (s *MediaSession) onUserEvent(se *rpc.SystemEvent) {
+	if se.GetInstanceId() != s.GetInstanceId() {
+		logger.Errorf("session:%v user event from instance(%v) that doesn't own the session", s.sessionId,
+			se.GetInstanceId())
+		return
+	}
+	if se.GetNode() == "" {
+		s.composer.SendUserEvent(se.GetEvent())
+		return
+	}
+	if s.composer.GetNode(se.GetNode()) == nil {
+		logger.Errorf("session:%v user event to node(%v) that is not exist", s.sessionId, se.GetNode())
+		return
+	}
+	args, err := comp.WithString(se.GetEvent())
+	if err != nil {
+		logger.Errorf("session:%v user event to node(%v) with invalid command: %v", s.sessionId, se.GetNode(), err)
+		return
+	}
+	s.composer.GetCommandInitiator().Cast(comp.InstanceSender, se.GetNode(), args)
+}
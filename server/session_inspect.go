@@ -0,0 +1,188 @@
+package server
+
+import (
+	"github.com/appcrash/media/server/rpc"
+	"sync/atomic"
+	"time"
+)
+
+// rtpStats counts packets moved between rtp stack and graph, updated by session loops or reactor worker
+type rtpStats struct {
+	receivedPackets, receivedBytes uint64
+	droppedPackets                 uint64
+	sentPackets, sentBytes         uint64
+}
+
+func (st *rtpStats) received(nbByte int, dropped bool) {
+	atomic.AddUint64(&st.receivedPackets, 1)
+	atomic.AddUint64(&st.receivedBytes, uint64(nbByte))
+	if dropped {
+		atomic.AddUint64(&st.droppedPackets, 1)
+	}
+}
+
+func (st *rtpStats) sent(nbByte int) {
+	atomic.AddUint64(&st.sentPackets, 1)
+	atomic.AddUint64(&st.sentBytes, uint64(nbByte))
+}
+
+func (st *rtpStats) toRpc() *rpc.RtpStats {
+	return &rpc.RtpStats{
+		ReceivedPackets: atomic.LoadUint64(&st.receivedPackets),
+		ReceivedBytes:   atomic.LoadUint64(&st.receivedBytes),
+		DroppedPackets:  atomic.LoadUint64(&st.droppedPackets),
+		SentPackets:     atomic.LoadUint64(&st.sentPackets),
+		SentBytes:       atomic.LoadUint64(&st.sentBytes),
+	}
+}
+
+func statusString(status int) string {
+	switch status {
+	case sessionStatusCreated:
+		return "created"
+	case sessionStatusUpdated:
+		return "updated"
+	case sessionStatusStarted:
+		return "started"
+	case sessionStatusStopped:
+		return "stopped"
+	}
+	return "unknown"
+}
+
+func unixMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+// matchListParam checks session against filters of ListSessions
+func (s *MediaSession) matchListParam(param *rpc.ListParam, now time.Time) bool {
+	if param.GetInstanceId() != "" && param.GetInstanceId() != s.GetInstanceId() {
+		return false
+	}
+	if statusList := param.GetStatus(); len(statusList) > 0 {
+		status := statusString(s.GetStatus())
+		matched := false
+		for _, st := range statusList {
+			if st == status {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	age := now.Sub(s.createTime)
+	if age < time.Duration(param.GetMinAge())*time.Second {
+		return false
+	}
+	if param.GetMaxAge() > 0 && age > time.Duration(param.GetMaxAge())*time.Second {
+		return false
+	}
+	return true
+}
+
+func (s *MediaSession) info() *rpc.SessionInfo {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return &rpc.SessionInfo{
+		SessionId:     s.sessionId.String(),
+		InstanceId:    s.GetInstanceId(),
+		Status:        statusString(s.status),
+		CreateTime:    unixMilli(s.createTime),
+		InterfaceName: s.rtpItf.name,
+		LocalIp:       s.localIp.String(),
+		LocalRtpPort:  uint32(s.localPort),
+		PeerIp:        s.remoteIp.String(),
+		PeerRtpPort:   uint32(s.remotePort),
+	}
+}
+
+func (s *MediaSession) describe() *rpc.SessionDescription {
+	desc := &rpc.SessionDescription{
+		Info:     s.info(),
+		Watchdog: s.watchdog.info(),
+		Stats:    s.stats.toRpc(),
+	}
+	desc.Codecs = s.codecInfos()
+	s.describeGraph(desc)
+	return desc
+}
+
+func (s *MediaSession) codecInfos() []*rpc.CodecInfo {
+	codecs := []*rpc.CodecInfo{{
+		PayloadNumber: uint32(s.avPayloadNumber),
+		PayloadType:   s.avPayloadCodec,
+		CodecParam:    s.avCodecParam,
+	}}
+	if s.telephoneEventPayloadNumber != 0 {
+		codecs = append(codecs, &rpc.CodecInfo{
+			PayloadNumber: uint32(s.telephoneEventPayloadNumber),
+			PayloadType:   s.telephoneEventPayloadCodec,
+			CodecParam:    s.telephoneEventCodecParam,
+		})
+	}
+	return codecs
+}
+
+// describeGraph fills nodes and links with composed ones, plus links created at runtime, which are only known by
+// event graph
+func (s *MediaSession) describeGraph(desc *rpc.SessionDescription) {
+	scope := s.sessionId.String()
+	nodeName := func(nodeScope, name string) string {
+		if nodeScope == scope {
+			return name
+		}
+		return name + "@" + nodeScope
+	}
+	composed := make(map[string]*rpc.GraphLink)
+	for _, nd := range s.composer.GetSortedNodes() {
+		nodeScope := nd.Scope
+		if nodeScope == "" {
+			nodeScope = scope
+		}
+		desc.Nodes = append(desc.Nodes, &rpc.GraphNode{Name: nd.Name, Type: nd.Type, Scope: nodeScope})
+	}
+	for _, l := range s.composer.GetLinks() {
+		gl := &rpc.GraphLink{
+			From: nodeName(l.Sender.GetNodeScope(), l.Sender.GetNodeName()),
+			To:   nodeName(l.ReceiverScope, l.ReceiverName),
+		}
+		if trait := l.LinkPoint.MessageTrait(); trait != nil {
+			gl.MessageType = trait.Name()
+		}
+		composed[gl.From+"->"+gl.To] = gl
+		desc.Links = append(desc.Links, gl)
+	}
+
+	si := s.server.graph.DescribeScope(scope)
+	if si == nil {
+		logger.Errorf("session(%v) describe graph timeout", scope)
+		return
+	}
+	for _, l := range si.Links {
+		from, to := nodeName(l.From.Scope, l.From.Name), nodeName(l.To.Scope, l.To.Name)
+		gl, ok := composed[from+"->"+to]
+		if !ok {
+			gl = &rpc.GraphLink{From: from, To: to}
+			desc.Links = append(desc.Links, gl)
+		}
+		gl.Overflow = l.Overflow.String()
+		gl.Dropped = l.Dropped
+	}
+}
+
+func (wd *WatchDog) info() *rpc.WatchdogInfo {
+	wd.mutex.Lock()
+	defer wd.mutex.Unlock()
+	return &rpc.WatchdogInfo{
+		InstanceAliveTime: unixMilli(wd.instanceAliveTimestamp),
+		SendAliveTime:     unixMilli(wd.loopAliveTimestamp[sendLoop]),
+		ReceiveAliveTime:  unixMilli(wd.loopAliveTimestamp[receiveLoop]),
+		RtcpAliveTime:     unixMilli(wd.loopAliveTimestamp[rtcpLoop]),
+		ErrorCount:        wd.nbError,
+	}
+}
@@ -0,0 +1,85 @@
+package server_test
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"github.com/appcrash/media/server/rpc"
+	"google.golang.org/grpc"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestSessionInspection(t *testing.T) {
+	conn, err := grpc.Dial(fmt.Sprintf("%v:%v", grpcIp, grpcPort), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := rpc.NewMediaApiClient(conn)
+	// GoRTP requires peer data port to be even
+	var peer *net.UDPConn
+	for port := 42000; peer == nil && port < 43000; port += 2 {
+		peer, _ = net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: port})
+	}
+	if peer == nil {
+		t.Fatal("no even port for peer")
+	}
+	defer peer.Close()
+
+	ctx := context.Background()
+	session, err := client.PrepareSession(ctx, &rpc.CreateParam{
+		PeerIp:   "127.0.0.1",
+		PeerPort: uint32(peer.LocalAddr().(*net.UDPAddr).Port),
+		Codecs: []*rpc.CodecInfo{{
+			PayloadNumber: 8,
+			PayloadType:   rpc.CodecType_PCM_ALAW,
+		}},
+		GraphDesc:  "[echo]",
+		InstanceId: "inspect",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.StopSession(ctx, &rpc.StopParam{SessionId: session.SessionId})
+
+	list, err := client.ListSessions(ctx, &rpc.ListParam{InstanceId: "inspect", Status: []string{"created"}})
+	if err != nil || len(list.Sessions) != 1 || list.Sessions[0].SessionId != session.SessionId {
+		t.Fatalf("created session should be listed: %v %v", list, err)
+	}
+	if list, _ = client.ListSessions(ctx, &rpc.ListParam{InstanceId: "inspect", MinAge: 3600}); len(list.Sessions) != 0 {
+		t.Fatal("new session should not match min age")
+	}
+
+	if _, err = client.StartSession(ctx, &rpc.StartParam{SessionId: session.SessionId}); err != nil {
+		t.Fatal(err)
+	}
+	packet := make([]byte, 12+160)
+	packet[0], packet[1] = 0x80, 8
+	remote := &net.UDPAddr{IP: net.ParseIP(session.LocalIp), Port: int(session.LocalRtpPort)}
+	buf := make([]byte, 1500)
+	for seq := 0; seq < 5; seq++ {
+		binary.BigEndian.PutUint16(packet[2:], uint16(seq))
+		peer.WriteToUDP(packet, remote)
+		peer.SetReadDeadline(time.Now().Add(time.Second))
+		peer.ReadFromUDP(buf)
+	}
+
+	desc, err := client.DescribeSession(ctx, &rpc.DescribeParam{SessionId: session.SessionId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if desc.Info.Status != "started" || desc.Info.PeerIp != "127.0.0.1" || len(desc.Codecs) != 1 {
+		t.Fatalf("invalid session description: %v", desc)
+	}
+	if desc.Stats.ReceivedPackets == 0 || desc.Stats.SentPackets == 0 {
+		t.Fatalf("rtp stats should be counted: %v", desc.Stats)
+	}
+	if len(desc.Nodes) != 1 || desc.Nodes[0].Type != "echo" {
+		t.Fatalf("invalid graph nodes: %v", desc.Nodes)
+	}
+	if _, err = client.DescribeSession(ctx, &rpc.DescribeParam{SessionId: "1"}); err == nil {
+		t.Fatal("describe session not exist should fail")
+	}
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"github.com/appcrash/media/server/rpc"
+	"github.com/appcrash/media/server/utils"
+	"sync"
+	"time"
+)
+
+// lifecycleWatcherBuffer is the max events queued for a watcher, events are dropped if watcher can't keep up
+const lifecycleWatcherBuffer = 256
+
+type lifecycleWatcher struct {
+	instanceId string
+	c          chan *rpc.SessionEvent
+	nbDropped  int
+}
+
+// lifecycleHub fans out session lifecycle events to watchers of WatchSessions rpc
+type lifecycleHub struct {
+	mutex    sync.Mutex
+	watchers *utils.Set[*lifecycleWatcher]
+}
+
+func newLifecycleHub() *lifecycleHub {
+	return &lifecycleHub{
+		watchers: utils.NewSet[*lifecycleWatcher](),
+	}
+}
+
+func (h *lifecycleHub) subscribe(instanceId string) *lifecycleWatcher {
+	w := &lifecycleWatcher{
+		instanceId: instanceId,
+		c:          make(chan *rpc.SessionEvent, lifecycleWatcherBuffer),
+	}
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.watchers.Add(w)
+	return w
+}
+
+func (h *lifecycleHub) unsubscribe(w *lifecycleWatcher) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.watchers.Remove(w)
+}
+
+// publish nonblock sends event to watchers of the session's instance
+func (h *lifecycleHub) publish(session *MediaSession, status int) {
+	evt := &rpc.SessionEvent{
+		SessionId:  session.sessionId.String(),
+		InstanceId: session.GetInstanceId(),
+		Time:       unixMilli(time.Now()),
+	}
+	switch status {
+	case sessionStatusCreated:
+		evt.Type = rpc.SessionEventType_SESSION_CREATED
+	case sessionStatusUpdated:
+		evt.Type = rpc.SessionEventType_SESSION_UPDATED
+	case sessionStatusStarted:
+		evt.Type = rpc.SessionEventType_SESSION_STARTED
+	case sessionStatusStopped:
+		evt.Type = rpc.SessionEventType_SESSION_STOPPED
+		evt.StopReason = session.GetStopReason()
+		evt.Report = session.GetReport()
+	case sessionStatusAdopted:
+		evt.Type = rpc.SessionEventType_SESSION_ADOPTED
+	default:
+		return
+	}
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.watchers.Iterate(func(w *lifecycleWatcher) {
+		if w.instanceId != evt.InstanceId {
+			return
+		}
+		select {
+		case w.c <- evt:
+		default:
+			w.nbDropped++
+			logger.Warnf("instance(%v) watcher is too slow, drop session event(total dropped:%v)",
+				w.instanceId, w.nbDropped)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package server_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/appcrash/media/server/rpc"
+	"google.golang.org/grpc"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestSessionLifecycleEvents(t *testing.T) {
+	conn, err := grpc.Dial(fmt.Sprintf("%v:%v", grpcIp, grpcPort), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := rpc.NewMediaApiClient(conn)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.WatchSessions(ctx, &rpc.WatchParam{InstanceId: "lifecycle"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := make(chan *rpc.SessionEvent, 16)
+	go func() {
+		for {
+			evt, err := stream.Recv()
+			if err != nil {
+				close(events)
+				return
+			}
+			events <- evt
+		}
+	}()
+	// wait for the watcher subscribed
+	time.Sleep(100 * time.Millisecond)
+
+	prepare := func(instanceId string) *rpc.Session {
+		s, err := client.PrepareSession(ctx, &rpc.CreateParam{
+			PeerIp:   "127.0.0.1",
+			PeerPort: 44000,
+			Codecs: []*rpc.CodecInfo{{
+				PayloadNumber: 8,
+				PayloadType:   rpc.CodecType_PCM_ALAW,
+			}},
+			GraphDesc:  "[echo]",
+			InstanceId: instanceId,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	expect := func(sessionId string, typ rpc.SessionEventType, reason rpc.StopReason) {
+		select {
+		case evt := <-events:
+			if evt.SessionId != sessionId || evt.Type != typ || evt.StopReason != reason ||
+				evt.InstanceId != "lifecycle" {
+				t.Fatalf("unexpected event: %v, expect %v of %v(%v)", evt, typ, sessionId, reason)
+			}
+			if typ == rpc.SessionEventType_SESSION_STOPPED &&
+				(evt.Report == nil || evt.Report.StopReason != reason || evt.Report.StartTime == 0) {
+				t.Fatalf("stopped event without valid report: %v", evt)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("no event received, expect %v of %v", typ, sessionId)
+		}
+	}
+
+	// events of other instance are filtered
+	other := prepare("lifecycle_other")
+	client.StopSession(ctx, &rpc.StopParam{SessionId: other.SessionId})
+
+	s1 := prepare("lifecycle")
+	expect(s1.SessionId, rpc.SessionEventType_SESSION_CREATED, rpc.StopReason_NONE)
+	client.StartSession(ctx, &rpc.StartParam{SessionId: s1.SessionId})
+	expect(s1.SessionId, rpc.SessionEventType_SESSION_STARTED, rpc.StopReason_NONE)
+	client.StopSession(ctx, &rpc.StopParam{SessionId: s1.SessionId})
+	expect(s1.SessionId, rpc.SessionEventType_SESSION_STOPPED, rpc.StopReason_EXPLICIT_STOP)
+
+	s2 := prepare("lifecycle")
+	expect(s2.SessionId, rpc.SessionEventType_SESSION_CREATED, rpc.StopReason_NONE)
+	client.StartSession(ctx, &rpc.StartParam{SessionId: s2.SessionId})
+	expect(s2.SessionId, rpc.SessionEventType_SESSION_STARTED, rpc.StopReason_NONE)
+	peer, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP("127.0.0.1"), Port: 44001})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer peer.Close()
+	// compound rtcp: empty RR then BYE
+	bye := []byte{0x80, 201, 0, 1, 0x12, 0x34, 0x56, 0x78, 0x81, 203, 0, 1, 0x12, 0x34, 0x56, 0x78}
+	peer.WriteToUDP(bye, &net.UDPAddr{IP: net.ParseIP(s2.LocalIp), Port: int(s2.LocalRtpPort) + 1})
+	expect(s2.SessionId, rpc.SessionEventType_SESSION_STOPPED, rpc.StopReason_PEER_BYE)
+}
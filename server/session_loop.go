@@ -4,9 +4,11 @@ import (
 	"context"
 	"github.com/appcrash/GoRTP/rtp"
 	"github.com/appcrash/media/server/prom"
+	"github.com/appcrash/media/server/rpc"
 	"github.com/appcrash/media/server/utils"
 	"github.com/prometheus/client_golang/prometheus"
 	"runtime/debug"
+	"time"
 )
 
 // receive rtcp packet
@@ -32,7 +34,7 @@ func (s *MediaSession) receiveRtcpLoop(ctx context.Context) {
 				if evt.EventType == rtp.RtcpBye {
 					// peer send bye, notify data send/receive loop to stop
 					logger.Debugf("session: %v rtp peer says bye", s.sessionId)
-					go s.Stop() // CAVEAT: don't call Stop() in this goroutine directly
+					go s.stop(rpc.StopReason_PEER_BYE) // CAVEAT: don't call stop() in this goroutine directly
 					return
 				}
 			}
@@ -42,6 +44,32 @@ func (s *MediaSession) receiveRtcpLoop(ctx context.Context) {
 	}
 }
 
+// pushReceived pushes received packets to graph, waits no longer than RtpOverflowTimeout of config as rtp stack can
+// not wait. the consumer only exposes a send-only channel, so the newest packets are dropped if graph doesn't catch up
+func (s *MediaSession) pushReceived(handleC chan<- *utils.RtpPacketList, pl *utils.RtpPacketList) {
+	// consumer may release the packet as soon as it is sent
+	n := len(pl.Payload)
+	select {
+	case handleC <- pl:
+		s.stats.received(n, false)
+		return
+	default:
+	}
+	if timeout := s.server.rtpOverflow; timeout > 0 {
+		timer := time.NewTimer(timeout)
+		select {
+		case handleC <- pl:
+			timer.Stop()
+			s.stats.received(n, false)
+			return
+		case <-timer.C:
+		}
+	}
+	s.stats.received(n, true)
+	prom.SessionRtpDropped.Inc()
+	pl.Release()
+}
+
 func (s *MediaSession) receiveRtpLoop(ctx context.Context) {
 	gauge := prom.SessionGoroutine.With(prometheus.Labels{"type": "recv"})
 	gauge.Inc()
@@ -82,21 +110,19 @@ func (s *MediaSession) receiveRtpLoop(ctx context.Context) {
 				return
 			}
 
-			// nonblock push received data to handler
+			// nonblock push received data to handler, packet is copied to a pooled buffer so that it can be freed to
+			// rtp stack for reusing
 			pl := utils.NewPacketListFromRtpPacket(rp)
-			select {
-			case s.handleC <- pl:
-			default:
+			rp.FreePacket()
+			if pl == nil {
+				continue
 			}
+			s.pushReceived(s.handleC, pl)
 			nbPacket++
 			if nbPacket > ReportInfoPacketInterval {
 				nbPacket = 0
 				s.watchdog.reportLoopInfo(receiveLoop)
 			}
-
-			// don't free packet, let it be GCed, as GoRTP will reuse this packet along with its buffer
-			// which may be hold by other packet-list objects
-			// rp.FreePacket()
 		case <-cancelC:
 			return
 		}
@@ -123,6 +149,7 @@ func (s *MediaSession) sendRtpLoop(ctx context.Context) {
 		logger.Infof("session:%v has no rtp pulling channel, stop local send early", s.sessionId)
 	}
 
+	s.watchdog.reportLoopInfo(sendLoop)
 	var nbPacket int
 	cancelC := ctx.Done()
 	for {
@@ -152,10 +179,15 @@ func (s *MediaSession) sendRtpLoop(ctx context.Context) {
 					packet.SetPayloadType(s.avPayloadNumber)
 					if _, err := s.rtpSession.WriteData(packet); err != nil {
 						s.watchdog.reportLoopError(sendLoop, err)
+					} else {
+						s.stats.sent(len(payload))
 					}
+					// payload is copied and written, recycle the packet
+					packet.FreePacket()
 				}
 				nbPacket++
 			})
+			packetList.Release()
 			if nbPacket > ReportInfoPacketInterval {
 				nbPacket = 0
 				s.watchdog.reportLoopInfo(sendLoop)
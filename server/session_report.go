@@ -0,0 +1,118 @@
+package server
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/appcrash/media/server/channel"
+	"github.com/appcrash/media/server/rpc"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// makeReport summarizes the stopped session, it is made only once
+func (s *MediaSession) makeReport() *rpc.SessionReport {
+	info := s.info()
+	wd := s.watchdog.info()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.report != nil {
+		return s.report
+	}
+	now := time.Now()
+	begin := s.startTime
+	if begin.IsZero() {
+		begin = s.createTime
+	}
+	s.report = &rpc.SessionReport{
+		SessionId:     info.SessionId,
+		InstanceId:    info.InstanceId,
+		StopReason:    s.stopReason,
+		CreateTime:    info.CreateTime,
+		StartTime:     unixMilli(s.startTime),
+		StopTime:      unixMilli(now),
+		Duration:      int64(now.Sub(begin) / time.Millisecond),
+		Codecs:        s.codecInfos(),
+		Stats:         s.stats.toRpc(),
+		ErrorCount:    wd.ErrorCount,
+		InterfaceName: info.InterfaceName,
+		LocalIp:       info.LocalIp,
+		LocalRtpPort:  info.LocalRtpPort,
+		PeerIp:        info.PeerIp,
+		PeerRtpPort:   info.PeerRtpPort,
+	}
+	return s.report
+}
+
+// deliverReport sends report to the instance created the session, and writes cdr if enabled
+func (srv *MediaServer) deliverReport(report *rpc.SessionReport) {
+	if report.InstanceId != "" && channel.GetSystemChannel().HasInstance(report.InstanceId) {
+		if err := channel.GetSystemChannel().NotifyInstance(&rpc.SystemEvent{
+			Cmd:        rpc.SystemCommand_SESSION_REPORT,
+			InstanceId: report.InstanceId,
+			SessionId:  report.SessionId,
+			Report:     report,
+		}); err != nil {
+			logger.Errorf("session(%v) failed to deliver report: %v", report.SessionId, err)
+		}
+	}
+	if srv.cdr != nil {
+		if err := srv.cdr.write(report); err != nil {
+			logger.Errorf("session(%v) failed to write cdr: %v", report.SessionId, err)
+		}
+	}
+}
+
+// cdrWriter appends one csv line for each stopped session, columns are:
+// session_id,instance_id,stop_reason,create_time,start_time,stop_time,duration,
+// received_packets,received_bytes,dropped_packets,sent_packets,sent_bytes,error_count,
+// interface_name,local_ip,local_rtp_port,peer_ip,peer_rtp_port,codecs
+//
+// times are unix milliseconds, codecs are joined by ';' as {codec}/{payload number}
+type cdrWriter struct {
+	mutex sync.Mutex
+	file  *os.File
+	w     *csv.Writer
+}
+
+func newCdrWriter(path string) (*cdrWriter, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &cdrWriter{file: f, w: csv.NewWriter(f)}, nil
+}
+
+func (cw *cdrWriter) write(r *rpc.SessionReport) error {
+	var codecs []string
+	for _, c := range r.Codecs {
+		codecs = append(codecs, fmt.Sprintf("%v/%v", c.PayloadType, c.PayloadNumber))
+	}
+	i64 := func(v int64) string { return strconv.FormatInt(v, 10) }
+	u64 := func(v uint64) string { return strconv.FormatUint(v, 10) }
+	st := r.Stats
+	record := []string{
+		r.SessionId, r.InstanceId, r.StopReason.String(),
+		i64(r.CreateTime), i64(r.StartTime), i64(r.StopTime), i64(r.Duration),
+		u64(st.GetReceivedPackets()), u64(st.GetReceivedBytes()), u64(st.GetDroppedPackets()),
+		u64(st.GetSentPackets()), u64(st.GetSentBytes()), i64(int64(r.ErrorCount)),
+		r.InterfaceName, r.LocalIp, u64(uint64(r.LocalRtpPort)), r.PeerIp, u64(uint64(r.PeerRtpPort)),
+		strings.Join(codecs, ";"),
+	}
+	cw.mutex.Lock()
+	defer cw.mutex.Unlock()
+	if err := cw.w.Write(record); err != nil {
+		return err
+	}
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+func (cw *cdrWriter) close() {
+	cw.mutex.Lock()
+	defer cw.mutex.Unlock()
+	cw.w.Flush()
+	cw.file.Close()
+}
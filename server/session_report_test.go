@@ -0,0 +1,75 @@
+package server_test
+
+import (
+	"context"
+	"encoding/csv"
+	"github.com/appcrash/media/server"
+	"github.com/appcrash/media/server/rpc"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type reportListener struct {
+	server.BaseSessionListener
+	reports chan *rpc.SessionReport
+}
+
+func (l *reportListener) OnSessionStopped(s *server.MediaSession) {
+	if s.GetStopReason() == s.GetReport().StopReason {
+		l.reports <- s.GetReport()
+	}
+}
+
+func TestSessionReportCdr(t *testing.T) {
+	cdrFile := filepath.Join(t.TempDir(), "cdr.csv")
+	listener := &reportListener{reports: make(chan *rpc.SessionReport, 1)}
+	client := startTestServer(t, &server.Config{
+		RtpIp:               "127.0.0.1",
+		CdrFile:             cdrFile,
+		SessionListenerList: []server.SessionListener{listener},
+	}).client
+
+	ctx := context.Background()
+	s, err := client.PrepareSession(ctx, &rpc.CreateParam{
+		PeerIp:   "127.0.0.1",
+		PeerPort: 44010,
+		Codecs: []*rpc.CodecInfo{{
+			PayloadNumber: 8,
+			PayloadType:   rpc.CodecType_PCM_ALAW,
+		}},
+		GraphDesc:  "[echo]",
+		InstanceId: "report",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.StopSession(ctx, &rpc.StopParam{SessionId: s.SessionId}); err != nil {
+		t.Fatal(err)
+	}
+	var report *rpc.SessionReport
+	select {
+	case report = <-listener.reports:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no session report after stopping session")
+	}
+	if report.SessionId != s.SessionId || report.StopReason != rpc.StopReason_EXPLICIT_STOP ||
+		report.StartTime != 0 || len(report.Codecs) != 1 {
+		t.Fatalf("invalid report: %v", report)
+	}
+
+	f, err := os.Open(cdrFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0][0] != s.SessionId || records[0][2] != "EXPLICIT_STOP" ||
+		records[0][18] != "PCM_ALAW/8" {
+		t.Fatalf("invalid cdr: %v", records)
+	}
+}
@@ -0,0 +1,298 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/appcrash/media/server/rpc"
+	"github.com/appcrash/media/server/sdp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strconv"
+	"strings"
+)
+
+// answerFmtpKeys are format parameters echoed in answer, the others are dropped as media server doesn't support
+// them. all parameters are echoed for codecs not listed here.
+var answerFmtpKeys = map[rpc.CodecType][]string{
+	rpc.CodecType_PCM_ALAW: {},
+	rpc.CodecType_AMRNB:    {"octet-align", "mode-set"},
+	rpc.CodecType_AMRWB:    {"octet-align", "mode-set"},
+	rpc.CodecType_H264:     {"profile-level-id", "packetization-mode", "level-asymmetry-allowed"},
+}
+
+const defaultTelephoneEventFmtp = "0-15"
+
+// sdpNegotiation is result of negotiating remote offer, only one audio/video media is accepted
+type sdpNegotiation struct {
+	offer      *sdp.Description
+	mediaIndex int
+	peerIp     string
+	peerPort   uint32
+	direction  string // of answer
+	av, te     sdp.RtpMap
+	codecs     []*rpc.CodecInfo
+}
+
+// codecOfEncoding maps upper-cased encoding name of rtpmap to audio/video codec, the reverse of profileOfCodec
+var codecOfEncoding = map[string]rpc.CodecType{
+	"PCMA":   rpc.CodecType_PCM_ALAW,
+	"AMR":    rpc.CodecType_AMRNB,
+	"AMR-WB": rpc.CodecType_AMRWB,
+	"H264":   rpc.CodecType_H264,
+	"EVS":    rpc.CodecType_EVS,
+}
+
+// codecOfRtpMap maps encoding name back to codec type, telephone-event is told apart by clock rate
+func codecOfRtpMap(rm sdp.RtpMap) (rpc.CodecType, bool) {
+	encoding := strings.ToUpper(rm.Encoding)
+	if encoding == "TELEPHONE-EVENT" {
+		switch rm.ClockRate {
+		case 8000:
+			return rpc.CodecType_TELEPHONE_EVENT_8K, true
+		case 16000:
+			return rpc.CodecType_TELEPHONE_EVENT_16K, true
+		}
+		return rpc.CodecType_RAW, false
+	}
+	c, ok := codecOfEncoding[encoding]
+	return c, ok
+}
+
+// answerFmtp keeps offered format parameters that media server supports
+func answerFmtp(c rpc.CodecType, offered string) string {
+	keys, ok := answerFmtpKeys[c]
+	if !ok {
+		return offered
+	}
+	var params []sdp.FmtpParam
+	for _, p := range sdp.ParseFmtp(offered) {
+		for _, k := range keys {
+			if p.Key == k {
+				params = append(params, p)
+				break
+			}
+		}
+	}
+	return sdp.FormatFmtp(params)
+}
+
+func isRtpProto(proto string) bool {
+	return proto == "RTP/AVP" || proto == "RTP/AVPF"
+}
+
+// negotiateOffer accepts the first rtp media with supported audio/video codec, codecs are chosen by preference of
+// the offer. telephone-event is accepted if its clock rate matches the chosen codec.
+func negotiateOffer(offerSdp string) (n *sdpNegotiation, err error) {
+	var offer *sdp.Description
+	if offer, err = sdp.Parse(offerSdp); err != nil {
+		return
+	}
+	for i, m := range offer.Media {
+		if m.Port == 0 || !isRtpProto(m.Proto) {
+			continue
+		}
+		var av *rpc.CodecInfo
+		var teCandidates []sdp.RtpMap
+		n = &sdpNegotiation{offer: offer, mediaIndex: i}
+		for _, format := range m.Formats {
+			pt, err1 := strconv.Atoi(format)
+			if err1 != nil {
+				continue
+			}
+			rm, ok := m.RtpMap(pt)
+			if !ok {
+				continue
+			}
+			c, ok := codecOfRtpMap(rm)
+			if !ok {
+				continue
+			}
+			switch c {
+			case rpc.CodecType_TELEPHONE_EVENT_8K, rpc.CodecType_TELEPHONE_EVENT_16K:
+				teCandidates = append(teCandidates, rm)
+			default:
+				if av == nil {
+					n.av = rm
+					av = &rpc.CodecInfo{
+						PayloadNumber: uint32(pt),
+						PayloadType:   c,
+						CodecParam:    answerFmtp(c, m.Fmtp(pt)),
+					}
+				}
+			}
+		}
+		if av == nil {
+			continue
+		}
+		n.codecs = []*rpc.CodecInfo{av}
+		for _, rm := range teCandidates {
+			if rm.ClockRate == n.av.ClockRate {
+				c, _ := codecOfRtpMap(rm)
+				n.te = rm
+				n.codecs = append(n.codecs, &rpc.CodecInfo{
+					PayloadNumber: uint32(rm.PayloadNumber),
+					PayloadType:   c,
+					CodecParam:    m.Fmtp(rm.PayloadNumber),
+				})
+				break
+			}
+		}
+		n.peerIp = offer.ConnectionOf(m)
+		n.peerPort = uint32(m.Port)
+		n.direction = sdp.AnswerDirection(offer.DirectionOf(m))
+		if n.peerIp == "" {
+			return nil, errors.New("no connection address in offer")
+		}
+		return
+	}
+	return nil, errors.New("no acceptable rtp media in offer")
+}
+
+// answer builds sdp with the negotiated media, other offered media are rejected by zero port
+func (n *sdpNegotiation) answer(s *MediaSession) string {
+	s.mutex.Lock()
+	s.sdpVersion++
+	version := s.sdpVersion
+	s.mutex.Unlock()
+	localIp := s.localIp.IP.String()
+	d := &sdp.Description{
+		Origin: sdp.Origin{
+			Username:  "-",
+			SessionId: uint64(s.sessionId),
+			Version:   version,
+			Address:   localIp,
+		},
+		Name:       "media",
+		Connection: localIp,
+	}
+	for i, om := range n.offer.Media {
+		if i != n.mediaIndex {
+			d.Media = append(d.Media, &sdp.Media{Type: om.Type, Proto: om.Proto, Formats: om.Formats[:1]})
+			continue
+		}
+		m := &sdp.Media{Type: om.Type, Port: int(s.localPort), Proto: om.Proto}
+		for j, ci := range n.codecs {
+			pt := strconv.Itoa(int(ci.PayloadNumber))
+			rm := n.av
+			if j > 0 {
+				rm = n.te
+			}
+			m.Formats = append(m.Formats, pt)
+			m.Attributes = append(m.Attributes, sdp.Attribute{Key: "rtpmap", Value: rm.String()})
+			fmtp := ci.CodecParam
+			if j > 0 && fmtp == "" {
+				fmtp = defaultTelephoneEventFmtp
+			}
+			if fmtp != "" {
+				m.Attributes = append(m.Attributes, sdp.Attribute{Key: "fmtp", Value: pt + " " + fmtp})
+			}
+		}
+		for _, a := range om.Attributes {
+			if a.Key == "ptime" {
+				m.Attributes = append(m.Attributes, a)
+			}
+		}
+		m.Attributes = append(m.Attributes, sdp.Attribute{Key: n.direction})
+		d.Media = append(d.Media, m)
+	}
+	return d.Marshal()
+}
+
+func (srv *MediaServer) PrepareSessionWithOffer(ctx context.Context, param *rpc.OfferParam) (*rpc.Answer, error) {
+	n, err := negotiateOffer(param.GetSdp())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid offer: %v", err)
+	}
+	rpcSession, err := srv.PrepareSession(ctx, &rpc.CreateParam{
+		PeerIp:        n.peerIp,
+		PeerPort:      n.peerPort,
+		Codecs:        n.codecs,
+		GraphDesc:     param.GetGraphDesc(),
+		InstanceId:    param.GetInstanceId(),
+		InterfaceName: param.GetInterfaceName(),
+		Watchdog:      param.GetWatchdog(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	sessionId, _ := SessionIdFromString(rpcSession.SessionId)
+	srv.sessionMutex.Lock()
+	session, ok := srv.sessionMap[sessionId]
+	srv.sessionMutex.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("session(%v) stopped before answering", sessionId)
+	}
+	session.mutex.Lock()
+	session.sdpDirection = n.direction
+	session.mutex.Unlock()
+	return &rpc.Answer{Session: rpcSession, Codecs: n.codecs, Sdp: n.answer(session)}, nil
+}
+
+// UpdateSessionWithOffer answers re-offer of the session, peer address, payload number of codec and telephone-event
+// can be changed before session started, while codec and direction can never be changed
+func (srv *MediaServer) UpdateSessionWithOffer(ctx context.Context, param *rpc.ReofferParam) (*rpc.Answer, error) {
+	if err := srv.authorizeSession(ctx, param.GetSessionId()); err != nil {
+		return nil, err
+	}
+	sessionId, err := SessionIdFromString(param.GetSessionId())
+	if err != nil {
+		return nil, errors.New("invalid session id")
+	}
+	srv.sessionMutex.Lock()
+	session, ok := srv.sessionMap[sessionId]
+	srv.sessionMutex.Unlock()
+	if !ok {
+		return nil, errors.New("session not exist")
+	}
+	n, err := negotiateOffer(param.GetSdp())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid offer: %v", err)
+	}
+	av := n.codecs[0]
+	if av.PayloadType != session.avPayloadCodec {
+		return nil, status.Errorf(codes.FailedPrecondition, "codec can not be changed from %v to %v",
+			session.avPayloadCodec, av.PayloadType)
+	}
+	session.mutex.Lock()
+	direction := session.sdpDirection
+	session.mutex.Unlock()
+	if direction != "" && direction != n.direction {
+		// media is always sent and received, the direction only tells the peer what is expected
+		return nil, status.Errorf(codes.FailedPrecondition, "direction can not be changed from %v to %v",
+			direction, n.direction)
+	}
+	te := &rpc.CodecInfo{PayloadType: rpc.CodecType_RAW}
+	if len(n.codecs) > 1 {
+		te = n.codecs[1]
+	}
+	if n.peerIp != session.remoteIp.String() || n.peerPort != uint32(session.remotePort) ||
+		uint8(av.PayloadNumber) != session.avPayloadNumber ||
+		uint8(te.PayloadNumber) != session.telephoneEventPayloadNumber ||
+		te.PayloadType != session.telephoneEventPayloadCodec || te.CodecParam != session.telephoneEventCodecParam {
+		if err = srv.updateSessionWith(&rpc.UpdateParam{
+			SessionId:     param.GetSessionId(),
+			PeerIp:        n.peerIp,
+			PeerPort:      n.peerPort,
+			PayloadNumber: int32(av.PayloadNumber),
+		}, func(s *MediaSession) {
+			s.telephoneEventPayloadNumber = uint8(te.PayloadNumber)
+			s.telephoneEventPayloadCodec = te.PayloadType
+			s.telephoneEventCodecParam = te.CodecParam
+			s.mutex.Lock()
+			s.sdpDirection = n.direction
+			s.mutex.Unlock()
+		}); err != nil {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+	}
+	rpcSession := &rpc.Session{
+		SessionId:     session.sessionId.String(),
+		LocalIp:       session.localIp.String(),
+		LocalRtpPort:  uint32(session.localPort),
+		PeerIp:        n.peerIp,
+		PeerRtpPort:   n.peerPort,
+		InterfaceName: session.rtpItf.name,
+	}
+	return &rpc.Answer{Session: rpcSession, Codecs: n.codecs, Sdp: n.answer(session)}, nil
+}
@@ -0,0 +1,115 @@
+package server_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/appcrash/media/server"
+	"github.com/appcrash/media/server/rpc"
+	"github.com/appcrash/media/server/sdp"
+	"google.golang.org/grpc/codes"
+	"strings"
+	"testing"
+)
+
+func sdpOffer(port int, formats string, attrs ...string) string {
+	return "v=0\r\n" +
+		"o=- 1 1 IN IP4 127.0.0.1\r\n" +
+		"s=-\r\n" +
+		"c=IN IP4 127.0.0.1\r\n" +
+		"t=0 0\r\n" +
+		fmt.Sprintf("m=audio %v RTP/AVP %v\r\n", port, formats) +
+		strings.Join(attrs, "\r\n") + "\r\n" +
+		"m=video 44100 RTP/AVP 99\r\n" +
+		"a=rtpmap:99 H264/90000\r\n"
+}
+
+func TestSessionOfferAnswer(t *testing.T) {
+	client := startTestServer(t, &server.Config{
+		RtpIp:          "127.0.0.1",
+		PortQuarantine: -1,
+	}).client
+	ctx := context.Background()
+
+	_, err := client.PrepareSessionWithOffer(ctx, &rpc.OfferParam{
+		Sdp:       "v=0\r\nc=IN IP4 127.0.0.1\r\nm=audio 44060 RTP/AVP 0 18\r\n",
+		GraphDesc: "[echo]",
+	})
+	expectCode(t, err, codes.InvalidArgument)
+	_, err = client.PrepareSessionWithOffer(ctx, &rpc.OfferParam{
+		Sdp:       "v=0\r\nc=IN IP4 127.0.0.1\r\nm=audio 44060 RTP/AVP 98\r\na=rtpmap:98 /8000\r\n",
+		GraphDesc: "[echo]",
+	})
+	expectCode(t, err, codes.InvalidArgument)
+
+	answer, err := client.PrepareSessionWithOffer(ctx, &rpc.OfferParam{
+		Sdp: sdpOffer(44060, "0 96 8 100 101",
+			"a=rtpmap:96 AMR-WB/16000/1",
+			"a=fmtp:96 mode-set=0,1,2; octet-align=1; max-red=0",
+			"a=rtpmap:100 telephone-event/8000",
+			"a=rtpmap:101 telephone-event/16000",
+			"a=ptime:20",
+			"a=sendonly"),
+		GraphDesc:  "[echo]",
+		InstanceId: "sdp",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	session := answer.Session
+	if session.PeerIp != "127.0.0.1" || session.PeerRtpPort != 44060 {
+		t.Fatalf("invalid peer address: %v", session)
+	}
+	if len(answer.Codecs) != 2 || answer.Codecs[0].PayloadType != rpc.CodecType_AMRWB ||
+		answer.Codecs[0].CodecParam != "mode-set=0,1,2; octet-align=1" ||
+		answer.Codecs[1].PayloadType != rpc.CodecType_TELEPHONE_EVENT_16K || answer.Codecs[1].PayloadNumber != 101 {
+		t.Fatalf("invalid negotiated codecs: %v", answer.Codecs)
+	}
+	d, err := sdp.Parse(answer.Sdp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	audio, video := d.Media[0], d.Media[1]
+	if d.ConnectionOf(audio) != session.LocalIp || audio.Port != int(session.LocalRtpPort) || video.Port != 0 {
+		t.Fatalf("invalid answer: %v", answer.Sdp)
+	}
+	if strings.Join(audio.Formats, " ") != "96 101" || audio.Fmtp(96) != "mode-set=0,1,2; octet-align=1" ||
+		audio.Fmtp(101) != "0-15" || d.DirectionOf(audio) != sdp.DirectionRecvOnly {
+		t.Fatalf("invalid answered audio: %v", answer.Sdp)
+	}
+	if rm, _ := audio.RtpMap(101); rm.Encoding != "telephone-event" || rm.ClockRate != 16000 {
+		t.Fatalf("invalid answered telephone event: %v", answer.Sdp)
+	}
+
+	// re-offer changes peer port and payload numbers
+	answer2, err := client.UpdateSessionWithOffer(ctx, &rpc.ReofferParam{
+		SessionId: session.SessionId,
+		Sdp: sdpOffer(44062, "97 102", "a=rtpmap:97 AMR-WB/16000", "a=fmtp:97 octet-align=1",
+			"a=rtpmap:102 telephone-event/16000", "a=sendonly"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d2, _ := sdp.Parse(answer2.Sdp)
+	if d2.Origin.Version != d.Origin.Version+1 || d2.Origin.SessionId != d.Origin.SessionId {
+		t.Fatalf("answer of re-offer should increase version only: %v", answer2.Sdp)
+	}
+	desc, err := client.DescribeSession(ctx, &rpc.DescribeParam{SessionId: session.SessionId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if desc.Info.PeerRtpPort != 44062 || len(desc.Codecs) != 2 || desc.Codecs[0].PayloadNumber != 97 ||
+		desc.Codecs[1].PayloadNumber != 102 {
+		t.Fatalf("session should be updated by re-offer: %v", desc)
+	}
+	_, err = client.UpdateSessionWithOffer(ctx, &rpc.ReofferParam{
+		SessionId: session.SessionId,
+		Sdp:       sdpOffer(44062, "97 102", "a=rtpmap:97 AMR-WB/16000", "a=rtpmap:102 telephone-event/16000"),
+	})
+	expectCode(t, err, codes.FailedPrecondition)
+	_, err = client.UpdateSessionWithOffer(ctx, &rpc.ReofferParam{
+		SessionId: session.SessionId,
+		Sdp:       sdpOffer(44062, "8"),
+	})
+	expectCode(t, err, codes.FailedPrecondition)
+	client.StopSession(ctx, &rpc.StopParam{SessionId: session.SessionId})
+}
@@ -2,6 +2,8 @@ package server
 
 import (
 	"context"
+	"fmt"
+	"github.com/appcrash/media/server/channel"
 	"github.com/appcrash/media/server/rpc"
 	"github.com/appcrash/media/server/utils"
 	"sync"
@@ -23,18 +25,95 @@ const (
 	nbLoopReporter
 )
 
+// WatchdogAction is what watchdog does when a session is unhealthy
+type WatchdogAction int
+
+const (
+	// WatchdogActionDefault inherits action from server's config
+	WatchdogActionDefault WatchdogAction = iota
+	// WatchdogActionStop stops the unhealthy session
+	WatchdogActionStop
+	// WatchdogActionNotify sends WATCHDOG_ALERT to the instance once for every problem, session keeps running
+	WatchdogActionNotify
+)
+
+// WatchdogPolicy decides when a session is unhealthy and what to do with it. when a policy overrides another one,
+// zero fields are inherited, negative timeout or threshold disables the check.
+type WatchdogPolicy struct {
+	// InstanceTimeout is for instance not reporting SESSION_INFO, or session created but not started
+	InstanceTimeout time.Duration
+	ReceiveTimeout  time.Duration
+	SendTimeout     time.Duration
+	ErrorThreshold  int
+	Action          WatchdogAction
+}
+
+// DefaultWatchdogPolicy is used if not overridden by server config or CreateParam, send side is not checked by
+// default as one-way sessions(i.e. recording) never send
+var DefaultWatchdogPolicy = WatchdogPolicy{
+	InstanceTimeout: SessionTimeoutPeriod,
+	ReceiveTimeout:  SessionTimeoutPeriod,
+	SendTimeout:     -1,
+	ErrorThreshold:  ReportErrorThreshold,
+	Action:          WatchdogActionStop,
+}
+
+// override returns the policy with non-zero fields of p replaced by o's
+func (p WatchdogPolicy) override(o WatchdogPolicy) WatchdogPolicy {
+	if o.InstanceTimeout != 0 {
+		p.InstanceTimeout = o.InstanceTimeout
+	}
+	if o.ReceiveTimeout != 0 {
+		p.ReceiveTimeout = o.ReceiveTimeout
+	}
+	if o.SendTimeout != 0 {
+		p.SendTimeout = o.SendTimeout
+	}
+	if o.ErrorThreshold != 0 {
+		p.ErrorThreshold = o.ErrorThreshold
+	}
+	if o.Action != WatchdogActionDefault {
+		p.Action = o.Action
+	}
+	return p
+}
+
+func watchdogPolicyFromRpc(wp *rpc.WatchdogPolicy) (p WatchdogPolicy) {
+	if wp == nil {
+		return
+	}
+	p.InstanceTimeout = time.Duration(wp.InstanceTimeout) * time.Second
+	p.ReceiveTimeout = time.Duration(wp.ReceiveTimeout) * time.Second
+	p.SendTimeout = time.Duration(wp.SendTimeout) * time.Second
+	p.ErrorThreshold = int(wp.ErrorThreshold)
+	switch wp.Action {
+	case rpc.WatchdogAction_WATCHDOG_STOP:
+		p.Action = WatchdogActionStop
+	case rpc.WatchdogAction_WATCHDOG_NOTIFY:
+		p.Action = WatchdogActionNotify
+	}
+	return
+}
+
+// expired checks whether timestamp is older than timeout, always false if timeout is disabled
+func expired(ts time.Time, timeout time.Duration) bool {
+	return timeout > 0 && !ts.IsZero() && time.Since(ts) > timeout
+}
+
 // WatchDog is used to detect sessions in abnormal state such as zombie session and end it if necessary
 // it detects state by:
 // 1. send/recv loops actively report info or error
 // 2. periodically check signalling server reported session info(if it is capable)
 //
-// if any of above reported timestamp timeout, watchdog will end this session
+// if any of above reported timestamp timeout, watchdog will end this session or notify the instance as its policy says
 type WatchDog struct {
 	session *MediaSession
+	policy  WatchdogPolicy
 
 	mutex                  sync.Mutex
 	started                bool
 	errorLogged            *utils.Set[int]
+	alerted                *utils.Set[string] // problems already notified, cleared once session is healthy
 	createTimestamp        time.Time
 	instanceAliveTimestamp time.Time // last time we recv session info state from instance
 	loopAliveTimestamp     [nbLoopReporter]time.Time
@@ -42,12 +121,14 @@ type WatchDog struct {
 	cancel                 context.CancelFunc
 }
 
-func newWatchDog(s *MediaSession) *WatchDog {
+func newWatchDog(s *MediaSession, policy WatchdogPolicy) *WatchDog {
 	now := time.Now()
 	return &WatchDog{
 		session:         s,
+		policy:          policy,
 		createTimestamp: now,
 		errorLogged:     utils.NewSet[int](),
+		alerted:         utils.NewSet[string](),
 	}
 }
 
@@ -58,9 +139,16 @@ func (wd *WatchDog) start() {
 		return
 	}
 	wd.started = true
+	if auditor := wd.session.server.auditor; auditor != nil {
+		auditor.add(wd)
+		wd.cancel = func() {
+			auditor.remove(wd)
+		}
+		return
+	}
 	ctx, cancel := context.WithCancel(context.Background())
 	wd.cancel = cancel
-	go wd.healthCheck(ctx)
+	go wd.healthCheck(ctx, wd.session.server.auditPeriod)
 }
 
 func (wd *WatchDog) reportLoopInfo(loopId int) {
@@ -77,9 +165,10 @@ func (wd *WatchDog) reportLoopError(loopId int, err error) {
 	}
 
 	wd.nbError++
-	if wd.nbError > ReportErrorThreshold {
-		logger.Errorf("watchdog(%v): stop session due to too many errors", wd.session.GetSessionId())
-		wd.stop()
+	// act only when the threshold is crossed, errors keep coming until session stops and audit checks it anyway
+	if threshold := wd.policy.ErrorThreshold; threshold >= 0 && int(wd.nbError) == threshold+1 {
+		// the reporting loop is waited by session's Stop(), don't block it
+		go wd.act(rpc.StopReason_ERROR_THRESHOLD, "too many errors reported by rtp loops")
 	}
 }
 
@@ -90,58 +179,154 @@ func (wd *WatchDog) reportSessionInfo(_ *rpc.SystemEvent) {
 }
 
 // stop session as well as watchdog itself
-func (wd *WatchDog) stop() {
-	wd.session.Stop()
+func (wd *WatchDog) stop(reason rpc.StopReason) {
+	wd.session.stop(reason)
 	if wd.cancel != nil {
 		wd.cancel()
 	}
 }
 
+// act takes action of policy on the problem, empty problem means session is already stopped
+func (wd *WatchDog) act(reason rpc.StopReason, problem string) {
+	session := wd.session
+	if problem == "" || wd.policy.Action != WatchdogActionNotify {
+		if problem != "" {
+			logger.Errorf("watchdog(%v): %v, stop session", session.sessionId, problem)
+		}
+		wd.stop(reason)
+		return
+	}
+	wd.mutex.Lock()
+	notified := wd.alerted.Contain(problem)
+	wd.alerted.Add(problem)
+	wd.mutex.Unlock()
+	if notified {
+		return
+	}
+	logger.Warnf("watchdog(%v): %v, notify instance(%v)", session.sessionId, problem, session.GetInstanceId())
+	if err := channel.GetSystemChannel().NotifyInstance(&rpc.SystemEvent{
+		Cmd:        rpc.SystemCommand_WATCHDOG_ALERT,
+		InstanceId: session.GetInstanceId(),
+		SessionId:  session.sessionId.String(),
+		Event:      problem,
+	}); err != nil {
+		logger.Errorf("watchdog(%v): failed to notify instance: %v", session.sessionId, err)
+	}
+}
+
 // healthCheck periodically check session's state
-func (wd *WatchDog) healthCheck(ctx context.Context) {
-	ticker := time.NewTicker(SessionAuditPeriod)
+func (wd *WatchDog) healthCheck(ctx context.Context, period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if reason, problem := wd.audit(); reason != rpc.StopReason_NONE {
+				wd.act(reason, problem)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// audit starts a new round check, returns why session should be stopped with a description of the problem, or
+// rpc.StopReason_NONE if it is healthy
+func (wd *WatchDog) audit() (reason rpc.StopReason, problem string) {
 	session := wd.session
+	policy := &wd.policy
+	wd.mutex.Lock()
+	defer wd.mutex.Unlock()
+	defer func() {
+		if reason == rpc.StopReason_NONE {
+			// problems can be notified again once they come back
+			wd.alerted = utils.NewSet[string]()
+		}
+	}()
+	switch session.status {
+	case sessionStatusStarted:
+		if expired(wd.loopAliveTimestamp[receiveLoop], policy.ReceiveTimeout) {
+			return rpc.StopReason_WATCHDOG_TIMEOUT, fmt.Sprintf("no rtp packet received in %v", policy.ReceiveTimeout)
+		}
+		if expired(wd.loopAliveTimestamp[sendLoop], policy.SendTimeout) {
+			return rpc.StopReason_WATCHDOG_TIMEOUT, fmt.Sprintf("no rtp packet sent in %v", policy.SendTimeout)
+		}
+		if threshold := policy.ErrorThreshold; threshold >= 0 && int(wd.nbError) > threshold {
+			return rpc.StopReason_ERROR_THRESHOLD, "too many errors reported by rtp loops"
+		}
+		fallthrough // more checks
+	case sessionStatusCreated:
+		// created session has no running loops, check instance aliveness and if create timestamp too far away
+		if wd.instanceAliveTimestamp.IsZero() {
+			// instance has not reported any info yet, so examine session's creation moment
+			if session.status == sessionStatusCreated && expired(wd.createTimestamp, policy.InstanceTimeout) {
+				return rpc.StopReason_WATCHDOG_TIMEOUT,
+					fmt.Sprintf("session created but not started in %v", policy.InstanceTimeout)
+			}
+		} else if expired(wd.instanceAliveTimestamp, policy.InstanceTimeout) {
+			// the instance is able to report its session info, check whether disconnected
+			return rpc.StopReason_WATCHDOG_TIMEOUT,
+				fmt.Sprintf("no update from instance in %v", policy.InstanceTimeout)
+		}
+	case sessionStatusStopped:
+		// stop watchdog itself, session's reason is kept
+		reason = rpc.StopReason_WATCHDOG_TIMEOUT
+	default:
+		logger.Errorf("session(%v) has unknown state(%v)", session.sessionId, session.status)
+	}
+	return
+}
+
+// watchdogScheduler audits many watchdogs in one goroutine instead of one goroutine per session
+type watchdogScheduler struct {
+	mutex  sync.Mutex
+	dogs   *utils.Set[*WatchDog]
+	cancel context.CancelFunc
+}
+
+func newWatchdogScheduler(period time.Duration) *watchdogScheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	ws := &watchdogScheduler{
+		dogs:   utils.NewSet[*WatchDog](),
+		cancel: cancel,
+	}
+	go ws.run(ctx, period)
+	return ws
+}
+
+func (ws *watchdogScheduler) add(wd *WatchDog) {
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+	ws.dogs.Add(wd)
+}
+
+func (ws *watchdogScheduler) remove(wd *WatchDog) {
+	ws.mutex.Lock()
+	defer ws.mutex.Unlock()
+	ws.dogs.Remove(wd)
+}
+
+func (ws *watchdogScheduler) stop() {
+	ws.cancel()
+}
+
+func (ws *watchdogScheduler) run(ctx context.Context, period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
 	for {
 		select {
 		case <-ticker.C:
-			sessionId := session.sessionId
-			// start a new round audit ...
-			var stopSession bool
-			wd.mutex.Lock()
-			switch session.status {
-			case sessionStatusStarted:
-				// currently only check that is any packet still received
-				// open question: how to use send loop's info to determine zombie session
-				recvTs := wd.loopAliveTimestamp[receiveLoop]
-				if !recvTs.IsZero() && time.Since(recvTs) > SessionTimeoutPeriod {
-					logger.Errorf("session(%v) has not received any packet in timeout period, stop it", sessionId)
-					stopSession = true
+			var dogs []*WatchDog
+			ws.mutex.Lock()
+			ws.dogs.Iterate(func(wd *WatchDog) {
+				dogs = append(dogs, wd)
+			})
+			ws.mutex.Unlock()
+			for _, wd := range dogs {
+				if reason, problem := wd.audit(); reason != rpc.StopReason_NONE {
+					// stopping session may take a while, don't delay others
+					go wd.act(reason, problem)
 				}
-				fallthrough // more checks
-			case sessionStatusCreated:
-				// created session has no running loops, check instance aliveness and if create timestamp too far away
-				if wd.instanceAliveTimestamp.IsZero() {
-					// instance has not reported any info yet, so examine session's creation moment
-					if session.status == sessionStatusCreated && time.Since(wd.createTimestamp) > SessionTimeoutPeriod {
-						logger.Errorf("session(%v) created but not started until timeout, stop it", sessionId)
-						stopSession = true
-					}
-				} else {
-					// the instance is able to report its session info, check whether disconnected
-					if time.Since(wd.instanceAliveTimestamp) > SessionTimeoutPeriod {
-						logger.Errorf("session(%v) has no update from instance since %v, timeout, stop it",
-							wd.instanceAliveTimestamp, sessionId)
-						stopSession = true
-					}
-				}
-			case sessionStatusStopped:
-				stopSession = true
-			default:
-				logger.Errorf("session(%v) has unknown state(%v)", sessionId, session.status)
-			}
-			wd.mutex.Unlock()
-			if stopSession {
-				wd.stop()
 			}
 		case <-ctx.Done():
 			return
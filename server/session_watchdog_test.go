@@ -0,0 +1,108 @@
+package server_test
+
+import (
+	"context"
+	"github.com/appcrash/media/server"
+	"github.com/appcrash/media/server/rpc"
+	"testing"
+	"time"
+)
+
+func TestWatchdogPolicy(t *testing.T) {
+	client := startTestServer(t, &server.Config{
+		RtpIp:               "127.0.0.1",
+		PortQuarantine:      -1,
+		WatchdogAuditPeriod: 100 * time.Millisecond,
+	}).client
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// instance receives alerts from system channel
+	sysStream, err := client.SystemChannel(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sysStream.Send(&rpc.SystemEvent{Cmd: rpc.SystemCommand_REGISTER, InstanceId: "watchdog"})
+	alerts := make(chan *rpc.SystemEvent, 8)
+	go func() {
+		for {
+			evt, err := sysStream.Recv()
+			if err != nil {
+				return
+			}
+			if evt.Cmd == rpc.SystemCommand_WATCHDOG_ALERT {
+				alerts <- evt
+			}
+		}
+	}()
+	watch, err := client.WatchSessions(ctx, &rpc.WatchParam{InstanceId: "watchdog"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stopped := make(chan *rpc.SessionEvent, 8)
+	go func() {
+		for {
+			evt, err := watch.Recv()
+			if err != nil {
+				return
+			}
+			if evt.Type == rpc.SessionEventType_SESSION_STOPPED {
+				stopped <- evt
+			}
+		}
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	prepare := func(policy *rpc.WatchdogPolicy) *rpc.Session {
+		s, err := client.PrepareSession(ctx, &rpc.CreateParam{
+			PeerIp:   "127.0.0.1",
+			PeerPort: 44020,
+			Codecs: []*rpc.CodecInfo{{
+				PayloadNumber: 8,
+				PayloadType:   rpc.CodecType_PCM_ALAW,
+			}},
+			GraphDesc:  "[echo]",
+			InstanceId: "watchdog",
+			Watchdog:   policy,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	// notify only: session keeps running after alert
+	s1 := prepare(&rpc.WatchdogPolicy{InstanceTimeout: 1, Action: rpc.WatchdogAction_WATCHDOG_NOTIFY})
+	select {
+	case evt := <-alerts:
+		if evt.SessionId != s1.SessionId {
+			t.Fatalf("unexpected alert: %v", evt)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("no watchdog alert received")
+	}
+	select {
+	case evt := <-alerts:
+		t.Fatalf("problem should be alerted only once: %v", evt)
+	case <-time.After(300 * time.Millisecond):
+	}
+	if _, err = client.DescribeSession(ctx, &rpc.DescribeParam{SessionId: s1.SessionId}); err != nil {
+		t.Fatalf("session should not be stopped by notify only policy: %v", err)
+	}
+	client.StopSession(ctx, &rpc.StopParam{SessionId: s1.SessionId})
+	<-stopped
+
+	// send side inactivity stops the session
+	s2 := prepare(&rpc.WatchdogPolicy{ReceiveTimeout: -1, SendTimeout: 1})
+	if _, err = client.StartSession(ctx, &rpc.StartParam{SessionId: s2.SessionId}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case evt := <-stopped:
+		if evt.SessionId != s2.SessionId || evt.StopReason != rpc.StopReason_WATCHDOG_TIMEOUT {
+			t.Fatalf("unexpected stop event: %v", evt)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("session should be stopped as nothing sent")
+	}
+}
@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// PacketBuffer is a reference-counted byte buffer backed by size-classed pools, it is shared among packets and
+// messages that fan out to many consumers without copying.
+//
+// The contract:
+// 1. the creator holds the first reference, whoever shares the buffer calls Retain, and every holder calls Release
+// exactly once when done. the buffer is recycled when the last reference is released.
+// 2. a shared buffer is read-only, holders call Writable before writing to it (copy-on-write).
+// 3. releasing is an optimization, a buffer never released is simply reclaimed by GC. but using a buffer after
+// releasing it, or releasing it more than once is a bug.
+type PacketBuffer struct {
+	refs  int32
+	class int // index of size class, -1 if the buffer is not from pool
+	buf   []byte
+}
+
+var packetBufferSizeClass = []int{256, 512, 1024, 2048, 4096, 16384, 65536}
+var packetBufferPool = make([]sync.Pool, len(packetBufferSizeClass))
+
+func init() {
+	for i := range packetBufferPool {
+		class, size := i, packetBufferSizeClass[i]
+		packetBufferPool[i].New = func() interface{} {
+			return &PacketBuffer{class: class, buf: make([]byte, 0, size)}
+		}
+	}
+}
+
+func sizeClassOf(size int) int {
+	for i, s := range packetBufferSizeClass {
+		if size <= s {
+			return i
+		}
+	}
+	return -1
+}
+
+// NewPacketBuffer gets a buffer of length size from pool, its content is undefined
+func NewPacketBuffer(size int) (b *PacketBuffer) {
+	if class := sizeClassOf(size); class >= 0 {
+		b = packetBufferPool[class].Get().(*PacketBuffer)
+		b.buf = b.buf[:size]
+	} else {
+		b = &PacketBuffer{class: -1, buf: make([]byte, size)}
+	}
+	b.refs = 1
+	return
+}
+
+// NewPacketBufferFrom gets a buffer from pool and copies data into it
+func NewPacketBufferFrom(data []byte) *PacketBuffer {
+	b := NewPacketBuffer(len(data))
+	copy(b.buf, data)
+	return b
+}
+
+// WrapPacketBuffer makes data reference-counted without copying, the buffer is never put into pool
+func WrapPacketBuffer(data []byte) *PacketBuffer {
+	return &PacketBuffer{refs: 1, class: -1, buf: data}
+}
+
+func (b *PacketBuffer) Bytes() []byte {
+	return b.buf
+}
+
+func (b *PacketBuffer) Len() int {
+	return len(b.buf)
+}
+
+func (b *PacketBuffer) RefCount() int32 {
+	return atomic.LoadInt32(&b.refs)
+}
+
+// Shared returns true if more than one holder refer to this buffer
+func (b *PacketBuffer) Shared() bool {
+	return atomic.LoadInt32(&b.refs) > 1
+}
+
+func (b *PacketBuffer) Retain() *PacketBuffer {
+	atomic.AddInt32(&b.refs, 1)
+	return b
+}
+
+func (b *PacketBuffer) Release() {
+	refs := atomic.AddInt32(&b.refs, -1)
+	if refs < 0 {
+		panic("packet buffer is released more than retained")
+	}
+	if refs == 0 && b.class >= 0 {
+		b.buf = b.buf[:0]
+		packetBufferPool[b.class].Put(b)
+	}
+}
+
+// Writable returns b itself if the caller is the only holder, otherwise a private copy is returned and the caller's
+// reference to b is released
+func (b *PacketBuffer) Writable() *PacketBuffer {
+	if !b.Shared() {
+		return b
+	}
+	nb := NewPacketBufferFrom(b.buf)
+	b.Release()
+	return nb
+}
@@ -0,0 +1,86 @@
+package utils_test
+
+import (
+	"bytes"
+	"github.com/appcrash/media/server/utils"
+	"testing"
+)
+
+func TestPacketBufferRefCount(t *testing.T) {
+	b := utils.NewPacketBufferFrom([]byte("abc"))
+	if b.Shared() || b.RefCount() != 1 {
+		t.Fatal("new buffer should have exactly one reference")
+	}
+	b.Retain()
+	if !b.Shared() {
+		t.Fatal("retained buffer should be shared")
+	}
+	w := b.Writable()
+	if w == b || !bytes.Equal(w.Bytes(), []byte("abc")) {
+		t.Fatal("writable of shared buffer should be a copy")
+	}
+	if b.Shared() {
+		t.Fatal("writable should release the reference of shared buffer")
+	}
+	if b.Writable() != b {
+		t.Fatal("writable of exclusive buffer should be itself")
+	}
+	b.Release()
+	w.Release()
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("over-release should panic")
+		}
+	}()
+	b.Release()
+}
+
+func TestPacketListCopyOnWrite(t *testing.T) {
+	raw := []byte{0x80, 8, 0, 1, 0, 0, 0, 160, 1, 2, 3, 4, 'p', 'a', 'y'}
+	pl := utils.NewPacketListFromBuffer(utils.NewPacketBufferFrom(raw), 12, len(raw))
+	cloned := pl.Clone()
+	cloned.MakeWritable()
+	cloned.Payload[0] = 'P'
+	if string(pl.Payload) != "pay" || string(cloned.Payload) != "Pay" {
+		t.Fatalf("writing cloned packet should not affect original one: %s %s", pl.Payload, cloned.Payload)
+	}
+	if !bytes.Equal(cloned.RawBuffer[:12], raw[:12]) {
+		t.Fatal("header should be copied along with payload")
+	}
+
+	// the original is exclusively owned now, no copy happens
+	payload := pl.Payload
+	pl.MakeWritable()
+	if &payload[0] != &pl.Payload[0] {
+		t.Fatal("exclusive packet should not be copied")
+	}
+	pl.Release()
+	cloned.Release()
+	if pl.Payload != nil || pl.RawBuffer != nil {
+		t.Fatal("released packet should not refer to buffer")
+	}
+}
+
+const benchPacketSize = 172
+
+// BenchmarkPacketListAlloc is how received packets were wrapped before pooling
+func BenchmarkPacketListAlloc(b *testing.B) {
+	packet := make([]byte, benchPacketSize)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		raw := make([]byte, len(packet))
+		copy(raw, packet)
+		pl := &utils.RtpPacketList{Payload: raw[12:], RawBuffer: raw}
+		_ = pl
+	}
+}
+
+func BenchmarkPacketListPooled(b *testing.B) {
+	packet := make([]byte, benchPacketSize)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		pl := utils.NewPacketListFromBuffer(utils.NewPacketBufferFrom(packet), 12, len(packet))
+		pl.Release()
+	}
+}
@@ -5,6 +5,9 @@ import "github.com/appcrash/GoRTP/rtp"
 // RtpPacketList is either received RTP data packet or generated packets by codecs that can be readily put to
 // stack for transmission. audio data is usually one packet at a time as no pts is required, but video codecs can
 // build multiple packets of the same pts. those packets can be linked and send to rtp stack as a whole.
+//
+// a packet can be backed by a pooled PacketBuffer, pushing a list to a channel hands over its references to the
+// receiver, who calls Release when done. see PacketBuffer for the contract.
 type RtpPacketList struct {
 	Payload     []byte // rtp payload
 	RawBuffer   []byte // rtp payload + rtp header
@@ -15,21 +18,35 @@ type RtpPacketList struct {
 	Ssrc        uint32
 	Csrc        []uint32
 
-	next *RtpPacketList // more RtpPacketList, if any
+	buffer *PacketBuffer  // backs RawBuffer and Payload, if any
+	next   *RtpPacketList // more RtpPacketList, if any
 }
 
+// NewPacketListFromRtpPacket copies packet into a pooled buffer, so the packet can be freed to rtp stack at once
 func NewPacketListFromRtpPacket(packet *rtp.DataPacket) *RtpPacketList {
 	if packet.InUse() <= 0 || packet.Buffer() == nil {
 		return nil
 	}
+	raw := packet.Buffer()[:packet.InUse()]
+	payload := packet.Payload()
+	payloadOffset := cap(raw) - cap(payload)
+	pl := NewPacketListFromBuffer(NewPacketBufferFrom(raw), payloadOffset, payloadOffset+len(payload))
+	pl.PayloadType = packet.PayloadType()
+	pl.Pts = packet.Timestamp()
+	pl.Marker = packet.Marker()
+	pl.Ssrc = packet.Ssrc()
+	pl.Csrc = packet.CsrcList()
+	return pl
+}
+
+// NewPacketListFromBuffer creates a single packet whose RawBuffer is the whole buf and Payload is buf[payloadStart:
+// payloadEnd], the packet takes over the caller's reference to buf
+func NewPacketListFromBuffer(buf *PacketBuffer, payloadStart, payloadEnd int) *RtpPacketList {
+	raw := buf.Bytes()
 	return &RtpPacketList{
-		Payload:     packet.Payload(),
-		RawBuffer:   packet.Buffer()[:packet.InUse()],
-		PayloadType: packet.PayloadType(),
-		Pts:         packet.Timestamp(),
-		Marker:      packet.Marker(),
-		Ssrc:        packet.Ssrc(),
-		Csrc:        packet.CsrcList(),
+		Payload:   raw[payloadStart:payloadEnd],
+		RawBuffer: raw,
+		buffer:    buf,
 	}
 }
 
@@ -41,8 +58,9 @@ func (pl *RtpPacketList) Iterate(f func(p *RtpPacketList)) {
 	}
 }
 
+// CloneSingle shares buffer with the original packet, call MakeWritable before writing to either of them
 func (pl RtpPacketList) CloneSingle() *RtpPacketList {
-	return &RtpPacketList{
+	cloned := &RtpPacketList{
 		Payload:     pl.Payload,
 		RawBuffer:   pl.RawBuffer,
 		PayloadType: pl.PayloadType,
@@ -51,6 +69,10 @@ func (pl RtpPacketList) CloneSingle() *RtpPacketList {
 		Ssrc:        pl.Ssrc,
 		Csrc:        pl.Csrc,
 	}
+	if pl.buffer != nil {
+		cloned.buffer = pl.buffer.Retain()
+	}
+	return cloned
 }
 
 func (pl *RtpPacketList) Clone() *RtpPacketList {
@@ -90,3 +112,43 @@ func (pl *RtpPacketList) Len() (length int) {
 	})
 	return
 }
+
+// Retain adds a reference to buffers of all packets, the list can then be pushed to one more consumer
+func (pl *RtpPacketList) Retain() *RtpPacketList {
+	pl.Iterate(func(p *RtpPacketList) {
+		if p.buffer != nil {
+			p.buffer.Retain()
+		}
+	})
+	return pl
+}
+
+// Release drops the holder's reference to buffers of all packets, the list must not be used any more
+func (pl *RtpPacketList) Release() {
+	pl.Iterate(func(p *RtpPacketList) {
+		if p.buffer != nil {
+			p.buffer.Release()
+			p.buffer = nil
+			p.Payload = nil
+			p.RawBuffer = nil
+		}
+	})
+}
+
+// MakeWritable copies any buffer that is shared with others, then payload of all packets can be modified in place
+func (pl *RtpPacketList) MakeWritable() {
+	pl.Iterate(func(p *RtpPacketList) {
+		if p.buffer == nil || !p.buffer.Shared() {
+			return
+		}
+		raw, payload := p.RawBuffer, p.Payload
+		nb := p.buffer.Writable()
+		newRaw := nb.Bytes()
+		if offset := cap(raw) - cap(payload); len(payload) > 0 && offset >= 0 && offset+len(payload) <= len(raw) &&
+			&raw[offset] == &payload[0] {
+			p.Payload = newRaw[offset : offset+len(payload)]
+		}
+		p.RawBuffer = newRaw
+		p.buffer = nb
+	})
+}
@@ -36,3 +36,9 @@ func (s *Set[T]) GetAndRemove() (v T) {
 func (s *Set[T]) Size() int {
 	return len(s.m)
 }
+
+func (s *Set[T]) Iterate(f func(v T)) {
+	for v := range s.m {
+		f(v)
+	}
+}